@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/simonvc/miniledger/internal/client"
 	"github.com/simonvc/miniledger/internal/ledger"
 	"github.com/spf13/cobra"
 )
@@ -27,7 +26,7 @@ var accountCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new account",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
 		acct := &ledger.Account{
 			ID:       acctCreateID,
@@ -54,7 +53,7 @@ var accountListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List accounts",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
 		accounts, err := c.ListAccounts(context.Background(), acctListCategory, nil)
 		if err != nil {
@@ -85,7 +84,7 @@ var accountGetCmd = &cobra.Command{
 	Short: "Get account details",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
 		acct, err := c.GetAccount(context.Background(), args[0])
 		if err != nil {
@@ -104,20 +103,30 @@ var accountGetCmd = &cobra.Command{
 }
 
 // account balance
+var acctBalanceAsOf string
+
 var accountBalanceCmd = &cobra.Command{
 	Use:   "balance [id]",
 	Short: "Get account balance",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
-		bal, err := c.GetAccountBalance(context.Background(), args[0])
+		asOf, err := parseAsOfFlag(acctBalanceAsOf)
+		if err != nil {
+			return err
+		}
+		bal, err := c.GetAccountBalance(context.Background(), args[0], asOf)
 		if err != nil {
 			return err
 		}
 
 		fmt.Printf("Account: %s\n", bal.AccountID)
 		fmt.Printf("Balance: %s %s (%s minor units)\n", bal.Formatted, bal.Currency, strconv.FormatInt(bal.Balance, 10))
+		if bal.Pending != 0 {
+			fmt.Printf("Pending: %s %s\n", ledger.FormatAmount(bal.Pending, bal.Currency), bal.Currency)
+			fmt.Printf("Available: %s %s\n", bal.AvailableFormatted, bal.Currency)
+		}
 		return nil
 	},
 }
@@ -133,6 +142,8 @@ func init() {
 
 	accountListCmd.Flags().StringVar(&acctListCategory, "category", "", "Filter by category")
 
+	accountBalanceCmd.Flags().StringVar(&acctBalanceAsOf, "as-of", "", "Balance as of this date (YYYY-MM-DD, end of day) or RFC 3339 time")
+
 	accountCmd.AddCommand(accountCreateCmd)
 	accountCmd.AddCommand(accountListCmd)
 	accountCmd.AddCommand(accountGetCmd)
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var apikeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage the API keys the server accepts",
+	Long: "Manage API keys in the ledger database given by --db. Keys work directly on\n" +
+		"the database, so the first admin key can be created before the server is\n" +
+		"started; 'apikey create' creates the ledger if it does not exist yet.\n" +
+		"Roles: reader (read only), poster (post transactions, create accounts, set\n" +
+		"rates) and admin (everything, including settings and account deletion).",
+}
+
+// openKeyStore opens the database at --db for key management. Unless
+// create is set, the ledger must already exist.
+func openKeyStore(create bool) (*store.Store, context.Context, error) {
+	if _, err := os.Stat(flagDB); err != nil && !create {
+		return nil, nil, fmt.Errorf("no ledger at %s (create one with 'miniledger apikey create --db %s'): %w", flagDB, flagDB, err)
+	}
+	st, err := store.Open(flagDB, store.Options{ReportingCurrency: flagReportingCurrency})
+	if err != nil {
+		return nil, nil, fmt.Errorf("open database: %w", err)
+	}
+	return st, store.WithActor(context.Background(), apikeyActor), nil
+}
+
+// apikeyActor is recorded in the audit log as who created or revoked a key.
+var apikeyActor string
+
+// apikey create
+var (
+	apikeyName string
+	apikeyRole string
+)
+
+var apikeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key and print its token",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, ctx, err := openKeyStore(true)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		key, token, err := st.CreateAPIKey(ctx, apikeyName, ledger.Role(apikeyRole))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s key %s (%s)\n\n", key.Role, key.ID, key.Name)
+		fmt.Printf("  %s\n\n", token)
+		fmt.Printf("The token is not stored and cannot be shown again. Pass it with --token or $%s.\n", tokenEnv)
+		return nil
+	},
+}
+
+var apikeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, ctx, err := openKeyStore(false)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		keys, err := st.ListAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			fmt.Println("No API keys.")
+			return nil
+		}
+		fmt.Printf("%-36s %-20s %-8s %-18s %s\n", "ID", "NAME", "ROLE", "CREATED", "REVOKED")
+		fmt.Printf("%-36s %-20s %-8s %-18s %s\n", "--", "----", "----", "-------", "-------")
+		for _, k := range keys {
+			revoked := "-"
+			if k.RevokedAt != nil {
+				revoked = k.RevokedAt.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%-36s %-20s %-8s %-18s %s\n", k.ID, k.Name, k.Role, k.CreatedAt.Local().Format("2006-01-02 15:04"), revoked)
+		}
+		return nil
+	},
+}
+
+var apikeyRevokeCmd = &cobra.Command{
+	Use:   "revoke [id]",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, ctx, err := openKeyStore(false)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		key, err := st.RevokeAPIKey(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Revoked %s key %s (%s)\n", key.Role, key.ID, key.Name)
+		return nil
+	},
+}
+
+func init() {
+	apikeyCreateCmd.Flags().StringVar(&apikeyName, "name", "", "Key name, recorded as the actor of its changes")
+	apikeyCreateCmd.Flags().StringVar(&apikeyRole, "role", string(ledger.RoleReader), "Role: reader, poster or admin")
+	apikeyCreateCmd.Flags().StringVar(&flagReportingCurrency, "reporting-currency", "", reportingCurrencyUsage)
+	apikeyCreateCmd.MarkFlagRequired("name")
+
+	apikeyCmd.PersistentFlags().StringVar(&apikeyActor, "actor", os.Getenv("USER"), "Name recorded in the audit log for the key changes you make")
+
+	apikeyCmd.AddCommand(apikeyCreateCmd)
+	apikeyCmd.AddCommand(apikeyListCmd)
+	apikeyCmd.AddCommand(apikeyRevokeCmd)
+	rootCmd.AddCommand(apikeyCmd)
+}
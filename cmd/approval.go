@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+var approvalCmd = &cobra.Command{
+	Use:   "approval",
+	Short: "Review transactions awaiting four-eyes approval",
+	Long: "Transactions with an entry above its account code's APPROVAL_THRESHOLD are\n" +
+		"parked unfinalized until a user other than the one who posted them approves\n" +
+		"or rejects them. Approving and rejecting need an admin key.",
+}
+
+// approval list
+var approvalStatus string
+
+var approvalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the approval queue",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		approvals, err := c.ListApprovals(context.Background(), approvalStatus)
+		if err != nil {
+			return err
+		}
+
+		if len(approvals) == 0 {
+			fmt.Println("No approvals found.")
+			return nil
+		}
+
+		fmt.Printf("%-38s %-16s %-12s %-8s %s\n", "TRANSACTION", "REQUESTED", "BY", "STATUS", "REASON")
+		fmt.Printf("%-38s %-16s %-12s %-8s %s\n", "-----------", "---------", "--", "------", "------")
+		for _, a := range approvals {
+			fmt.Printf("%-38s %-16s %-12s %-8s %s\n",
+				a.TransactionID,
+				a.RequestedAt.Local().Format("2006-01-02 15:04"),
+				a.RequestedBy,
+				a.Status,
+				a.Reason,
+			)
+			if a.Transaction != nil {
+				fmt.Printf("  %s\n", a.Transaction.Description)
+			}
+			if a.DecidedBy != "" {
+				decided := fmt.Sprintf("  %s by %s", a.Status, a.DecidedBy)
+				if a.Comment != "" {
+					decided += ": " + a.Comment
+				}
+				fmt.Println(decided)
+			}
+		}
+		return nil
+	},
+}
+
+// approval approve / reject
+var approvalComment string
+
+var approvalApproveCmd = &cobra.Command{
+	Use:   "approve [transaction-id]",
+	Short: "Approve a parked transaction and post it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		a, err := c.ApproveTransaction(context.Background(), args[0], approvalComment)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction %s approved by %s\n", a.TransactionID, a.DecidedBy)
+		if a.Transaction != nil {
+			if a.Transaction.Status == ledger.StatusPending && a.Transaction.ExpiresAt != nil {
+				fmt.Printf("Pending until %s\n", a.Transaction.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+			} else if a.Transaction.Finalized {
+				fmt.Printf("Posted at %s\n", a.Transaction.PostedAt.Format("2006-01-02 15:04:05"))
+			}
+		}
+		return nil
+	},
+}
+
+var approvalRejectCmd = &cobra.Command{
+	Use:   "reject [transaction-id]",
+	Short: "Reject a parked transaction so it is never posted",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		a, err := c.RejectTransaction(context.Background(), args[0], approvalComment)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction %s rejected by %s\n", a.TransactionID, a.DecidedBy)
+		return nil
+	},
+}
+
+func init() {
+	approvalListCmd.Flags().StringVar(&approvalStatus, "status", "", "Filter by status: pending (default), approved, rejected or all")
+
+	approvalApproveCmd.Flags().StringVar(&approvalComment, "comment", "", "Note recorded with the decision")
+	approvalRejectCmd.Flags().StringVar(&approvalComment, "comment", "", "Why the transaction is rejected")
+
+	approvalCmd.AddCommand(approvalListCmd)
+	approvalCmd.AddCommand(approvalApproveCmd)
+	approvalCmd.AddCommand(approvalRejectCmd)
+
+	rootCmd.AddCommand(approvalCmd)
+}
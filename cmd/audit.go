@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/simonvc/miniledger/internal/client"
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+var auditFormat string
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Verify the ledger's double-entry invariants",
+	Long: "Check the ledger's data against its invariants: balanced transactions and\n" +
+		"currencies, orphan and unfinalized entries, entry currencies, account codes,\n" +
+		"system accounts, recomputed balances, installed triggers and schema version.\n" +
+		"Exits non-zero if any check fails.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if auditFormat != "text" && auditFormat != "json" {
+			return fmt.Errorf("unknown format %q (use text or json)", auditFormat)
+		}
+		c := newClient()
+		report, err := c.Audit(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if auditFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			printAuditReport(report)
+		}
+
+		if !report.Passed {
+			return fmt.Errorf("audit failed: %d of %d checks failed", report.Failed(), len(report.Checks))
+		}
+		return nil
+	},
+}
+
+var verifyChainFormat string
+
+var verifyChainCmd = &cobra.Command{
+	Use:   "verify-chain",
+	Short: "Recompute the tamper-evident transaction hash chain",
+	Long: "Recompute the SHA-256 hash chain over every finalized transaction and report\n" +
+		"the first broken link. Exits non-zero if the chain does not verify.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyChainFormat != "text" && verifyChainFormat != "json" {
+			return fmt.Errorf("unknown format %q (use text or json)", verifyChainFormat)
+		}
+		c := newClient()
+		v, err := c.VerifyChain(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if verifyChainFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("Verified %d link(s), head %s\n", v.Length, v.Head)
+			if b := v.Break; b != nil {
+				fmt.Printf("\nBroken at link %d, transaction %s:\n  %s\n", b.Seq, b.TransactionID, b.Reason)
+				if b.Expected != "" {
+					fmt.Printf("  expected %s\n  stored   %s\n", b.Expected, b.Stored)
+				}
+			}
+		}
+
+		if !v.Valid {
+			return fmt.Errorf("hash chain is broken at transaction %s", v.Break.TransactionID)
+		}
+		return nil
+	},
+}
+
+// audit log
+var (
+	auditLogActor     string
+	auditLogOperation string
+	auditLogTarget    string
+	auditLogFrom      string
+	auditLogTo        string
+	auditLogLimit     int
+	auditLogCursor    string
+	auditLogAll       bool
+	auditLogFormat    string
+)
+
+var auditLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show who changed accounts, settings, currencies, periods and rates",
+	Long: "List the audit log newest first, one page at a time: who made each change\n" +
+		"to an account, CoA setting, currency, period or FX rate, when, and what\n" +
+		"changed. --operation takes an operation such as setting.upsert or a family\n" +
+		"such as setting. Pass the printed cursor back with --cursor, or use --all.",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if auditLogFormat != "text" && auditLogFormat != "json" {
+			return fmt.Errorf("unknown format %q (use text or json)", auditLogFormat)
+		}
+		c := newClient()
+
+		from, to, err := parseDateRange(auditLogFrom, auditLogTo)
+		if err != nil {
+			return err
+		}
+		q := client.AuditLogQuery{
+			Actor:     auditLogActor,
+			Operation: auditLogOperation,
+			Target:    auditLogTarget,
+			From:      from,
+			To:        to,
+			Limit:     auditLogLimit,
+			Cursor:    auditLogCursor,
+		}
+
+		var entries []ledger.AuditLogEntry
+		var next string
+		for {
+			page, err := c.ListAuditLog(context.Background(), q)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, page.Entries...)
+			next = page.NextCursor
+			if !auditLogAll || next == "" {
+				break
+			}
+			q.Cursor = next
+		}
+
+		if auditLogFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(ledger.AuditLogPage{Entries: entries, NextCursor: next})
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No audit log entries found.")
+			return nil
+		}
+
+		fmt.Printf("%-20s %-12s %-17s %-24s %s\n", "TIME", "ACTOR", "OPERATION", "TARGET", "CHANGE")
+		fmt.Printf("%-20s %-12s %-17s %-24s %s\n", "----", "-----", "---------", "------", "------")
+		for _, e := range entries {
+			fmt.Printf("%-20s %-12s %-17s %-24s %s\n",
+				e.At.Local().Format("2006-01-02 15:04:05"),
+				e.Actor,
+				e.Operation,
+				e.Target,
+				auditChange(e),
+			)
+		}
+		if next != "" {
+			fmt.Printf("\nMore results: --cursor %s\n", next)
+		}
+		return nil
+	},
+}
+
+// auditChange summarises an audit log entry on one line: the fields of the
+// record created or deleted, or the fields updated.
+func auditChange(e ledger.AuditLogEntry) string {
+	var parts []string
+	for _, ch := range e.Changes() {
+		switch {
+		case len(e.Before) == 0:
+			parts = append(parts, ch.Field+"="+ch.After)
+		case len(e.After) == 0:
+			parts = append(parts, ch.Field+"="+ch.Before)
+		default:
+			parts = append(parts, fmt.Sprintf("%s: %s -> %s", ch.Field, ch.Before, ch.After))
+		}
+	}
+	s := strings.Join(parts, ", ")
+	switch {
+	case len(e.Before) == 0:
+		s = "created " + s
+	case len(e.After) == 0:
+		s = "deleted " + s
+	case s == "":
+		s = "(no change)"
+	}
+	if len(s) > 70 {
+		s = s[:68] + ".."
+	}
+	return s
+}
+
+func printAuditReport(r *ledger.AuditReport) {
+	fmt.Printf("Schema version %d, generated %s\n\n", r.SchemaVersion, r.GeneratedAt.Format("2006-01-02 15:04:05"))
+	for _, c := range r.Checks {
+		status := "PASS"
+		if !c.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("  %-4s  %-20s %s\n", status, c.Name, c.Description)
+		for _, p := range c.Problems {
+			fmt.Printf("          - %s\n", p)
+		}
+		if more := c.Count - len(c.Problems); more > 0 {
+			fmt.Printf("          ... and %d more\n", more)
+		}
+	}
+	fmt.Println()
+	if r.Passed {
+		fmt.Printf("All %d checks passed.\n", len(r.Checks))
+	} else {
+		fmt.Printf("%d of %d checks failed.\n", r.Failed(), len(r.Checks))
+	}
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditFormat, "format", "text", "Output format: text or json")
+	rootCmd.AddCommand(auditCmd)
+
+	auditLogCmd.Flags().StringVar(&auditLogActor, "by", "", "Filter by actor")
+	auditLogCmd.Flags().StringVar(&auditLogOperation, "operation", "", "Filter by operation or family (e.g. setting)")
+	auditLogCmd.Flags().StringVar(&auditLogTarget, "target", "", "Filter by target (account ID, code/setting, currency, period ID or currency@time)")
+	auditLogCmd.Flags().StringVar(&auditLogFrom, "from", "", "Changed on or after (YYYY-MM-DD or RFC 3339)")
+	auditLogCmd.Flags().StringVar(&auditLogTo, "to", "", "Changed on or before (YYYY-MM-DD or RFC 3339)")
+	auditLogCmd.Flags().IntVar(&auditLogLimit, "limit", 50, "Page size (max 500)")
+	auditLogCmd.Flags().StringVar(&auditLogCursor, "cursor", "", "Continue from a previous page's cursor")
+	auditLogCmd.Flags().BoolVar(&auditLogAll, "all", false, "Fetch every page")
+	auditLogCmd.Flags().StringVar(&auditLogFormat, "format", "text", "Output format: text or json")
+	auditCmd.AddCommand(auditLogCmd)
+
+	verifyChainCmd.Flags().StringVar(&verifyChainFormat, "format", "text", "Output format: text or json")
+	rootCmd.AddCommand(verifyChainCmd)
+}
@@ -4,19 +4,25 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/simonvc/miniledger/internal/client"
 	"github.com/simonvc/miniledger/internal/ledger"
 	"github.com/spf13/cobra"
 )
 
+var balanceAsOf string
+
 var balanceCmd = &cobra.Command{
 	Use:   "balance",
 	Short: "Show balance sheet",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
-		bs, err := c.BalanceSheet(context.Background())
+		asOf, err := parseAsOfFlag(balanceAsOf)
+		if err != nil {
+			return err
+		}
+		bs, err := c.BalanceSheet(context.Background(), asOf)
 		if err != nil {
 			return err
 		}
@@ -30,9 +36,13 @@ var trialBalanceCmd = &cobra.Command{
 	Use:   "trial",
 	Short: "Show trial balance",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
-		tb, err := c.TrialBalance(context.Background())
+		asOf, err := parseAsOfFlag(balanceAsOf)
+		if err != nil {
+			return err
+		}
+		tb, err := c.TrialBalance(context.Background(), asOf)
 		if err != nil {
 			return err
 		}
@@ -42,30 +52,65 @@ var trialBalanceCmd = &cobra.Command{
 	},
 }
 
+var (
+	consolidatedEntities string
+	consolidatedCurrency string
+)
+
+var consolidatedBalanceCmd = &cobra.Command{
+	Use:   "consolidated",
+	Short: "Show the consolidated balance sheet of several entities",
+	Long: "Combine the balance sheets of --entities (default: all) into one, eliminating\n" +
+		"each nostro <B:ccy> held by entity A against the vostro >A:ccy< held by entity B.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		asOf, err := parseAsOfFlag(balanceAsOf)
+		if err != nil {
+			return err
+		}
+		var entities []string
+		for _, e := range strings.Split(consolidatedEntities, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				entities = append(entities, e)
+			}
+		}
+		cbs, err := c.ConsolidatedBalanceSheet(context.Background(), entities, consolidatedCurrency, asOf)
+		if err != nil {
+			return err
+		}
+
+		printConsolidatedBalanceSheet(cbs)
+		return nil
+	},
+}
+
 func printBalanceSheet(bs *ledger.BalanceSheet) {
 	w := 60
 	fmt.Println()
 	fmt.Println(center("BALANCE SHEET", w))
 	fmt.Println(center(strings.Repeat("=", 20), w))
+	printAsOf(bs.AsOf, w)
 	fmt.Println()
 
+	rc := bs.ReportingCurrency
 	printSection("ASSETS", bs.Assets, w)
 	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
-	fmt.Printf("%-*s%15s\n", w-15, "Total Assets", formatSigned(bs.TotalAssets, "USD"))
+	fmt.Printf("%-*s%15s\n", w-15, "Total Assets ("+rc+")", formatSigned(bs.TotalAssets, rc))
 	fmt.Println()
 
 	printSection("LIABILITIES", bs.Liabilities, w)
 	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
-	fmt.Printf("%-*s%15s\n", w-15, "Total Liabilities", formatSigned(bs.TotalLiabilities, "USD"))
+	fmt.Printf("%-*s%15s\n", w-15, "Total Liabilities ("+rc+")", formatSigned(bs.TotalLiabilities, rc))
 	fmt.Println()
 
 	printSection("EQUITY", bs.Equity, w)
 	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
-	fmt.Printf("%-*s%15s\n", w-15, "Total Equity", formatSigned(bs.TotalEquity, "USD"))
+	fmt.Printf("%-*s%15s\n", w-15, "Total Equity ("+rc+")", formatSigned(bs.TotalEquity, rc))
 	fmt.Println()
 
 	fmt.Printf("%*s%s\n", w-15, "", "═════════════")
-	fmt.Printf("%-*s%15s\n", w-15, "Total L + E", formatSigned(bs.TotalLiabilities+bs.TotalEquity, "USD"))
+	fmt.Printf("%-*s%15s\n", w-15, "Total L + E ("+rc+")", formatSigned(bs.TotalLiabilities+bs.TotalEquity, rc))
 
 	if bs.Balanced {
 		fmt.Println("\n  [BALANCED]")
@@ -86,11 +131,73 @@ func printSection(title string, lines []ledger.BalanceSheetLine, w int) {
 	}
 }
 
+func printConsolidatedBalanceSheet(cbs *ledger.ConsolidatedBalanceSheet) {
+	w := 70
+	fmt.Println()
+	fmt.Println(center("CONSOLIDATED BALANCE SHEET", w))
+	fmt.Println(center(strings.Repeat("=", 26), w))
+	fmt.Println(center(strings.Join(cbs.Entities, ", "), w))
+	printAsOf(cbs.AsOf, w)
+	fmt.Println()
+
+	rc := cbs.ReportingCurrency
+	printEntitySection("ASSETS", cbs.Assets, w)
+	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
+	fmt.Printf("%-*s%15s\n", w-15, "Total Assets ("+rc+")", formatSigned(cbs.TotalAssets, rc))
+	fmt.Println()
+
+	printEntitySection("LIABILITIES", cbs.Liabilities, w)
+	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
+	fmt.Printf("%-*s%15s\n", w-15, "Total Liabilities ("+rc+")", formatSigned(cbs.TotalLiabilities, rc))
+	fmt.Println()
+
+	printEntitySection("EQUITY", cbs.Equity, w)
+	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
+	fmt.Printf("%-*s%15s\n", w-15, "Total Equity ("+rc+")", formatSigned(cbs.TotalEquity, rc))
+	fmt.Println()
+
+	fmt.Printf("%*s%s\n", w-15, "", "═════════════")
+	fmt.Printf("%-*s%15s\n", w-15, "Total L + E ("+rc+")", formatSigned(cbs.TotalLiabilities+cbs.TotalEquity, rc))
+
+	if len(cbs.Eliminations) > 0 {
+		fmt.Println()
+		fmt.Println("  INTERCOMPANY ELIMINATIONS")
+		fmt.Printf("  %s\n", strings.Repeat("─", w-4))
+		for _, e := range cbs.Eliminations {
+			fmt.Printf("  %-10s %-16s%15s\n", e.NostroEntity, e.NostroAccount, formatSigned(e.NostroBalance, e.Currency))
+			fmt.Printf("  %-10s %-16s%15s", e.VostroEntity, e.VostroAccount, formatSigned(e.VostroBalance, e.Currency))
+			if e.Difference != 0 {
+				fmt.Printf("   difference %s", formatSigned(e.Difference, e.Currency))
+			}
+			fmt.Println()
+		}
+	}
+
+	if cbs.Balanced {
+		fmt.Println("\n  [BALANCED]")
+	} else {
+		fmt.Println("\n  [UNBALANCED!]")
+	}
+}
+
+func printEntitySection(title string, lines []ledger.BalanceSheetLine, w int) {
+	fmt.Printf("  %s\n", title)
+	fmt.Printf("  %s\n", strings.Repeat("─", w-4))
+	for _, l := range lines {
+		name := l.AccountName
+		if len(name) > 26 {
+			name = name[:24] + ".."
+		}
+		fmt.Printf("  %-10s %-6s %-*s%15s\n", l.Entity, l.AccountID, w-30, name, formatSigned(l.Balance, l.Currency))
+	}
+}
+
 func printTrialBalance(tb *ledger.TrialBalance) {
 	w := 70
 	fmt.Println()
 	fmt.Println(center("TRIAL BALANCE", w))
 	fmt.Println(center(strings.Repeat("=", 20), w))
+	printAsOf(tb.AsOf, w)
 	fmt.Println()
 
 	fmt.Printf("  %-8s %-30s %15s %15s\n", "ID", "NAME", "DEBIT", "CREDIT")
@@ -113,9 +220,9 @@ func printTrialBalance(tb *ledger.TrialBalance) {
 	}
 
 	fmt.Printf("  %s\n", strings.Repeat("─", w-4))
-	fmt.Printf("  %-39s %15s %15s\n", "TOTALS",
-		ledger.FormatAmount(tb.TotalDebit, "USD"),
-		ledger.FormatAmount(tb.TotalCredit, "USD"))
+	fmt.Printf("  %-39s %15s %15s\n", "TOTALS ("+tb.ReportingCurrency+")",
+		ledger.FormatAmount(tb.TotalDebit, tb.ReportingCurrency),
+		ledger.FormatAmount(tb.TotalCredit, tb.ReportingCurrency))
 
 	if tb.Balanced {
 		fmt.Println("\n  [BALANCED]")
@@ -124,6 +231,24 @@ func printTrialBalance(tb *ledger.TrialBalance) {
 	}
 }
 
+// parseAsOfFlag parses an --as-of value; empty means the current state.
+func parseAsOfFlag(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	asOf, err := ledger.ParseAsOf(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--as-of: %w", err)
+	}
+	return asOf, nil
+}
+
+func printAsOf(asOf *time.Time, w int) {
+	if asOf != nil {
+		fmt.Println(center("As of "+asOf.Format("2006-01-02 15:04:05"), w))
+	}
+}
+
 func center(s string, w int) string {
 	if len(s) >= w {
 		return s
@@ -140,6 +265,12 @@ func formatSigned(amount int64, currency string) string {
 }
 
 func init() {
+	balanceCmd.PersistentFlags().StringVar(&balanceAsOf, "as-of", "", "Report the position as of this date (YYYY-MM-DD, end of day) or RFC 3339 time")
+
+	consolidatedBalanceCmd.Flags().StringVar(&consolidatedEntities, "entities", "", "Comma-separated entities to consolidate (default: all)")
+	consolidatedBalanceCmd.Flags().StringVar(&consolidatedCurrency, "currency", "", "Currency to consolidate into (default: the entities' shared reporting currency)")
+
 	balanceCmd.AddCommand(trialBalanceCmd)
+	balanceCmd.AddCommand(consolidatedBalanceCmd)
 	rootCmd.AddCommand(balanceCmd)
 }
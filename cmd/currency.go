@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var currencyCmd = &cobra.Command{
+	Use:   "currency",
+	Short: "Manage the ledger's currencies",
+}
+
+// currency list
+var currencyListAll bool
+
+var currencyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List enabled currencies",
+	Long:  "List the currencies accepted for new accounts and rates.\nWith --all, list every ISO 4217 currency in the registry.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+		ctx := context.Background()
+
+		info, err := c.LedgerInfo(ctx)
+		if err != nil {
+			return err
+		}
+		defs, err := c.ListCurrencies(ctx, !currencyListAll)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Reporting currency: %s\n\n", info.ReportingCurrency)
+		fmt.Printf("%-5s %-3s %-8s %s\n", "CODE", "EXP", "ENABLED", "NAME")
+		fmt.Printf("%-5s %-3s %-8s %s\n", "----", "---", "-------", "----")
+		for _, d := range defs {
+			enabled := "no"
+			if d.Enabled {
+				enabled = "yes"
+			}
+			fmt.Printf("%-5s %-3d %-8s %s\n", d.Code, d.Exponent, enabled, d.Name)
+		}
+		return nil
+	},
+}
+
+var currencyEnableCmd = &cobra.Command{
+	Use:   "enable [code]",
+	Short: "Enable a currency for new accounts",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCurrencyEnabled(args[0], true)
+	},
+}
+
+var currencyDisableCmd = &cobra.Command{
+	Use:   "disable [code]",
+	Short: "Disable a currency; existing accounts are kept",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setCurrencyEnabled(args[0], false)
+	},
+}
+
+func setCurrencyEnabled(code string, enabled bool) error {
+	c := newClient()
+
+	d, err := c.SetCurrencyEnabled(context.Background(), strings.ToUpper(code), enabled)
+	if err != nil {
+		return err
+	}
+	state := "disabled"
+	if d.Enabled {
+		state = "enabled"
+	}
+	fmt.Printf("%s (%s, %d decimals) %s\n", d.Code, d.Name, d.Exponent, state)
+	return nil
+}
+
+func init() {
+	currencyListCmd.Flags().BoolVar(&currencyListAll, "all", false, "Include disabled currencies")
+
+	currencyCmd.AddCommand(currencyListCmd)
+	currencyCmd.AddCommand(currencyEnableCmd)
+	currencyCmd.AddCommand(currencyDisableCmd)
+
+	rootCmd.AddCommand(currencyCmd)
+}
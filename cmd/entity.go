@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var entityCmd = &cobra.Command{
+	Use:   "entity",
+	Short: "Manage the entities served alongside the default ledger",
+	Long: "Each entity is a separate ledger with its own accounts and reporting currency.\n" +
+		"Select one for other commands with --entity <name>.",
+}
+
+// entity create
+var entityCreateReportingCurrency string
+
+var entityCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an entity",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+		e, err := c.CreateEntity(context.Background(), args[0], entityCreateReportingCurrency)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created entity %s (reporting currency %s)\n", e.Name, e.ReportingCurrency)
+		return nil
+	},
+}
+
+var entityListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List entities",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+		entities, err := c.ListEntities(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(entities) == 0 {
+			fmt.Println("No entities.")
+			return nil
+		}
+		fmt.Printf("%-32s %s\n", "NAME", "REPORTING")
+		fmt.Printf("%-32s %s\n", "----", "---------")
+		for _, e := range entities {
+			fmt.Printf("%-32s %s\n", e.Name, e.ReportingCurrency)
+		}
+		return nil
+	},
+}
+
+func init() {
+	entityCreateCmd.Flags().StringVar(&entityCreateReportingCurrency, "reporting-currency", "", "Reporting currency (ISO 4217) of the new entity (default GEL)")
+
+	entityCmd.AddCommand(entityCreateCmd)
+	entityCmd.AddCommand(entityListCmd)
+	rootCmd.AddCommand(entityCmd)
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+var fxCmd = &cobra.Command{
+	Use:   "fx",
+	Short: "Manage foreign exchange",
+}
+
+var fxRateCmd = &cobra.Command{
+	Use:   "rate",
+	Short: "Manage FX rates to the reporting currency",
+}
+
+// fx rate set
+var fxRateEffective string
+
+var fxRateSetCmd = &cobra.Command{
+	Use:   "set [currency] [rate]",
+	Short: "Set the mid-rate of a currency in the reporting currency",
+	Long:  "Record the rate of one unit of currency in the ledger's reporting currency,\neffective from --effective (YYYY-MM-DD or RFC 3339, default now) until the\nnext rate.",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rate, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid rate %q", args[1])
+		}
+		var effectiveAt time.Time
+		if fxRateEffective != "" {
+			if effectiveAt, err = ledger.ParseTime(fxRateEffective); err != nil {
+				return fmt.Errorf("--effective: %w", err)
+			}
+		}
+
+		c := newClient()
+		ctx := context.Background()
+		r, err := c.SetFXRate(ctx, strings.ToUpper(args[0]), rate, effectiveAt)
+		if err != nil {
+			return err
+		}
+		info, err := c.LedgerInfo(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("1 %s = %.4f %s from %s\n", r.Currency, r.Rate, info.ReportingCurrency, r.EffectiveAt.Format(time.RFC3339))
+		return nil
+	},
+}
+
+// fx rate list
+var (
+	fxRateCurrency string
+	fxRateAsOf     string
+)
+
+var fxRateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List FX rate history",
+	Long:  "List every recorded rate, newest first. With --as-of, list only the rate\neffective for each currency at that date.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+		ctx := context.Background()
+
+		if fxRateAsOf != "" {
+			asOf, err := parseAsOfFlag(fxRateAsOf)
+			if err != nil {
+				return err
+			}
+			table, err := c.EffectiveFXRates(ctx, asOf)
+			if err != nil {
+				return err
+			}
+			info, err := c.LedgerInfo(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%-6s %12s\n", "CCY", "RATE")
+			fmt.Printf("%-6s %12s\n", "---", "----")
+			for _, ccy := range slices.Sorted(maps.Keys(table)) {
+				rate := table[ccy]
+				if ccy == info.ReportingCurrency {
+					continue
+				}
+				if fxRateCurrency != "" && ccy != strings.ToUpper(fxRateCurrency) {
+					continue
+				}
+				fmt.Printf("%-6s %12.4f\n", ccy, rate)
+			}
+			return nil
+		}
+
+		rates, err := c.ListFXRates(ctx, strings.ToUpper(fxRateCurrency))
+		if err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			fmt.Println("No FX rates found.")
+			return nil
+		}
+
+		fmt.Printf("%-6s %12s  %s\n", "CCY", "RATE", "EFFECTIVE")
+		fmt.Printf("%-6s %12s  %s\n", "---", "----", "---------")
+		for _, r := range rates {
+			fmt.Printf("%-6s %12.4f  %s\n", r.Currency, r.Rate, r.EffectiveAt.Format(time.RFC3339))
+		}
+		return nil
+	},
+}
+
+// fx rate import
+var fxRateImportCmd = &cobra.Command{
+	Use:   "import [file.csv]",
+	Short: "Import historical FX rates from CSV",
+	Long:  "Import rates from a CSV file with columns currency,rate,effective_at\n(a header row is optional). Use - to read from stdin. The import is\nall-or-nothing.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		rates, err := readRatesCSV(r)
+		if err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			return fmt.Errorf("no rates in %s", args[0])
+		}
+
+		c := newClient()
+		n, err := c.ImportFXRates(context.Background(), rates)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d FX rates\n", n)
+		return nil
+	},
+}
+
+// readRatesCSV parses currency,rate,effective_at records, skipping a
+// leading header row.
+func readRatesCSV(r io.Reader) ([]ledger.FXRate, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+
+	var rates []ledger.FXRate
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(rec[0], "currency") {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rec[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, rec[1])
+		}
+		effectiveAt, err := ledger.ParseTime(strings.TrimSpace(rec[2]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, ledger.FXRate{
+			Currency:    strings.ToUpper(strings.TrimSpace(rec[0])),
+			Rate:        rate,
+			EffectiveAt: effectiveAt,
+		})
+	}
+}
+
+// fx revalue
+var fxRevalueAsOf string
+
+var fxRevalueCmd = &cobra.Command{
+	Use:   "revalue",
+	Short: "Revalue foreign-currency balances and book unrealised FX gains and losses",
+	Long:  "Revalue every foreign-currency monetary balance at the rate effective at\n--as-of and post the difference in the reporting currency to ~fxgain or\n~fxloss against ~fxreval. Revaluations must be run in date order.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asOf, err := parseAsOfFlag(fxRevalueAsOf)
+		if err != nil {
+			return err
+		}
+
+		c := newClient()
+		rev, err := c.RevalueFX(context.Background(), asOf)
+		if err != nil {
+			return err
+		}
+		printRevaluation(rev)
+		return nil
+	},
+}
+
+// fx revaluations
+var fxRevaluationsCmd = &cobra.Command{
+	Use:   "revaluations [id]",
+	Short: "List FX revaluation runs, or show one with the rates it used",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+		ctx := context.Background()
+
+		if len(args) == 1 {
+			rev, err := c.GetFXRevaluation(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			printRevaluation(rev)
+			return nil
+		}
+
+		revs, err := c.ListFXRevaluations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(revs) == 0 {
+			fmt.Println("No FX revaluations.")
+			return nil
+		}
+
+		fmt.Printf("%-36s %-20s %14s %14s %-4s  %s\n", "ID", "AS OF", "GAIN", "LOSS", "CCY", "TRANSACTION")
+		fmt.Printf("%-36s %-20s %14s %14s %-4s  %s\n", "--", "-----", "----", "----", "---", "-----------")
+		for _, r := range revs {
+			fmt.Printf("%-36s %-20s %14s %14s %-4s  %s\n",
+				r.ID, r.AsOf.Format(time.RFC3339),
+				ledger.FormatAmount(r.Gain, r.ReportingCurrency),
+				ledger.FormatAmount(r.Loss, r.ReportingCurrency),
+				r.ReportingCurrency, r.TransactionID)
+		}
+		return nil
+	},
+}
+
+func printRevaluation(rev *ledger.FXRevaluation) {
+	rc := rev.ReportingCurrency
+	fmt.Printf("FX revaluation %s as of %s\n\n", rev.ID, rev.AsOf.Format(time.RFC3339))
+	fmt.Printf("%-16s %-4s %16s %10s %16s %16s %14s\n", "ACCOUNT", "CCY", "BALANCE", "RATE", "CARRYING "+rc, "REVALUED "+rc, "ADJUSTMENT")
+	for _, l := range rev.Lines {
+		fmt.Printf("%-16s %-4s %16s %10.4f %16s %16s %14s\n",
+			l.AccountID, l.Currency,
+			ledger.FormatAmount(l.Balance, l.Currency), l.Rate,
+			ledger.FormatAmount(l.CarryingAmount, rc),
+			ledger.FormatAmount(l.RevaluedAmount, rc),
+			ledger.FormatAmount(l.Adjustment, rc))
+	}
+	fmt.Println()
+	fmt.Printf("Gain: %s %s\n", ledger.FormatAmount(rev.Gain, rc), rc)
+	fmt.Printf("Loss: %s %s\n", ledger.FormatAmount(rev.Loss, rc), rc)
+	if rev.TransactionID != "" {
+		fmt.Printf("Posted as transaction %s\n", rev.TransactionID)
+	} else {
+		fmt.Println("No adjustment to post.")
+	}
+}
+
+func init() {
+	fxRateSetCmd.Flags().StringVar(&fxRateEffective, "effective", "", "Effective from (YYYY-MM-DD or RFC 3339, default now)")
+
+	fxRateListCmd.Flags().StringVar(&fxRateCurrency, "currency", "", "Only this currency")
+	fxRateListCmd.Flags().StringVar(&fxRateAsOf, "as-of", "", "Show the rates effective at this date (YYYY-MM-DD, end of day) or RFC 3339 time")
+
+	fxRateCmd.AddCommand(fxRateSetCmd)
+	fxRateCmd.AddCommand(fxRateListCmd)
+	fxRateCmd.AddCommand(fxRateImportCmd)
+	fxRevalueCmd.Flags().StringVar(&fxRevalueAsOf, "as-of", "", "Revalue as of this date (YYYY-MM-DD, end of day) or RFC 3339 time")
+	fxRevalueCmd.MarkFlagRequired("as-of")
+
+	fxCmd.AddCommand(fxRateCmd)
+	fxCmd.AddCommand(fxRevalueCmd)
+	fxCmd.AddCommand(fxRevaluationsCmd)
+
+	rootCmd.AddCommand(fxCmd)
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/simonvc/miniledger/internal/importer"
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Bulk import accounts or transactions from CSV or JSONL",
+	Long: "Bulk import accounts or transactions from a .csv or .jsonl file.\n" +
+		"Every record is checked with the same validation as the API first; invalid\n" +
+		"records are reported by line and skipped. Use --dry-run to only check the file.",
+}
+
+var (
+	importFile      string
+	importDryRun    bool
+	importUnits     string
+	importBatchSize int
+)
+
+var importAccountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Import accounts",
+	Long: "Import accounts, one per row or line, creating them in batches of --batch-size.\n" +
+		"CSV needs id, name and code columns and may have currency (default USD) and category.\n" +
+		"JSONL lines are objects with the same fields.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkBatchSize(); err != nil {
+			return err
+		}
+		format, err := importer.FormatOf(importFile)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(importFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rd, err := importer.NewAccountReader(f, format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", importFile, err)
+		}
+
+		c := newClient()
+		ctx := context.Background()
+		var res importResult
+		var batch []*ledger.Account
+		var lines []int
+		create := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			results, err := c.CreateAccounts(ctx, batch)
+			if err != nil {
+				return fmt.Errorf("creating the batch starting on line %d: %w (%d accounts imported before it)", lines[0], err, res.ok)
+			}
+			for i, r := range results {
+				if r.Error != "" {
+					res.fail(lines[i], errors.New(r.Error))
+				} else {
+					res.ok++
+				}
+			}
+			batch, lines = batch[:0], lines[:0]
+			return nil
+		}
+
+		seen := map[string]int{}
+		for {
+			line, acct, err := rd.Next()
+			if err == io.EOF {
+				break
+			}
+			if !res.check(line, err) {
+				return fmt.Errorf("%s: %w", importFile, err)
+			}
+			if err != nil {
+				continue
+			}
+			if err := acct.Validate(); err != nil {
+				res.fail(line, err)
+				continue
+			}
+			if first, ok := seen[acct.ID]; ok {
+				res.fail(line, fmt.Errorf("%w: %s is also on line %d", ledger.ErrDuplicateAccount, acct.ID, first))
+				continue
+			}
+			seen[acct.ID] = line
+			if importDryRun {
+				res.ok++
+				continue
+			}
+
+			batch = append(batch, acct)
+			lines = append(lines, line)
+			if len(batch) == importBatchSize {
+				if err := create(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := create(); err != nil {
+			return err
+		}
+		return res.report("accounts")
+	},
+}
+
+var importTransactionsCmd = &cobra.Command{
+	Use:   "transactions",
+	Short: "Import transactions",
+	Long: "Import transactions, posting them in batches of --batch-size.\n" +
+		"CSV has one entry per row with txn, account_id, amount and currency columns and\n" +
+		"optional description, posted_at and idempotency_key columns; consecutive rows with\n" +
+		"the same txn make up one transaction, described by its first row.\n" +
+		"JSONL lines are POST /transactions bodies.\n" +
+		"Amounts are minor units (1050), or major units (10.50) with --units major.\n" +
+		"Give each transaction an idempotency_key to make re-running an import safe.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		units, err := importer.ParseUnits(importUnits)
+		if err != nil {
+			return err
+		}
+		if err := checkBatchSize(); err != nil {
+			return err
+		}
+		format, err := importer.FormatOf(importFile)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(importFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rd, err := importer.NewTransactionReader(f, format, units)
+		if err != nil {
+			return fmt.Errorf("%s: %w", importFile, err)
+		}
+
+		c := newClient()
+		ctx := context.Background()
+		var res importResult
+		var batch []*ledger.Transaction
+		var lines []int
+		post := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			results, err := c.CreateTransactions(ctx, batch, false)
+			if err != nil {
+				return fmt.Errorf("posting the batch starting on line %d: %w (%d transactions imported before it)", lines[0], err, res.ok)
+			}
+			for i, r := range results {
+				if r.Error != "" {
+					res.fail(lines[i], errors.New(r.Error))
+				} else {
+					res.ok++
+				}
+			}
+			batch, lines = batch[:0], lines[:0]
+			return nil
+		}
+
+		for {
+			line, txn, err := rd.Next()
+			if err == io.EOF {
+				break
+			}
+			if !res.check(line, err) {
+				return fmt.Errorf("%s: %w", importFile, err)
+			}
+			if err != nil {
+				continue
+			}
+			if err := txn.Validate(); err != nil {
+				res.fail(line, err)
+				continue
+			}
+			if importDryRun {
+				res.ok++
+				continue
+			}
+
+			batch = append(batch, txn)
+			lines = append(lines, line)
+			if len(batch) == importBatchSize {
+				if err := post(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := post(); err != nil {
+			return err
+		}
+		return res.report("transactions")
+	},
+}
+
+// checkBatchSize refuses a --batch-size the server would refuse.
+func checkBatchSize() error {
+	if importBatchSize < 1 || importBatchSize > server.MaxBatchSize {
+		return fmt.Errorf("--batch-size must be between 1 and %d", server.MaxBatchSize)
+	}
+	return nil
+}
+
+// importResult tallies the records of an import and reports failed ones
+// as file:line.
+type importResult struct {
+	ok, failed int
+}
+
+// check reports err if it is a *importer.LineError and says whether the
+// import can go on.
+func (r *importResult) check(line int, err error) bool {
+	var lerr *importer.LineError
+	if errors.As(err, &lerr) {
+		r.fail(lerr.Line, lerr.Err)
+		return true
+	}
+	return err == nil
+}
+
+func (r *importResult) fail(line int, err error) {
+	r.failed++
+	fmt.Fprintf(os.Stderr, "%s:%d: %v\n", importFile, line, err)
+}
+
+func (r *importResult) report(what string) error {
+	if importDryRun {
+		fmt.Printf("Checked %d %s: %d valid, %d invalid\n", r.ok+r.failed, what, r.ok, r.failed)
+	} else {
+		fmt.Printf("Imported %d %s, %d failed\n", r.ok, what, r.failed)
+	}
+	if r.failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", r.failed, r.ok+r.failed, what)
+	}
+	return nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{importAccountsCmd, importTransactionsCmd} {
+		c.Flags().StringVar(&importFile, "file", "", "File to import (.csv or .jsonl)")
+		c.Flags().BoolVar(&importDryRun, "dry-run", false, "Only validate the file; post nothing")
+		c.Flags().IntVar(&importBatchSize, "batch-size", 500, fmt.Sprintf("Records sent per request (at most %d)", server.MaxBatchSize))
+		c.MarkFlagRequired("file")
+	}
+	importTransactionsCmd.Flags().StringVar(&importUnits, "units", "minor", "How amounts are written: minor (1050) or major (10.50)")
+
+	importCmd.AddCommand(importAccountsCmd, importTransactionsCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+var periodCmd = &cobra.Command{
+	Use:   "period",
+	Short: "Manage accounting periods",
+}
+
+// period open
+var (
+	periodStart string
+	periodEnd   string
+)
+
+var periodOpenCmd = &cobra.Command{
+	Use:   "open [id]",
+	Short: "Open an accounting period",
+	Long:  "Create a new period with --start and --end (end is exclusive).\nWithout dates, reopen a period that is currently closing.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+		ctx := context.Background()
+
+		if periodStart == "" && periodEnd == "" {
+			p, err := c.ReopenPeriod(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Period %s reopened\n", p.ID)
+			return nil
+		}
+		if periodStart == "" || periodEnd == "" {
+			return fmt.Errorf("--start and --end must be given together")
+		}
+
+		start, err := ledger.ParseTime(periodStart)
+		if err != nil {
+			return fmt.Errorf("--start: %w", err)
+		}
+		end, err := ledger.ParseTime(periodEnd)
+		if err != nil {
+			return fmt.Errorf("--end: %w", err)
+		}
+
+		p, err := c.CreatePeriod(ctx, args[0], start, end)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Period opened: %s (%s to %s)\n", p.ID, p.StartsAt.Format("2006-01-02"), p.EndsAt.Format("2006-01-02"))
+		return nil
+	},
+}
+
+// period close
+var periodSoft bool
+
+var periodCloseCmd = &cobra.Command{
+	Use:   "close [id]",
+	Short: "Close an accounting period",
+	Long:  "Lock a period so no further transactions can be posted into it.\nWith --soft the period moves to closing, which still accepts adjustments and can be reopened.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		p, err := c.ClosePeriod(context.Background(), args[0], periodSoft)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Period %s is now %s\n", p.ID, p.Status)
+		return nil
+	},
+}
+
+// period list
+var periodListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List accounting periods",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		periods, err := c.ListPeriods(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if len(periods) == 0 {
+			fmt.Println("No periods found.")
+			return nil
+		}
+
+		fmt.Printf("%-12s %-12s %-12s %-8s %s\n", "ID", "START", "END", "STATUS", "CLOSED")
+		fmt.Printf("%-12s %-12s %-12s %-8s %s\n", "----", "-----", "---", "------", "------")
+		for _, p := range periods {
+			closed := ""
+			if p.ClosedAt != nil {
+				closed = p.ClosedAt.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("%-12s %-12s %-12s %-8s %s\n",
+				p.ID,
+				p.StartsAt.Format("2006-01-02"),
+				p.EndsAt.Format("2006-01-02"),
+				p.Status,
+				closed,
+			)
+		}
+		return nil
+	},
+}
+
+// period close-year
+var periodCloseYearCmd = &cobra.Command{
+	Use:   "close-year [year]",
+	Short: "Post year-end closing entries into retained earnings",
+	Long:  "Sweep every revenue (4xxx) and expense (5xxx) balance at year end into\n3010 Retained Earnings, posting one closing transaction per currency.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid year %q", args[0])
+		}
+		c := newClient()
+
+		closings, err := c.CloseYear(context.Background(), year)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Year %d closed:\n", year)
+		for _, cl := range closings {
+			fmt.Printf("  %s %s\n", cl.Currency, cl.TransactionID)
+		}
+		return nil
+	},
+}
+
+// period closings
+var periodClosingsCmd = &cobra.Command{
+	Use:   "closings",
+	Short: "List year-end closings",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		closings, err := c.ListYearClosings(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if len(closings) == 0 {
+			fmt.Println("No years closed.")
+			return nil
+		}
+
+		fmt.Printf("%-6s %-8s %-38s %s\n", "YEAR", "CCY", "TRANSACTION", "CLOSED")
+		fmt.Printf("%-6s %-8s %-38s %s\n", "----", "---", "-----------", "------")
+		for _, cl := range closings {
+			fmt.Printf("%-6d %-8s %-38s %s\n", cl.Year, cl.Currency, cl.TransactionID, cl.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	periodOpenCmd.Flags().StringVar(&periodStart, "start", "", "Period start date (YYYY-MM-DD)")
+	periodOpenCmd.Flags().StringVar(&periodEnd, "end", "", "Period end date, exclusive (YYYY-MM-DD)")
+
+	periodCloseCmd.Flags().BoolVar(&periodSoft, "soft", false, "Soft close: move to closing instead of locking")
+
+	periodCmd.AddCommand(periodOpenCmd)
+	periodCmd.AddCommand(periodCloseCmd)
+	periodCmd.AddCommand(periodListCmd)
+	periodCmd.AddCommand(periodCloseYearCmd)
+	periodCmd.AddCommand(periodClosingsCmd)
+
+	rootCmd.AddCommand(periodCmd)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Financial reports",
+}
+
+// report pnl
+var (
+	reportFrom string
+	reportTo   string
+)
+
+var reportPnLCmd = &cobra.Command{
+	Use:     "pnl",
+	Aliases: []string{"income-statement"},
+	Short:   "Show the income statement (profit and loss)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := parseDateRange(reportFrom, reportTo)
+		if err != nil {
+			return err
+		}
+		c := newClient()
+
+		is, err := c.IncomeStatement(context.Background(), from, to)
+		if err != nil {
+			return err
+		}
+
+		printIncomeStatement(is)
+		return nil
+	},
+}
+
+// report cashflow
+var reportCashFlowCmd = &cobra.Command{
+	Use:     "cashflow",
+	Aliases: []string{"cash-flow"},
+	Short:   "Show the cash flow statement for the 1010/1060 cash accounts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := parseDateRange(reportFrom, reportTo)
+		if err != nil {
+			return err
+		}
+		c := newClient()
+
+		cf, err := c.CashFlowStatement(context.Background(), from, to)
+		if err != nil {
+			return err
+		}
+
+		printCashFlowStatement(cf)
+		return nil
+	},
+}
+
+// parseDateRange parses --from (start of day) and --to (end of day) values.
+// Empty values leave the bound open.
+func parseDateRange(fromFlag, toFlag string) (from, to time.Time, err error) {
+	if fromFlag != "" {
+		if from, err = ledger.ParseTime(fromFlag); err != nil {
+			return from, to, fmt.Errorf("--from: %w", err)
+		}
+	}
+	if toFlag != "" {
+		if to, err = ledger.ParseAsOf(toFlag); err != nil {
+			return from, to, fmt.Errorf("--to: %w", err)
+		}
+	}
+	return from, to, nil
+}
+
+func printIncomeStatement(is *ledger.IncomeStatement) {
+	w := 60
+	fmt.Println()
+	fmt.Println(center("INCOME STATEMENT", w))
+	fmt.Println(center(strings.Repeat("=", 20), w))
+	fmt.Println(center(reportRangeLabel(is.From, is.To), w))
+	fmt.Println()
+
+	rc := is.ReportingCurrency
+	printPnLSection("REVENUE", is.Revenue, rc, w)
+	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
+	fmt.Printf("%-*s%15s\n", w-15, "Total Revenue ("+rc+")", formatSigned(is.TotalRevenue, rc))
+	fmt.Println()
+
+	printPnLSection("EXPENSES", is.Expenses, rc, w)
+	fmt.Printf("%*s%s\n", w-15, "", "─────────────")
+	fmt.Printf("%-*s%15s\n", w-15, "Total Expenses ("+rc+")", formatSigned(is.TotalExpenses, rc))
+	fmt.Println()
+
+	fmt.Printf("%*s%s\n", w-15, "", "═════════════")
+	fmt.Printf("%-*s%15s\n", w-15, "Net Income ("+rc+")", formatSigned(is.NetIncome, rc))
+}
+
+func printPnLSection(title string, groups []ledger.IncomeStatementGroup, rc string, w int) {
+	fmt.Printf("  %s\n", title)
+	fmt.Printf("  %s\n", strings.Repeat("─", w-4))
+	for _, g := range groups {
+		fmt.Printf("  %d %s\n", g.Code, g.Name)
+		for _, l := range g.Lines {
+			name := l.AccountName
+			if len(name) > 26 {
+				name = name[:24] + ".."
+			}
+			fmt.Printf("    %-8s %-*s%15s %s\n", l.AccountID, w-32, name, formatSigned(l.Amount, l.Currency), l.Currency)
+		}
+		ccys := make([]string, 0, len(g.Subtotals))
+		for ccy := range g.Subtotals {
+			ccys = append(ccys, ccy)
+		}
+		sort.Strings(ccys)
+		if len(ccys) > 1 {
+			for _, ccy := range ccys {
+				fmt.Printf("    %-*s%15s %s\n", w-23, "Subtotal", formatSigned(g.Subtotals[ccy], ccy), ccy)
+			}
+		}
+		fmt.Printf("    %-*s%15s %s\n", w-23, fmt.Sprintf("Total %d", g.Code), formatSigned(g.Total, rc), rc)
+	}
+}
+
+func printCashFlowStatement(cf *ledger.CashFlowStatement) {
+	w := 60
+	fmt.Println()
+	fmt.Println(center("CASH FLOW STATEMENT", w))
+	fmt.Println(center(strings.Repeat("=", 20), w))
+	fmt.Println(center(reportRangeLabel(cf.From, cf.To), w))
+	fmt.Println()
+
+	rc := cf.ReportingCurrency
+	for _, sec := range []ledger.CashFlowSection{cf.Operating, cf.Investing, cf.Financing} {
+		fmt.Printf("  %s ACTIVITIES\n", strings.ToUpper(string(sec.Activity)))
+		fmt.Printf("  %s\n", strings.Repeat("─", w-4))
+		for _, l := range sec.Lines {
+			fmt.Printf("    %-*s%15s %s\n", w-23, fmt.Sprintf("%d %s", l.Code, l.Name), formatSigned(l.Amount, l.Currency), l.Currency)
+		}
+		fmt.Printf("%*s%s\n", w-15, "", "─────────────")
+		fmt.Printf("%-*s%15s\n", w-15, "Net "+string(sec.Activity)+" ("+rc+")", formatSigned(sec.Total, rc))
+		fmt.Println()
+	}
+
+	ccys := map[string]bool{}
+	for _, m := range []map[string]int64{cf.Opening, cf.Closing, cf.NetChange} {
+		for ccy := range m {
+			ccys[ccy] = true
+		}
+	}
+	sorted := make([]string, 0, len(ccys))
+	for ccy := range ccys {
+		sorted = append(sorted, ccy)
+	}
+	sort.Strings(sorted)
+
+	fmt.Printf("  %-8s %15s %15s %15s\n", "CCY", "OPENING", "NET CHANGE", "CLOSING")
+	for _, ccy := range sorted {
+		fmt.Printf("  %-8s %15s %15s %15s\n", ccy,
+			formatSigned(cf.Opening[ccy], ccy), formatSigned(cf.NetChange[ccy], ccy), formatSigned(cf.Closing[ccy], ccy))
+	}
+	fmt.Println()
+	fmt.Printf("%-*s%15s\n", w-15, "Net change in cash ("+rc+")", formatSigned(cf.NetChangeTotal, rc))
+}
+
+func reportRangeLabel(from, to *time.Time) string {
+	switch {
+	case from != nil && to != nil:
+		return from.Format("2006-01-02") + " to " + to.Format("2006-01-02")
+	case from != nil:
+		return "From " + from.Format("2006-01-02")
+	case to != nil:
+		return "To " + to.Format("2006-01-02")
+	default:
+		return "All time"
+	}
+}
+
+func init() {
+	reportCmd.PersistentFlags().StringVar(&reportFrom, "from", "", "Start date (YYYY-MM-DD or RFC 3339), inclusive")
+	reportCmd.PersistentFlags().StringVar(&reportTo, "to", "", "End date (YYYY-MM-DD, end of day, or RFC 3339), inclusive")
+
+	reportCmd.AddCommand(reportPnLCmd)
+	reportCmd.AddCommand(reportCashFlowCmd)
+
+	rootCmd.AddCommand(reportCmd)
+}
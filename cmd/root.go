@@ -1,14 +1,56 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+
+	"github.com/simonvc/miniledger/internal/client"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagServer string
-	flagDB     string
+	flagServer            string
+	flagDB                string
+	flagEntity            string
+	flagToken             string
+	flagReportingCurrency string
+	flagEntitiesDir       string
 )
 
+// reportingCurrencyUsage describes the --reporting-currency flag of the
+// commands that open the database.
+const reportingCurrencyUsage = "Reporting currency (ISO 4217) when creating a new ledger (default GEL)"
+
+// entitiesDirUsage describes the --entities-dir flag of the commands that
+// open the database.
+const entitiesDirUsage = "Directory holding the entity databases (default: entities/ next to --db)"
+
+// tokenEnv is the environment variable the API token is read from when
+// --token is not given.
+const tokenEnv = "MINILEDGER_TOKEN"
+
+// newClient returns a client for --server, scoped to --entity if set,
+// authenticating with --token.
+func newClient() *client.Client {
+	return client.New(flagServer).ForEntity(flagEntity).WithToken(apiToken())
+}
+
+// apiToken returns --token, or $MINILEDGER_TOKEN without it.
+func apiToken() string {
+	if flagToken != "" {
+		return flagToken
+	}
+	return os.Getenv(tokenEnv)
+}
+
+// entitiesDir returns the directory the served entities live in.
+func entitiesDir() string {
+	if flagEntitiesDir != "" {
+		return flagEntitiesDir
+	}
+	return filepath.Join(filepath.Dir(flagDB), "entities")
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "miniledger",
 	Short: "Double-entry accounting ledger with IFRS chart of accounts",
@@ -18,6 +60,8 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.PersistentFlags().StringVar(&flagServer, "server", "http://localhost:8888", "Server address")
 	rootCmd.PersistentFlags().StringVar(&flagDB, "db", "ledger.db", "SQLite database path")
+	rootCmd.PersistentFlags().StringVar(&flagEntity, "entity", "", "Entity to operate on (default: the server's default ledger)")
+	rootCmd.PersistentFlags().StringVar(&flagToken, "token", "", "API token (default $"+tokenEnv+")")
 }
 
 func Execute() error {
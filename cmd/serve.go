@@ -1,29 +1,92 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
 	"github.com/simonvc/miniledger/internal/server"
 	"github.com/simonvc/miniledger/internal/store"
 	"github.com/spf13/cobra"
 )
 
-var serveAddr string
+var (
+	serveAddr        string
+	serveNoAuth      bool
+	serveBackupDir   string
+	serveBackupEvery time.Duration
+	serveBackupKeep  int
+)
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Start the HTTP server",
+	Long: "Start the HTTP server. Every request must present an API key created with\n" +
+		"'miniledger apikey create', unless --no-auth is given. Without --no-auth, the\n" +
+		"server refuses to start until the ledger has an API key.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		st, err := store.Open(flagDB)
+		if serveBackupKeep < 0 {
+			return fmt.Errorf("--backup-keep must not be negative")
+		}
+		st, err := store.Open(flagDB, store.Options{ReportingCurrency: flagReportingCurrency})
 		if err != nil {
 			return err
 		}
 		defer st.Close()
+		if !serveNoAuth && !hasActiveKey(st) {
+			return fmt.Errorf("%s has no API keys, so every request would be refused. Create an admin key with\n\n"+
+				"  miniledger apikey create --db %s --name admin --role admin\n\n"+
+				"or serve without authentication with --no-auth", flagDB, flagDB)
+		}
+
+		entities := store.NewRegistry(entitiesDir())
+		defer entities.Close()
 
-		srv := server.New(st, serveAddr)
+		srv := server.New(st, entities, serveAddr)
+		srv.EnableBackups(backupDir(), serveBackupKeep, serveBackupEvery)
+		if serveBackupEvery > 0 {
+			log.Printf("backing up every %s into %s, keeping %d", serveBackupEvery, backupDir(), serveBackupKeep)
+		}
+		if serveNoAuth {
+			log.Printf("authentication disabled: the API is open to anyone who can reach %s", serveAddr)
+		} else {
+			srv.RequireAuth()
+		}
 		return srv.ListenAndServe()
 	},
 }
 
+// backupDir returns the directory the server writes backups into.
+func backupDir() string {
+	if serveBackupDir != "" {
+		return serveBackupDir
+	}
+	return filepath.Join(filepath.Dir(flagDB), "backups")
+}
+
+// hasActiveKey reports whether st has an unrevoked API key.
+func hasActiveKey(st *store.Store) bool {
+	keys, err := st.ListAPIKeys(context.Background())
+	if err != nil {
+		return false
+	}
+	for _, k := range keys {
+		if k.RevokedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8888", "Listen address")
+	serveCmd.Flags().BoolVar(&serveNoAuth, "no-auth", false, "Serve without API keys (anyone who can reach the server has full access)")
+	serveCmd.Flags().StringVar(&flagReportingCurrency, "reporting-currency", "", reportingCurrencyUsage)
+	serveCmd.Flags().StringVar(&flagEntitiesDir, "entities-dir", "", entitiesDirUsage)
+	serveCmd.Flags().StringVar(&serveBackupDir, "backup-dir", "", "Directory backups are written to (default: backups/ next to --db)")
+	serveCmd.Flags().DurationVar(&serveBackupEvery, "backup-every", 0, "Back up every ledger this often, e.g. 6h (default: only on request)")
+	serveCmd.Flags().IntVar(&serveBackupKeep, "backup-keep", 7, "Backups of each ledger to keep; 0 keeps them all")
 	rootCmd.AddCommand(serveCmd)
 }
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/pdf"
+	"github.com/spf13/cobra"
+)
+
+// account statement
+var (
+	stmtFrom   string
+	stmtTo     string
+	stmtFormat string
+	stmtOutput string
+)
+
+var accountStatementCmd = &cobra.Command{
+	Use:   "statement [id]",
+	Short: "Account statement with running balance",
+	Long:  "Print the account's entries for a date range with opening, running and closing balances.\nFormats: text (default), csv, pdf. PDF output requires --output.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := parseDateRange(stmtFrom, stmtTo)
+		if err != nil {
+			return err
+		}
+		if stmtFormat == "pdf" && stmtOutput == "" {
+			return fmt.Errorf("pdf output requires --output")
+		}
+
+		c := newClient()
+		st, err := c.AccountStatement(context.Background(), args[0], from, to)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if stmtOutput != "" {
+			f, err := os.Create(stmtOutput)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		switch stmtFormat {
+		case "text":
+			for _, line := range statementText(st) {
+				fmt.Fprintln(w, line)
+			}
+		case "csv":
+			err = writeStatementCSV(w, st)
+		case "pdf":
+			err = pdf.WriteText(w, statementText(st))
+		default:
+			return fmt.Errorf("unknown format %q (use text, csv or pdf)", stmtFormat)
+		}
+		if err != nil {
+			return err
+		}
+		if stmtOutput != "" {
+			fmt.Printf("Statement written to %s\n", stmtOutput)
+		}
+		return nil
+	},
+}
+
+// statementText renders st as plain ASCII lines, shared by text and PDF output.
+func statementText(st *ledger.AccountStatement) []string {
+	var lines []string
+	add := func(format string, a ...any) { lines = append(lines, fmt.Sprintf(format, a...)) }
+	row := "%-10s  %-32s %-22s %14s %14s %16s  %s"
+
+	add("ACCOUNT STATEMENT")
+	add("Account:   %s  %s (%s)", st.AccountID, st.AccountName, st.Currency)
+	add("Period:    %s", reportRangeLabel(st.From, st.To))
+	add("Generated: %s", st.GeneratedAt.Format("2006-01-02 15:04:05 MST"))
+	add("")
+	add(row, "DATE", "DESCRIPTION", "COUNTERPARTY", "DEBIT", "CREDIT", "BALANCE", "CCY")
+	add(row, "----", "-----------", "------------", "-----", "------", "-------", "---")
+
+	for _, ccy := range statementCurrencies(st) {
+		add(row, "", "Opening balance", "", "", "", formatSigned(st.Opening[ccy], ccy), ccy)
+	}
+	for _, l := range st.Lines {
+		debit, credit := "", ""
+		if l.Amount >= 0 {
+			debit = ledger.FormatAmount(l.Amount, l.Currency)
+		} else {
+			credit = ledger.FormatAmount(-l.Amount, l.Currency)
+		}
+		add(row, l.PostedAt.Format("2006-01-02"), truncate(l.Description, 32), truncate(strings.Join(l.Counterparties, ","), 22),
+			debit, credit, formatSigned(l.Balance, l.Currency), l.Currency)
+	}
+	if len(st.Lines) == 0 {
+		add("%-10s  %s", "", "(no entries in period)")
+	}
+	for _, ccy := range statementCurrencies(st) {
+		add(row, "", "Closing balance", "", "", "", formatSigned(st.Closing[ccy], ccy), ccy)
+	}
+	return lines
+}
+
+func writeStatementCSV(w io.Writer, st *ledger.AccountStatement) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "transaction_id", "description", "counterparties", "amount", "currency", "balance"})
+	for _, ccy := range statementCurrencies(st) {
+		cw.Write([]string{"", "", "Opening balance", "", "", ccy, strconv.FormatInt(st.Opening[ccy], 10)})
+	}
+	for _, l := range st.Lines {
+		cw.Write([]string{
+			l.PostedAt.Format("2006-01-02T15:04:05Z07:00"),
+			l.TransactionID,
+			l.Description,
+			strings.Join(l.Counterparties, ";"),
+			strconv.FormatInt(l.Amount, 10),
+			l.Currency,
+			strconv.FormatInt(l.Balance, 10),
+		})
+	}
+	for _, ccy := range statementCurrencies(st) {
+		cw.Write([]string{"", "", "Closing balance", "", "", ccy, strconv.FormatInt(st.Closing[ccy], 10)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// statementCurrencies lists the currencies with an opening or closing
+// balance, falling back to the account currency for an empty statement.
+func statementCurrencies(st *ledger.AccountStatement) []string {
+	seen := map[string]bool{}
+	for ccy := range st.Opening {
+		seen[ccy] = true
+	}
+	for ccy := range st.Closing {
+		seen[ccy] = true
+	}
+	if len(seen) == 0 && st.Currency != "*" {
+		seen[st.Currency] = true
+	}
+	ccys := make([]string, 0, len(seen))
+	for ccy := range seen {
+		ccys = append(ccys, ccy)
+	}
+	sort.Strings(ccys)
+	return ccys
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n-2] + ".."
+	}
+	return s
+}
+
+func init() {
+	accountStatementCmd.Flags().StringVar(&stmtFrom, "from", "", "Start date (YYYY-MM-DD or RFC 3339), inclusive")
+	accountStatementCmd.Flags().StringVar(&stmtTo, "to", "", "End date (YYYY-MM-DD, end of day, or RFC 3339), inclusive")
+	accountStatementCmd.Flags().StringVar(&stmtFormat, "format", "text", "Output format: text, csv or pdf")
+	accountStatementCmd.Flags().StringVarP(&stmtOutput, "output", "o", "", "Write to file instead of stdout")
+
+	accountCmd.AddCommand(accountStatementCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/simonvc/miniledger/internal/client"
 	"github.com/simonvc/miniledger/internal/ledger"
@@ -19,8 +20,12 @@ var transactionCmd = &cobra.Command{
 
 // transaction create
 var (
-	txnDescription string
-	txnEntries     []string // format: "account_id:amount:currency"
+	txnDescription    string
+	txnEntries        []string // format: "account_id:amount:currency"
+	txnIdempotencyKey string
+	txnPostedAt       string
+	txnPending        bool
+	txnExpiresIn      time.Duration
 )
 
 var transactionCreateCmd = &cobra.Command{
@@ -28,10 +33,25 @@ var transactionCreateCmd = &cobra.Command{
 	Short: "Create a new transaction",
 	Long:  `Create a transaction with double-entry bookkeeping entries.\nEach --entry is formatted as "account_id:amount:currency" (e.g. "1010:+5000:USD")`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
 		txn := &ledger.Transaction{
-			Description: txnDescription,
+			Description:    txnDescription,
+			IdempotencyKey: txnIdempotencyKey,
+		}
+		if txnPostedAt != "" {
+			postedAt, err := ledger.ParseTime(txnPostedAt)
+			if err != nil {
+				return fmt.Errorf("--posted-at: %w", err)
+			}
+			txn.PostedAt = postedAt
+		}
+		if txnPending {
+			txn.Status = ledger.StatusPending
+			if txnExpiresIn > 0 {
+				expiresAt := time.Now().Add(txnExpiresIn)
+				txn.ExpiresAt = &expiresAt
+			}
 		}
 
 		for _, e := range txnEntries {
@@ -57,6 +77,11 @@ var transactionCreateCmd = &cobra.Command{
 
 		fmt.Printf("Transaction created: %s\n", created.ID)
 		fmt.Printf("Description: %s\n", created.Description)
+		if created.ApprovalStatus == ledger.ApprovalPending {
+			fmt.Printf("Awaiting approval (see 'miniledger approval list')\n")
+		} else if created.Status == ledger.StatusPending && created.ExpiresAt != nil {
+			fmt.Printf("Pending until %s\n", created.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+		}
 		fmt.Printf("Entries:\n")
 		for _, entry := range created.Entries {
 			direction := "DR"
@@ -72,18 +97,63 @@ var transactionCreateCmd = &cobra.Command{
 }
 
 // transaction list
-var txnListAccountID string
+var (
+	txnListAccountID string
+	txnListFrom      string
+	txnListTo        string
+	txnListSearch    string
+	txnListCurrency  string
+	txnListCode      int
+	txnListMinAmount int64
+	txnListMaxAmount int64
+	txnListLimit     int
+	txnListCursor    string
+	txnListAll       bool
+	txnListPending   bool
+)
 
 var transactionListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List transactions",
+	Long: "List transactions newest first, one page at a time.\n" +
+		"Pass the printed cursor back with --cursor for the next page, or use --all.\n" +
+		"Amounts are absolute minor units and, like --account, --currency and --code,\n" +
+		"must match a single entry of the transaction.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
-		txns, err := c.ListTransactions(context.Background(), txnListAccountID)
+		from, to, err := parseDateRange(txnListFrom, txnListTo)
 		if err != nil {
 			return err
 		}
+		q := client.TxnQuery{
+			AccountID:   txnListAccountID,
+			From:        from,
+			To:          to,
+			Description: txnListSearch,
+			Currency:    txnListCurrency,
+			Code:        txnListCode,
+			MinAmount:   txnListMinAmount,
+			MaxAmount:   txnListMaxAmount,
+			Limit:       txnListLimit,
+			Cursor:      txnListCursor,
+			Pending:     txnListPending,
+		}
+
+		var txns []ledger.Transaction
+		var next string
+		for {
+			page, err := c.ListTransactions(context.Background(), q)
+			if err != nil {
+				return err
+			}
+			txns = append(txns, page.Transactions...)
+			next = page.NextCursor
+			if !txnListAll || next == "" {
+				break
+			}
+			q.Cursor = next
+		}
 
 		if len(txns) == 0 {
 			fmt.Println("No transactions found.")
@@ -104,6 +174,9 @@ var transactionListCmd = &cobra.Command{
 				desc,
 			)
 		}
+		if next != "" {
+			fmt.Printf("\nMore results: --cursor %s\n", next)
+		}
 		return nil
 	},
 }
@@ -114,7 +187,7 @@ var transactionGetCmd = &cobra.Command{
 	Short: "Get transaction details",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c := client.New(flagServer)
+		c := newClient()
 
 		txn, err := c.GetTransaction(context.Background(), args[0])
 		if err != nil {
@@ -125,6 +198,24 @@ var transactionGetCmd = &cobra.Command{
 		fmt.Printf("Description: %s\n", txn.Description)
 		fmt.Printf("Posted:      %s\n", txn.PostedAt.Format("2006-01-02 15:04:05"))
 		fmt.Printf("Finalized:   %v\n", txn.Finalized)
+		if txn.Status != "" {
+			fmt.Printf("Status:      %s\n", txn.Status)
+		}
+		if txn.ApprovalStatus != "" {
+			fmt.Printf("Approval:    %s\n", txn.ApprovalStatus)
+		}
+		if txn.Status == ledger.StatusPending && txn.ExpiresAt != nil {
+			fmt.Printf("Expires:     %s\n", txn.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		if txn.ReversalOf != "" {
+			fmt.Printf("Reverses:    %s\n", txn.ReversalOf)
+		}
+		if txn.ReversedBy != "" {
+			fmt.Printf("Reversed by: %s\n", txn.ReversedBy)
+		}
+		if txn.Hash != "" {
+			fmt.Printf("Hash:        %s\n", txn.Hash)
+		}
 		fmt.Printf("Entries:\n")
 		fmt.Printf("  %-4s %-12s %12s %s\n", "TYPE", "ACCOUNT", "AMOUNT", "CURRENCY")
 		for _, entry := range txn.Entries {
@@ -140,17 +231,105 @@ var transactionGetCmd = &cobra.Command{
 	},
 }
 
+// transaction reverse
+var txnReverseReason string
+
+var transactionReverseCmd = &cobra.Command{
+	Use:   "reverse [id]",
+	Short: "Post a reversing transaction",
+	Long:  "Post a new transaction that negates every entry of the given transaction.\nA transaction can only be reversed once.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		rev, err := c.ReverseTransaction(context.Background(), args[0], txnReverseReason)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Transaction %s reversed by: %s\n", args[0], rev.ID)
+		fmt.Printf("Description: %s\n", rev.Description)
+		if rev.ApprovalStatus == ledger.ApprovalPending {
+			fmt.Printf("Awaiting approval (see 'miniledger approval list')\n")
+		}
+		fmt.Printf("Entries:\n")
+		for _, entry := range rev.Entries {
+			direction := "DR"
+			amt := entry.Amount
+			if amt < 0 {
+				direction = "CR"
+				amt = -amt
+			}
+			fmt.Printf("  %s %-12s %s %s\n", direction, entry.AccountID, ledger.FormatAmount(amt, entry.Currency), entry.Currency)
+		}
+		return nil
+	},
+}
+
+// transaction finalize / void
+var transactionFinalizeCmd = &cobra.Command{
+	Use:   "finalize [id]",
+	Short: "Capture a pending transaction",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		txn, err := c.FinalizeTransaction(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction %s finalized at %s\n", txn.ID, txn.PostedAt.Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+var transactionVoidCmd = &cobra.Command{
+	Use:   "void [id]",
+	Short: "Release a pending transaction without posting it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		txn, err := c.VoidTransaction(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction %s %s\n", txn.ID, txn.Status)
+		return nil
+	},
+}
+
 func init() {
 	transactionCreateCmd.Flags().StringVar(&txnDescription, "description", "", "Transaction description")
 	transactionCreateCmd.Flags().StringSliceVar(&txnEntries, "entry", nil, "Entry in format account_id:amount:currency (can be repeated)")
+	transactionCreateCmd.Flags().StringVar(&txnIdempotencyKey, "idempotency-key", "", "Idempotency key; retrying with the same key returns the original transaction")
+	transactionCreateCmd.Flags().StringVar(&txnPostedAt, "posted-at", "", "Posting date (YYYY-MM-DD or RFC 3339), defaults to now")
+	transactionCreateCmd.Flags().BoolVar(&txnPending, "pending", false, "Place a hold; finalize or void it later")
+	transactionCreateCmd.Flags().DurationVar(&txnExpiresIn, "expires-in", 0, "Hold lifetime, e.g. 72h (default 7 days)")
 	transactionCreateCmd.MarkFlagRequired("description")
 	transactionCreateCmd.MarkFlagRequired("entry")
 
 	transactionListCmd.Flags().StringVar(&txnListAccountID, "account", "", "Filter by account ID")
+	transactionListCmd.Flags().StringVar(&txnListFrom, "from", "", "Posted on or after (YYYY-MM-DD or RFC 3339)")
+	transactionListCmd.Flags().StringVar(&txnListTo, "to", "", "Posted on or before (YYYY-MM-DD or RFC 3339)")
+	transactionListCmd.Flags().StringVar(&txnListSearch, "search", "", "Filter by description text")
+	transactionListCmd.Flags().StringVar(&txnListCurrency, "currency", "", "Filter by entry currency")
+	transactionListCmd.Flags().IntVar(&txnListCode, "code", 0, "Filter by chart of accounts code")
+	transactionListCmd.Flags().Int64Var(&txnListMinAmount, "min-amount", 0, "Minimum entry amount in minor units")
+	transactionListCmd.Flags().Int64Var(&txnListMaxAmount, "max-amount", 0, "Maximum entry amount in minor units")
+	transactionListCmd.Flags().IntVar(&txnListLimit, "limit", 50, "Page size (max 500)")
+	transactionListCmd.Flags().StringVar(&txnListCursor, "cursor", "", "Continue from a previous page's cursor")
+	transactionListCmd.Flags().BoolVar(&txnListAll, "all", false, "Fetch every page")
+	transactionListCmd.Flags().BoolVar(&txnListPending, "pending", false, "List open holds instead of posted transactions")
+
+	transactionReverseCmd.Flags().StringVar(&txnReverseReason, "reason", "", "Reason for the reversal")
 
 	transactionCmd.AddCommand(transactionCreateCmd)
 	transactionCmd.AddCommand(transactionListCmd)
 	transactionCmd.AddCommand(transactionGetCmd)
+	transactionCmd.AddCommand(transactionReverseCmd)
+	transactionCmd.AddCommand(transactionFinalizeCmd)
+	transactionCmd.AddCommand(transactionVoidCmd)
 
 	rootCmd.AddCommand(transactionCmd)
 }
@@ -19,20 +19,24 @@ var tuiCmd = &cobra.Command{
 	Short: "Launch interactive terminal UI",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		serverAddr := flagServer
+		embedded := !cmd.Flags().Changed("server")
 
-		if !cmd.Flags().Changed("server") {
-			st, err := store.Open(flagDB)
+		if embedded {
+			st, err := store.Open(flagDB, store.Options{ReportingCurrency: flagReportingCurrency})
 			if err != nil {
 				return fmt.Errorf("open database: %w", err)
 			}
 			defer st.Close()
 
+			entities := store.NewRegistry(entitiesDir())
+			defer entities.Close()
+
 			ln, err := net.Listen("tcp", "127.0.0.1:0")
 			if err != nil {
 				return fmt.Errorf("listen: %w", err)
 			}
 
-			srv := server.New(st, ln.Addr().String())
+			srv := server.New(st, entities, ln.Addr().String())
 			go func() {
 				if err := srv.Serve(ln); err != nil {
 					log.Printf("embedded server error: %v", err)
@@ -41,8 +45,11 @@ var tuiCmd = &cobra.Command{
 			serverAddr = "http://" + ln.Addr().String()
 		}
 
-		c := client.New(serverAddr)
+		c := client.New(serverAddr).ForEntity(flagEntity).WithToken(apiToken())
 		app := tui.NewApp(c)
+		if embedded {
+			app.EmbeddedServer()
+		}
 		p := tea.NewProgram(app, tea.WithAltScreen())
 		_, err := p.Run()
 		return err
@@ -50,5 +57,7 @@ var tuiCmd = &cobra.Command{
 }
 
 func init() {
+	tuiCmd.Flags().StringVar(&flagReportingCurrency, "reporting-currency", "", reportingCurrencyUsage)
+	tuiCmd.Flags().StringVar(&flagEntitiesDir, "entities-dir", "", entitiesDirUsage)
 	rootCmd.AddCommand(tuiCmd)
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/simonvc/miniledger/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage webhooks that receive posted transactions",
+	Long: "Every transaction posted from now on is POSTed as JSON to each webhook, signed\n" +
+		"with the webhook's secret in the " + server.SignatureHeader + " header, and retried\n" +
+		"with backoff until it is accepted. Managing webhooks needs an admin key.",
+}
+
+// webhook add
+var webhookSecret string
+
+var webhookAddCmd = &cobra.Command{
+	Use:   "add [url]",
+	Short: "Add a webhook and print its signing secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		hook, err := c.CreateWebhook(context.Background(), args[0], webhookSecret)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Added webhook %s -> %s\n\n", hook.ID, hook.URL)
+		fmt.Printf("  %s\n\n", hook.Secret)
+		fmt.Printf("Verify deliveries with the HMAC-SHA256 of the body under this secret. It cannot be shown again.\n")
+		return nil
+	},
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhooks with their delivery counts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		hooks, err := c.ListWebhooks(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(hooks) == 0 {
+			fmt.Println("No webhooks.")
+			return nil
+		}
+		fmt.Printf("%-36s %-18s %9s %9s %7s  %s\n", "ID", "CREATED", "DELIVERED", "PENDING", "FAILED", "URL")
+		fmt.Printf("%-36s %-18s %9s %9s %7s  %s\n", "--", "-------", "---------", "-------", "------", "---")
+		for _, h := range hooks {
+			fmt.Printf("%-36s %-18s %9d %9d %7d  %s\n",
+				h.ID, h.CreatedAt.Local().Format("2006-01-02 15:04"), h.Delivered, h.Pending, h.Failed, h.URL)
+		}
+		return nil
+	},
+}
+
+var webhookRemoveCmd = &cobra.Command{
+	Use:   "remove [id]",
+	Short: "Remove a webhook and drop its undelivered events",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newClient()
+
+		if err := c.DeleteWebhook(context.Background(), args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed webhook %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	webhookAddCmd.Flags().StringVar(&webhookSecret, "secret", "", "Signing secret (default: generated)")
+
+	webhookCmd.AddCommand(webhookAddCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookRemoveCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
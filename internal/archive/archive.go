@@ -0,0 +1,342 @@
+// Package archive writes and reads ledger export archives. An archive is a
+// zip of JSON Lines files (accounts, posted transactions with their entries,
+// FX rates, periods, year-end closings, FX revaluations with their lines,
+// and CoA code settings) described by manifest.json, which records the
+// archive layout and schema versions and each file's row count and SHA-256.
+package archive
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+// Names of the files in an archive, in the order they are written.
+const (
+	ManifestFile       = "manifest.json"
+	AccountsFile       = "accounts.jsonl"
+	TransactionsFile   = "transactions.jsonl"
+	FXRatesFile        = "fx_rates.jsonl"
+	PeriodsFile        = "periods.jsonl"
+	YearClosingsFile   = "year_closings.jsonl"
+	FXRevaluationsFile = "fx_revaluations.jsonl"
+	SettingsFile       = "coa_settings.jsonl"
+)
+
+// dataFiles are the JSON Lines files every archive holds, in the order
+// they are written and restored.
+var dataFiles = []string{
+	AccountsFile, TransactionsFile, FXRatesFile, PeriodsFile, YearClosingsFile, FXRevaluationsFile, SettingsFile,
+}
+
+// maxLine is the longest JSON line read back, which bounds the entries of
+// one transaction.
+const maxLine = 16 << 20
+
+// Export writes a consistent snapshot of st to w and returns its manifest.
+func Export(ctx context.Context, w io.Writer, st *store.Store) (*ledger.ArchiveManifest, error) {
+	zw := zip.NewWriter(w)
+	m := &ledger.ArchiveManifest{
+		Format:    ledger.ArchiveFormat,
+		Version:   ledger.ArchiveVersion,
+		CreatedAt: time.Now().UTC(),
+		ChainHead: ledger.GenesisHash,
+	}
+
+	err := st.Snapshot(ctx, func(snap *store.Snapshot) error {
+		m.SchemaVersion = snap.SchemaVersion
+		m.ReportingCurrency = snap.ReportingCurrency
+
+		// Each file is written by its own snapshot method, in dataFiles
+		// order.
+		write := map[string]func(f *jsonlWriter) error{
+			AccountsFile: func(f *jsonlWriter) error {
+				return snap.Accounts(ctx, func(a *ledger.Account) error { return f.write(a) })
+			},
+			TransactionsFile: func(f *jsonlWriter) error {
+				return snap.Transactions(ctx, func(t *ledger.Transaction) error {
+					m.ChainHead = t.Hash
+					return f.write(t)
+				})
+			},
+			FXRatesFile: func(f *jsonlWriter) error {
+				return snap.FXRates(ctx, func(r ledger.FXRate) error { return f.write(r) })
+			},
+			PeriodsFile: func(f *jsonlWriter) error {
+				return snap.Periods(ctx, func(p *ledger.Period) error { return f.write(p) })
+			},
+			YearClosingsFile: func(f *jsonlWriter) error {
+				return snap.YearClosings(ctx, func(c ledger.YearClosing) error { return f.write(c) })
+			},
+			FXRevaluationsFile: func(f *jsonlWriter) error {
+				return snap.FXRevaluations(ctx, func(r *ledger.FXRevaluation) error { return f.write(r) })
+			},
+			SettingsFile: func(f *jsonlWriter) error {
+				return snap.Settings(ctx, func(cs ledger.CoASetting) error { return f.write(cs) })
+			},
+		}
+		for _, name := range dataFiles {
+			f, err := create(zw, name, m.CreatedAt)
+			if err != nil {
+				return err
+			}
+			if err := write[name](f); err != nil {
+				return err
+			}
+			m.Files = append(m.Files, f.done())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mw, err := zw.CreateHeader(fileHeader(ManifestFile, m.CreatedAt))
+	if err != nil {
+		return nil, fmt.Errorf("write %s: %w", ManifestFile, err)
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("write %s: %w", ManifestFile, err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("finish archive: %w", err)
+	}
+	return m, nil
+}
+
+// jsonlWriter writes one JSON value per line to an archive file, counting
+// and hashing what it writes.
+type jsonlWriter struct {
+	name string
+	enc  *json.Encoder
+	sum  hash.Hash
+	rows int
+}
+
+func create(zw *zip.Writer, name string, modified time.Time) (*jsonlWriter, error) {
+	w, err := zw.CreateHeader(fileHeader(name, modified))
+	if err != nil {
+		return nil, fmt.Errorf("write %s: %w", name, err)
+	}
+	sum := sha256.New()
+	return &jsonlWriter{name: name, enc: json.NewEncoder(io.MultiWriter(w, sum)), sum: sum}, nil
+}
+
+func (f *jsonlWriter) write(v any) error {
+	if err := f.enc.Encode(v); err != nil {
+		return fmt.Errorf("write %s: %w", f.name, err)
+	}
+	f.rows++
+	return nil
+}
+
+func (f *jsonlWriter) done() ledger.ArchiveFile {
+	return ledger.ArchiveFile{Name: f.name, Rows: f.rows, SHA256: hex.EncodeToString(f.sum.Sum(nil))}
+}
+
+func fileHeader(name string, modified time.Time) *zip.FileHeader {
+	return &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified}
+}
+
+// Reader is an open archive.
+type Reader struct {
+	zr       *zip.ReadCloser
+	Manifest ledger.ArchiveManifest
+}
+
+// Open opens the archive at path and reads its manifest.
+func Open(path string) (*Reader, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ledger.ErrInvalidArchive, err)
+	}
+	r := &Reader{zr: zr}
+	if err := r.readManifest(); err != nil {
+		zr.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Close closes the archive.
+func (r *Reader) Close() error {
+	return r.zr.Close()
+}
+
+func (r *Reader) readManifest() error {
+	f, err := r.zr.Open(ManifestFile)
+	if err != nil {
+		return fmt.Errorf("%w: no %s", ledger.ErrInvalidArchive, ManifestFile)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&r.Manifest); err != nil {
+		return fmt.Errorf("%w: %s: %v", ledger.ErrInvalidArchive, ManifestFile, err)
+	}
+	m := &r.Manifest
+	if m.Format != ledger.ArchiveFormat {
+		return fmt.Errorf("%w: format %q, want %q", ledger.ErrInvalidArchive, m.Format, ledger.ArchiveFormat)
+	}
+	if m.Version < 1 || m.Version > ledger.ArchiveVersion {
+		return fmt.Errorf("%w: layout version %d, this miniledger reads up to %d", ledger.ErrInvalidArchive, m.Version, ledger.ArchiveVersion)
+	}
+	for _, name := range dataFiles {
+		if r.file(name) == nil {
+			return fmt.Errorf("%w: the manifest does not list %s", ledger.ErrInvalidArchive, name)
+		}
+	}
+	return nil
+}
+
+// file returns the manifest entry for name, or nil.
+func (r *Reader) file(name string) *ledger.ArchiveFile {
+	for i := range r.Manifest.Files {
+		if r.Manifest.Files[i].Name == name {
+			return &r.Manifest.Files[i]
+		}
+	}
+	return nil
+}
+
+// Verify checks every file against the row count and checksum the
+// manifest records for it.
+func (r *Reader) Verify() error {
+	for _, want := range r.Manifest.Files {
+		f, err := r.zr.Open(want.Name)
+		if err != nil {
+			return fmt.Errorf("%w: %s is missing", ledger.ErrInvalidArchive, want.Name)
+		}
+		sum := sha256.New()
+		rows := 0
+		s := bufio.NewScanner(io.TeeReader(f, sum))
+		s.Buffer(make([]byte, 64<<10), maxLine)
+		for s.Scan() {
+			rows++
+		}
+		err = s.Err()
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%w: read %s: %v", ledger.ErrInvalidArchive, want.Name, err)
+		}
+		if got := hex.EncodeToString(sum.Sum(nil)); got != want.SHA256 {
+			return fmt.Errorf("%w: %s has checksum %s, the manifest says %s", ledger.ErrInvalidArchive, want.Name, got, want.SHA256)
+		}
+		if rows != want.Rows {
+			return fmt.Errorf("%w: %s has %d rows, the manifest says %d", ledger.ErrInvalidArchive, want.Name, rows, want.Rows)
+		}
+	}
+	return nil
+}
+
+// Restore verifies the archive and replays it into st, which must be a new
+// ledger with the archive's reporting currency.
+func (r *Reader) Restore(ctx context.Context, st *store.Store) error {
+	if err := r.Verify(); err != nil {
+		return err
+	}
+	return st.Restore(ctx, &r.Manifest, func(rs *store.Restorer) error {
+		err := r.each(AccountsFile, func(data []byte) error {
+			var a ledger.Account
+			if err := decode(data, &a); err != nil {
+				return err
+			}
+			return rs.Account(ctx, &a)
+		})
+		if err != nil {
+			return err
+		}
+		err = r.each(TransactionsFile, func(data []byte) error {
+			var t ledger.Transaction
+			if err := decode(data, &t); err != nil {
+				return err
+			}
+			return rs.Transaction(ctx, &t)
+		})
+		if err != nil {
+			return err
+		}
+		err = r.each(FXRatesFile, func(data []byte) error {
+			var rate ledger.FXRate
+			if err := decode(data, &rate); err != nil {
+				return err
+			}
+			return rs.FXRate(ctx, rate)
+		})
+		if err != nil {
+			return err
+		}
+		err = r.each(PeriodsFile, func(data []byte) error {
+			var p ledger.Period
+			if err := decode(data, &p); err != nil {
+				return err
+			}
+			return rs.Period(ctx, &p)
+		})
+		if err != nil {
+			return err
+		}
+		err = r.each(YearClosingsFile, func(data []byte) error {
+			var c ledger.YearClosing
+			if err := decode(data, &c); err != nil {
+				return err
+			}
+			return rs.YearClosing(ctx, c)
+		})
+		if err != nil {
+			return err
+		}
+		err = r.each(FXRevaluationsFile, func(data []byte) error {
+			var rev ledger.FXRevaluation
+			if err := decode(data, &rev); err != nil {
+				return err
+			}
+			return rs.FXRevaluation(ctx, &rev)
+		})
+		if err != nil {
+			return err
+		}
+		return r.each(SettingsFile, func(data []byte) error {
+			var cs ledger.CoASetting
+			if err := decode(data, &cs); err != nil {
+				return err
+			}
+			rs.Setting(cs)
+			return nil
+		})
+	})
+}
+
+// each calls fn with every line of the archive file name.
+func (r *Reader) each(name string, fn func(data []byte) error) error {
+	f, err := r.zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s is missing", ledger.ErrInvalidArchive, name)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64<<10), maxLine)
+	for line := 1; s.Scan(); line++ {
+		if err := fn(s.Bytes()); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+	return s.Err()
+}
+
+func decode(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ledger.ErrInvalidArchive, err)
+	}
+	return nil
+}
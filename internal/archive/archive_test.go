@@ -0,0 +1,171 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TestRoundTrip restores a ledger with a revaluation, a closed year and a
+// closed period, and checks the copy reports and behaves like the original.
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	src, err := store.Open(filepath.Join(dir, "src.db"), store.Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer src.Close()
+
+	for _, a := range []*ledger.Account{
+		{ID: "<bk:usd>", Name: "Bank USD", Code: 1010, Category: ledger.CategoryAssets, Currency: "USD"},
+		{ID: "sales", Name: "Sales", Code: 4010, Category: ledger.CategoryRevenue, Currency: "USD"},
+		{ID: "re_usd", Name: "Retained Earnings USD", Code: 3010, Category: ledger.CategoryEquity, Currency: "USD"},
+		{ID: "re_gel", Name: "Retained Earnings GEL", Code: 3010, Category: ledger.CategoryEquity, Currency: "GEL"},
+	} {
+		if err := src.CreateAccount(ctx, a); err != nil {
+			t.Fatalf("CreateAccount %s: %v", a.ID, err)
+		}
+	}
+	if _, err := src.SetRate(ctx, "USD", 2.5, date("2025-01-01")); err != nil {
+		t.Fatalf("SetRate: %v", err)
+	}
+	sale := &ledger.Transaction{
+		Description: "Sale",
+		PostedAt:    date("2025-03-01"),
+		Entries: []ledger.Entry{
+			{AccountID: "<bk:usd>", Amount: 10000, Currency: "USD"},
+			{AccountID: "sales", Amount: -10000, Currency: "USD"},
+		},
+	}
+	if err := src.CreateTransaction(ctx, sale); err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+	if _, err := src.SetRate(ctx, "USD", 2.7, date("2025-06-30")); err != nil {
+		t.Fatalf("SetRate: %v", err)
+	}
+	if _, err := src.RevalueFX(ctx, date("2025-06-30")); err != nil {
+		t.Fatalf("RevalueFX: %v", err)
+	}
+	if _, err := src.CloseYear(ctx, 2025); err != nil {
+		t.Fatalf("CloseYear: %v", err)
+	}
+	q1 := &ledger.Period{ID: "2025-Q1", StartsAt: date("2025-01-01"), EndsAt: date("2025-04-01")}
+	if err := src.CreatePeriod(ctx, q1); err != nil {
+		t.Fatalf("CreatePeriod: %v", err)
+	}
+	if _, err := src.SetPeriodStatus(ctx, q1.ID, ledger.PeriodClosing); err != nil {
+		t.Fatalf("SetPeriodStatus: %v", err)
+	}
+	if _, err := src.SetPeriodStatus(ctx, q1.ID, ledger.PeriodClosed); err != nil {
+		t.Fatalf("SetPeriodStatus: %v", err)
+	}
+	deposit := func() *ledger.Transaction {
+		return &ledger.Transaction{
+			Description:    "Deposit",
+			IdempotencyKey: "dep-1",
+			Entries: []ledger.Entry{
+				{AccountID: "<bk:usd>", Amount: 500, Currency: "USD"},
+				{AccountID: "sales", Amount: -500, Currency: "USD"},
+			},
+		}
+	}
+	dep := deposit()
+	if err := src.CreateTransaction(ctx, dep); err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+
+	path := filepath.Join(dir, "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Export(ctx, f, src); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ar, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open archive: %v", err)
+	}
+	defer ar.Close()
+	dst, err := store.Open(filepath.Join(dir, "dst.db"), store.Options{ReportingCurrency: ar.Manifest.ReportingCurrency})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer dst.Close()
+	if err := ar.Restore(ctx, dst); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	compare := func(name string, get func(*store.Store) (any, error)) {
+		t.Helper()
+		want, err := get(src)
+		if err != nil {
+			t.Fatalf("%s of the original: %v", name, err)
+		}
+		got, err := get(dst)
+		if err != nil {
+			t.Fatalf("%s of the restored ledger: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s of the restored ledger:\n%+v\nwant\n%+v", name, got, want)
+		}
+	}
+	compare("IncomeStatement", func(st *store.Store) (any, error) {
+		is, err := st.IncomeStatement(ctx, date("2025-01-01"), date("2026-01-01"))
+		if err == nil {
+			is.GeneratedAt = time.Time{}
+		}
+		return is, err
+	})
+	compare("ListFXRevaluations", func(st *store.Store) (any, error) { return st.ListFXRevaluations(ctx) })
+	compare("ListPeriods", func(st *store.Store) (any, error) { return st.ListPeriods(ctx) })
+	compare("ListYearClosings", func(st *store.Store) (any, error) { return st.ListYearClosings(ctx) })
+	compare("ListRates", func(st *store.Store) (any, error) { return st.ListRates(ctx, "") })
+
+	if is, err := dst.IncomeStatement(ctx, date("2025-01-01"), date("2026-01-01")); err != nil || is.TotalRevenue == 0 {
+		t.Errorf("restored 2025 income statement = %+v, %v; want the sale's revenue", is, err)
+	}
+
+	// The restored ledger refuses what the original would, and replays
+	// what it would.
+	replay := deposit()
+	if err := dst.CreateTransaction(ctx, replay); err != nil || replay.ID != dep.ID {
+		t.Errorf("replaying the deposit on the restored ledger got %s, %v; want %s", replay.ID, err, dep.ID)
+	}
+	if _, err := dst.CloseYear(ctx, 2025); err == nil {
+		t.Error("closed 2025 again on the restored ledger")
+	}
+	late := &ledger.Transaction{
+		Description: "Backdated",
+		PostedAt:    date("2025-02-01"),
+		Entries: []ledger.Entry{
+			{AccountID: "<bk:usd>", Amount: 100, Currency: "USD"},
+			{AccountID: "sales", Amount: -100, Currency: "USD"},
+		},
+	}
+	if err := dst.CreateTransaction(ctx, late); err == nil {
+		t.Error("posted into the closed period on the restored ledger")
+	}
+	if _, err := dst.RevalueFX(ctx, date("2025-06-30")); err == nil {
+		t.Error("revalued as of 2025-06-30 again on the restored ledger")
+	}
+}
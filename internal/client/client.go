@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/simonvc/miniledger/internal/ledger"
@@ -15,6 +17,8 @@ import (
 
 type Client struct {
 	baseURL    string
+	entity     string // named entity the client's requests go to; empty for the default ledger
+	token      string // API token sent as a bearer token
 	httpClient *http.Client
 }
 
@@ -27,8 +31,46 @@ func New(baseURL string) *Client {
 	}
 }
 
+// ForEntity returns a client for the named entity's ledger. An empty name
+// means the default ledger.
+func (c *Client) ForEntity(name string) *Client {
+	scoped := *c
+	scoped.entity = name
+	return &scoped
+}
+
+// WithToken returns a client that authenticates with the API token token.
+func (c *Client) WithToken(token string) *Client {
+	scoped := *c
+	scoped.token = token
+	return &scoped
+}
+
+// Entity returns the entity the client is scoped to, or "" for the default
+// ledger.
+func (c *Client) Entity() string {
+	return c.entity
+}
+
+// endpoint returns the URL of an /api/v1 path, routed to the client's
+// entity if it has one.
+func (c *Client) endpoint(path string) string {
+	if c.entity != "" && strings.HasPrefix(path, "/api/v1/") {
+		path = "/api/v1/entities/" + url.PathEscape(c.entity) + strings.TrimPrefix(path, "/api/v1")
+	}
+	return c.baseURL + path
+}
+
 func (c *Client) CreateAccount(ctx context.Context, acct *ledger.Account) (*ledger.Account, error) {
-	body := map[string]any{
+	var result ledger.Account
+	if err := c.post(ctx, "/api/v1/accounts", accountBody(acct), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func accountBody(acct *ledger.Account) map[string]any {
+	return map[string]any{
 		"id":        acct.ID,
 		"name":      acct.Name,
 		"code":      acct.Code,
@@ -36,11 +78,31 @@ func (c *Client) CreateAccount(ctx context.Context, acct *ledger.Account) (*ledg
 		"category":  acct.Category,
 		"is_system": acct.IsSystem,
 	}
-	var result ledger.Account
-	if err := c.post(ctx, "/api/v1/accounts", body, &result); err != nil {
+}
+
+// AccountBatchResult is the outcome of one account of CreateAccounts.
+// Status is the HTTP status it would have had if created on its own.
+type AccountBatchResult struct {
+	Status  int             `json:"status"`
+	Account *ledger.Account `json:"account,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// CreateAccounts creates accts in one request and one SQL transaction,
+// returning a result per account in order. Each account is created or
+// fails on its own.
+func (c *Client) CreateAccounts(ctx context.Context, accts []*ledger.Account) ([]AccountBatchResult, error) {
+	items := make([]map[string]any, len(accts))
+	for i, acct := range accts {
+		items[i] = accountBody(acct)
+	}
+	var result struct {
+		Results []AccountBatchResult `json:"results"`
+	}
+	if err := c.post(ctx, "/api/v1/accounts/batch", map[string]any{"accounts": items}, &result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return result.Results, nil
 }
 
 func (c *Client) ListAccounts(ctx context.Context, category string, system *bool) ([]ledger.Account, error) {
@@ -75,22 +137,66 @@ type BalanceResponse struct {
 	Balance   int64  `json:"balance"`
 	Currency  string `json:"currency"`
 	Formatted string `json:"formatted"`
+
+	// Pending is the net of open holds; Available is the ledger balance
+	// less holds against the account. Both are omitted for as-of queries.
+	Pending            int64  `json:"pending,omitempty"`
+	Available          int64  `json:"available,omitempty"`
+	AvailableFormatted string `json:"available_formatted,omitempty"`
 }
 
-func (c *Client) GetAccountBalance(ctx context.Context, id string) (*BalanceResponse, error) {
+// GetAccountBalance returns the balance of id. A zero asOf means the
+// current balance.
+func (c *Client) GetAccountBalance(ctx context.Context, id string, asOf time.Time) (*BalanceResponse, error) {
 	var result BalanceResponse
-	if err := c.get(ctx, "/api/v1/accounts/"+url.PathEscape(id)+"/balance", &result); err != nil {
+	if err := c.get(ctx, "/api/v1/accounts/"+url.PathEscape(id)+"/balance"+asOfQuery(asOf), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *Client) ListAccountEntries(ctx context.Context, id string) ([]ledger.Entry, error) {
-	var result []ledger.Entry
-	if err := c.get(ctx, "/api/v1/accounts/"+url.PathEscape(id)+"/entries", &result); err != nil {
+// ListAccountEntries fetches one page of id's entries, newest first. Pass
+// the previous page's NextCursor to continue; limit 0 uses the server default.
+func (c *Client) ListAccountEntries(ctx context.Context, id string, limit int, cursor string) (*ledger.EntryPage, error) {
+	params := url.Values{}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	var result ledger.EntryPage
+	if err := c.get(ctx, "/api/v1/accounts/"+url.PathEscape(id)+"/entries?"+params.Encode(), &result); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return &result, nil
+}
+
+// AllAccountEntries follows the entry pages of id to the end.
+func (c *Client) AllAccountEntries(ctx context.Context, id string) ([]ledger.Entry, error) {
+	var all []ledger.Entry
+	cursor := ""
+	for {
+		page, err := c.ListAccountEntries(ctx, id, maxPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Entries...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// AccountStatement fetches the statement for id between from and to; zero
+// bounds are left open.
+func (c *Client) AccountStatement(ctx context.Context, id string, from, to time.Time) (*ledger.AccountStatement, error) {
+	var result ledger.AccountStatement
+	if err := c.get(ctx, "/api/v1/accounts/"+url.PathEscape(id)+"/statement?"+rangeQuery(from, to), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 func (c *Client) RenameAccount(ctx context.Context, id, newName string) (*ledger.Account, error) {
@@ -106,7 +212,23 @@ func (c *Client) DeleteAccount(ctx context.Context, id string) error {
 	return c.del(ctx, "/api/v1/accounts/"+url.PathEscape(id))
 }
 
+// CreateTransaction posts txn. When txn.IdempotencyKey is set it is sent as
+// the Idempotency-Key header, so retrying the same call is safe. Set
+// txn.Status to ledger.StatusPending to place a hold instead.
 func (c *Client) CreateTransaction(ctx context.Context, txn *ledger.Transaction) (*ledger.Transaction, error) {
+	header := http.Header{}
+	if txn.IdempotencyKey != "" {
+		header.Set("Idempotency-Key", txn.IdempotencyKey)
+	}
+	var result ledger.Transaction
+	if err := c.postWithHeader(ctx, "/api/v1/transactions", header, transactionBody(txn), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// transactionBody is the request body that creates txn.
+func transactionBody(txn *ledger.Transaction) map[string]any {
 	type entryReq struct {
 		AccountID string `json:"account_id"`
 		Amount    int64  `json:"amount"`
@@ -120,23 +242,110 @@ func (c *Client) CreateTransaction(ctx context.Context, txn *ledger.Transaction)
 		"description": txn.Description,
 		"entries":     entries,
 	}
-	var result ledger.Transaction
-	if err := c.post(ctx, "/api/v1/transactions", body, &result); err != nil {
+	if txn.IdempotencyKey != "" {
+		body["idempotency_key"] = txn.IdempotencyKey
+	}
+	if !txn.PostedAt.IsZero() {
+		body["posted_at"] = txn.PostedAt.Format(time.RFC3339Nano)
+	}
+	if txn.Status == ledger.StatusPending {
+		body["pending"] = true
+		if txn.ExpiresAt != nil {
+			body["expires_at"] = txn.ExpiresAt.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return body
+}
+
+// BatchResult is the outcome of one transaction of CreateTransactions.
+// Status is the HTTP status it would have had if posted on its own: 201, or
+// 202 when parked for approval, on success.
+type BatchResult struct {
+	Status      int                 `json:"status"`
+	Transaction *ledger.Transaction `json:"transaction,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// CreateTransactions posts txns in one request and one SQL transaction,
+// returning a result per transaction in order. When atomic, either all are
+// posted or none are and the first failure is returned as the error.
+// Otherwise each is posted or fails on its own. Each txn's IdempotencyKey
+// is honoured as in CreateTransaction.
+func (c *Client) CreateTransactions(ctx context.Context, txns []*ledger.Transaction, atomic bool) ([]BatchResult, error) {
+	items := make([]map[string]any, len(txns))
+	for i, txn := range txns {
+		items[i] = transactionBody(txn)
+	}
+	mode := "best_effort"
+	if atomic {
+		mode = "atomic"
+	}
+	var result struct {
+		Results []BatchResult `json:"results"`
+	}
+	body := map[string]any{"mode": mode, "transactions": items}
+	if err := c.post(ctx, "/api/v1/transactions/batch", body, &result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return result.Results, nil
 }
 
-func (c *Client) ListTransactions(ctx context.Context, accountID string) ([]ledger.Transaction, error) {
-	params := url.Values{}
-	if accountID != "" {
-		params.Set("account_id", accountID)
+// maxPageSize is the largest page the server hands out.
+const maxPageSize = 500
+
+// TxnQuery filters a transaction listing. Zero values are ignored; amounts
+// are absolute minor units matched against a single entry, together with
+// AccountID, Currency and Code.
+type TxnQuery struct {
+	AccountID   string
+	From        time.Time
+	To          time.Time
+	Description string
+	Currency    string
+	Code        int
+	MinAmount   int64
+	MaxAmount   int64
+	Pending     bool // open holds instead of finalized transactions
+	Limit       int
+	Cursor      string
+}
+
+func (q TxnQuery) encode() string {
+	params, _ := url.ParseQuery(rangeQuery(q.From, q.To))
+	set := func(k, v string) {
+		if v != "" {
+			params.Set(k, v)
+		}
 	}
-	var result []ledger.Transaction
-	if err := c.get(ctx, "/api/v1/transactions?"+params.Encode(), &result); err != nil {
+	set("account_id", q.AccountID)
+	set("q", q.Description)
+	set("currency", q.Currency)
+	set("cursor", q.Cursor)
+	if q.Code != 0 {
+		params.Set("code", strconv.Itoa(q.Code))
+	}
+	if q.MinAmount > 0 {
+		params.Set("min_amount", strconv.FormatInt(q.MinAmount, 10))
+	}
+	if q.MaxAmount > 0 {
+		params.Set("max_amount", strconv.FormatInt(q.MaxAmount, 10))
+	}
+	if q.Pending {
+		params.Set("pending", "true")
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return params.Encode()
+}
+
+// ListTransactions fetches one page of transactions, newest first.
+func (c *Client) ListTransactions(ctx context.Context, q TxnQuery) (*ledger.TransactionPage, error) {
+	var result ledger.TransactionPage
+	if err := c.get(ctx, "/api/v1/transactions?"+q.encode(), &result); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return &result, nil
 }
 
 func (c *Client) GetTransaction(ctx context.Context, id string) (*ledger.Transaction, error) {
@@ -147,25 +356,312 @@ func (c *Client) GetTransaction(ctx context.Context, id string) (*ledger.Transac
 	return &result, nil
 }
 
-func (c *Client) BalanceSheet(ctx context.Context) (*ledger.BalanceSheet, error) {
+// ReverseTransaction posts a reversing transaction for id and returns it.
+func (c *Client) ReverseTransaction(ctx context.Context, id, reason string) (*ledger.Transaction, error) {
+	body := map[string]string{"reason": reason}
+	var result ledger.Transaction
+	if err := c.post(ctx, "/api/v1/transactions/"+id+"/reverse", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FinalizeTransaction captures the pending transaction id.
+func (c *Client) FinalizeTransaction(ctx context.Context, id string) (*ledger.Transaction, error) {
+	var result ledger.Transaction
+	if err := c.post(ctx, "/api/v1/transactions/"+id+"/finalize", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// VoidTransaction releases the pending transaction id.
+func (c *Client) VoidTransaction(ctx context.Context, id string) (*ledger.Transaction, error) {
+	var result ledger.Transaction
+	if err := c.post(ctx, "/api/v1/transactions/"+id+"/void", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListApprovals returns the approval queue: pending requests when status
+// is empty, or "all" for decided ones too.
+func (c *Client) ListApprovals(ctx context.Context, status string) ([]ledger.Approval, error) {
+	path := "/api/v1/approvals"
+	if status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+	var result []ledger.Approval
+	if err := c.get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ApproveTransaction approves the parked transaction id.
+func (c *Client) ApproveTransaction(ctx context.Context, id, comment string) (*ledger.Approval, error) {
+	body := map[string]string{"comment": comment}
+	var result ledger.Approval
+	if err := c.post(ctx, "/api/v1/approvals/"+url.PathEscape(id)+"/approve", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RejectTransaction refuses the parked transaction id.
+func (c *Client) RejectTransaction(ctx context.Context, id, comment string) (*ledger.Approval, error) {
+	body := map[string]string{"comment": comment}
+	var result ledger.Approval
+	if err := c.post(ctx, "/api/v1/approvals/"+url.PathEscape(id)+"/reject", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreatePeriod opens a new accounting period covering [start, end).
+func (c *Client) CreatePeriod(ctx context.Context, id string, start, end time.Time) (*ledger.Period, error) {
+	body := map[string]string{
+		"id":        id,
+		"starts_at": start.Format(time.RFC3339Nano),
+		"ends_at":   end.Format(time.RFC3339Nano),
+	}
+	var result ledger.Period
+	if err := c.post(ctx, "/api/v1/periods", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) ListPeriods(ctx context.Context) ([]ledger.Period, error) {
+	var result []ledger.Period
+	if err := c.get(ctx, "/api/v1/periods", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClosePeriod locks a period against new postings. With soft set the period
+// moves to "closing" instead, which still accepts adjustments.
+func (c *Client) ClosePeriod(ctx context.Context, id string, soft bool) (*ledger.Period, error) {
+	body := map[string]bool{"soft": soft}
+	var result ledger.Period
+	if err := c.post(ctx, "/api/v1/periods/"+url.PathEscape(id)+"/close", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReopenPeriod moves a closing period back to open.
+func (c *Client) ReopenPeriod(ctx context.Context, id string) (*ledger.Period, error) {
+	var result ledger.Period
+	if err := c.post(ctx, "/api/v1/periods/"+url.PathEscape(id)+"/reopen", struct{}{}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CloseYear posts the year-end closing transactions for year.
+func (c *Client) CloseYear(ctx context.Context, year int) ([]ledger.YearClosing, error) {
+	body := map[string]int{"year": year}
+	var result []ledger.YearClosing
+	if err := c.post(ctx, "/api/v1/closings", body, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) ListYearClosings(ctx context.Context) ([]ledger.YearClosing, error) {
+	var result []ledger.YearClosing
+	if err := c.get(ctx, "/api/v1/closings", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetFXRate records the GEL mid-rate for currency from effectiveAt onwards;
+// a zero effectiveAt means now.
+func (c *Client) SetFXRate(ctx context.Context, currency string, rate float64, effectiveAt time.Time) (*ledger.FXRate, error) {
+	body := map[string]any{"currency": currency, "rate": rate}
+	if !effectiveAt.IsZero() {
+		body["effective_at"] = effectiveAt.UTC().Format(time.RFC3339Nano)
+	}
+	var result ledger.FXRate
+	if err := c.post(ctx, "/api/v1/fx/rates", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ImportFXRates stores a batch of historical rates atomically and returns
+// the number imported.
+func (c *Client) ImportFXRates(ctx context.Context, rates []ledger.FXRate) (int, error) {
+	body := make([]map[string]any, len(rates))
+	for i, r := range rates {
+		body[i] = map[string]any{
+			"currency":     r.Currency,
+			"rate":         r.Rate,
+			"effective_at": r.EffectiveAt.UTC().Format(time.RFC3339Nano),
+		}
+	}
+	var result struct {
+		Imported int `json:"imported"`
+	}
+	if err := c.post(ctx, "/api/v1/fx/rates/import", body, &result); err != nil {
+		return 0, err
+	}
+	return result.Imported, nil
+}
+
+// ListFXRates returns the rate history, newest first; an empty currency
+// lists every currency.
+func (c *Client) ListFXRates(ctx context.Context, currency string) ([]ledger.FXRate, error) {
+	path := "/api/v1/fx/rates"
+	if currency != "" {
+		path += "?currency=" + url.QueryEscape(currency)
+	}
+	var result []ledger.FXRate
+	if err := c.get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EffectiveFXRates returns every currency's GEL rate effective at asOf;
+// the zero time means now.
+func (c *Client) EffectiveFXRates(ctx context.Context, asOf time.Time) (ledger.RateTable, error) {
+	var result ledger.RateTable
+	if err := c.get(ctx, "/api/v1/fx/rates/effective"+asOfQuery(asOf), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RevalueFX runs an FX revaluation as of asOf; the zero time means now.
+func (c *Client) RevalueFX(ctx context.Context, asOf time.Time) (*ledger.FXRevaluation, error) {
+	body := map[string]string{}
+	if !asOf.IsZero() {
+		body["as_of"] = asOf.UTC().Format(time.RFC3339Nano)
+	}
+	var result ledger.FXRevaluation
+	if err := c.post(ctx, "/api/v1/fx/revaluations", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) ListFXRevaluations(ctx context.Context) ([]ledger.FXRevaluation, error) {
+	var result []ledger.FXRevaluation
+	if err := c.get(ctx, "/api/v1/fx/revaluations", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetFXRevaluation fetches a revaluation run with its audit lines.
+func (c *Client) GetFXRevaluation(ctx context.Context, id string) (*ledger.FXRevaluation, error) {
+	var result ledger.FXRevaluation
+	if err := c.get(ctx, "/api/v1/fx/revaluations/"+url.PathEscape(id), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) BalanceSheet(ctx context.Context, asOf time.Time) (*ledger.BalanceSheet, error) {
 	var result ledger.BalanceSheet
-	if err := c.get(ctx, "/api/v1/reports/balance-sheet", &result); err != nil {
+	if err := c.get(ctx, "/api/v1/reports/balance-sheet"+asOfQuery(asOf), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *Client) TrialBalance(ctx context.Context) (*ledger.TrialBalance, error) {
+func (c *Client) TrialBalance(ctx context.Context, asOf time.Time) (*ledger.TrialBalance, error) {
 	var result ledger.TrialBalance
-	if err := c.get(ctx, "/api/v1/reports/trial-balance", &result); err != nil {
+	if err := c.get(ctx, "/api/v1/reports/trial-balance"+asOfQuery(asOf), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func (c *Client) RegulatoryRatios(ctx context.Context) (*ledger.RegulatoryRatios, error) {
+// IncomeStatement fetches the P&L between from and to; zero bounds are
+// left open.
+func (c *Client) IncomeStatement(ctx context.Context, from, to time.Time) (*ledger.IncomeStatement, error) {
+	var result ledger.IncomeStatement
+	if err := c.get(ctx, "/api/v1/reports/income-statement?"+rangeQuery(from, to), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CashFlowStatement fetches the cash flow statement between from and to;
+// zero bounds are left open.
+func (c *Client) CashFlowStatement(ctx context.Context, from, to time.Time) (*ledger.CashFlowStatement, error) {
+	var result ledger.CashFlowStatement
+	if err := c.get(ctx, "/api/v1/reports/cash-flow?"+rangeQuery(from, to), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) RegulatoryRatios(ctx context.Context, asOf time.Time) (*ledger.RegulatoryRatios, error) {
 	var result ledger.RegulatoryRatios
-	if err := c.get(ctx, "/api/v1/reports/ratios", &result); err != nil {
+	if err := c.get(ctx, "/api/v1/reports/ratios"+asOfQuery(asOf), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Audit runs the server's invariant checks on the ledger.
+func (c *Client) Audit(ctx context.Context) (*ledger.AuditReport, error) {
+	var result ledger.AuditReport
+	if err := c.get(ctx, "/api/v1/audit/invariants", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AuditLogQuery filters an audit log listing. Zero values are ignored;
+// Operation matches an operation such as setting.upsert or a family such
+// as setting.
+type AuditLogQuery struct {
+	Actor     string
+	Operation string
+	Target    string
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Cursor    string
+}
+
+func (q AuditLogQuery) encode() string {
+	params, _ := url.ParseQuery(rangeQuery(q.From, q.To))
+	set := func(k, v string) {
+		if v != "" {
+			params.Set(k, v)
+		}
+	}
+	set("actor", q.Actor)
+	set("operation", q.Operation)
+	set("target", q.Target)
+	set("cursor", q.Cursor)
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return params.Encode()
+}
+
+// ListAuditLog fetches one page of the audit log, newest first.
+func (c *Client) ListAuditLog(ctx context.Context, q AuditLogQuery) (*ledger.AuditLogPage, error) {
+	var result ledger.AuditLogPage
+	if err := c.get(ctx, "/api/v1/audit?"+q.encode(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// VerifyChain recomputes the server's transaction hash chain.
+func (c *Client) VerifyChain(ctx context.Context) (*ledger.ChainVerification, error) {
+	var result ledger.ChainVerification
+	if err := c.get(ctx, "/api/v1/audit/chain", &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -179,6 +675,83 @@ func (c *Client) GetChart(ctx context.Context) ([]ledger.ChartEntry, error) {
 	return result, nil
 }
 
+// ListCurrencies returns the server's currency registry; with enabledOnly
+// set, only currencies accepted for new accounts.
+func (c *Client) ListCurrencies(ctx context.Context, enabledOnly bool) ([]ledger.CurrencyDef, error) {
+	path := "/api/v1/currencies"
+	if enabledOnly {
+		path += "?enabled=true"
+	}
+	var result []ledger.CurrencyDef
+	if err := c.get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetCurrencyEnabled enables or disables code for new accounts.
+func (c *Client) SetCurrencyEnabled(ctx context.Context, code string, enabled bool) (*ledger.CurrencyDef, error) {
+	action := "/disable"
+	if enabled {
+		action = "/enable"
+	}
+	var result ledger.CurrencyDef
+	if err := c.post(ctx, "/api/v1/currencies/"+url.PathEscape(code)+action, struct{}{}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LedgerInfo returns the ledger-wide settings, such as its reporting
+// currency.
+func (c *Client) LedgerInfo(ctx context.Context) (*ledger.LedgerInfo, error) {
+	var result ledger.LedgerInfo
+	if err := c.get(ctx, "/api/v1/ledger", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateEntity creates a named entity with its own ledger. An empty
+// reporting currency uses the server default.
+func (c *Client) CreateEntity(ctx context.Context, name, reportingCurrency string) (*ledger.Entity, error) {
+	body := map[string]any{"name": name, "reporting_currency": reportingCurrency}
+	var result ledger.Entity
+	if err := c.ForEntity("").post(ctx, "/api/v1/entities", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) ListEntities(ctx context.Context) ([]ledger.Entity, error) {
+	var result []ledger.Entity
+	if err := c.ForEntity("").get(ctx, "/api/v1/entities", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ConsolidatedBalanceSheet fetches the balance sheet of the named entities
+// (all if none) in currency (their shared reporting currency if empty),
+// with intercompany balances eliminated. A zero asOf means now.
+func (c *Client) ConsolidatedBalanceSheet(ctx context.Context, entities []string, currency string, asOf time.Time) (*ledger.ConsolidatedBalanceSheet, error) {
+	params := url.Values{}
+	if len(entities) > 0 {
+		params.Set("entities", strings.Join(entities, ","))
+	}
+	if currency != "" {
+		params.Set("currency", currency)
+	}
+	if !asOf.IsZero() {
+		params.Set("as_of", asOf.UTC().Format(time.RFC3339Nano))
+	}
+	var result ledger.ConsolidatedBalanceSheet
+	if err := c.ForEntity("").get(ctx, "/api/v1/reports/consolidated-balance-sheet?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (c *Client) ListSettings(ctx context.Context) ([]ledger.CoASetting, error) {
 	var result []ledger.CoASetting
 	if err := c.get(ctx, "/api/v1/settings", &result); err != nil {
@@ -206,13 +779,67 @@ func (c *Client) DeleteSetting(ctx context.Context, code int, setting ledger.Set
 	return c.del(ctx, path)
 }
 
+// CreateWebhook subscribes rawURL to the ledger's events. An empty secret
+// lets the server generate one; the result carries it.
+func (c *Client) CreateWebhook(ctx context.Context, rawURL, secret string) (*ledger.Webhook, error) {
+	body := map[string]string{"url": rawURL, "secret": secret}
+	var result ledger.Webhook
+	if err := c.post(ctx, "/api/v1/webhooks", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]ledger.Webhook, error) {
+	var result []ledger.Webhook
+	if err := c.get(ctx, "/api/v1/webhooks", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return c.del(ctx, "/api/v1/webhooks/"+url.PathEscape(id))
+}
+
+// Backup asks the server to write a verified backup of the ledger into its
+// backup directory.
+func (c *Client) Backup(ctx context.Context) (*ledger.Backup, error) {
+	var result ledger.Backup
+	if err := c.post(ctx, "/api/v1/admin/backup", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// asOfQuery encodes a report as-of bound; the zero time means the current
+// state and adds no parameter.
+func asOfQuery(asOf time.Time) string {
+	if asOf.IsZero() {
+		return ""
+	}
+	return "?as_of=" + url.QueryEscape(asOf.UTC().Format(time.RFC3339Nano))
+}
+
+// rangeQuery encodes the from/to window of the period reports.
+func rangeQuery(from, to time.Time) string {
+	params := url.Values{}
+	if !from.IsZero() {
+		params.Set("from", from.UTC().Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		params.Set("to", to.UTC().Format(time.RFC3339Nano))
+	}
+	return params.Encode()
+}
+
 // Ping checks if the server is reachable.
 func (c *Client) Ping(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/chart", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint("/api/v1/chart"), nil)
 	if err != nil {
 		return err
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return err
 	}
@@ -221,7 +848,7 @@ func (c *Client) Ping(ctx context.Context) error {
 }
 
 func (c *Client) get(ctx context.Context, path string, result any) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint(path), nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -229,11 +856,11 @@ func (c *Client) get(ctx context.Context, path string, result any) error {
 }
 
 func (c *Client) del(ctx context.Context, path string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+path, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.endpoint(path), nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -254,7 +881,7 @@ func (c *Client) patch(ctx context.Context, path string, body any, result any) e
 	if err != nil {
 		return fmt.Errorf("marshal body: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, "PATCH", c.baseURL+path, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.endpoint(path), bytes.NewReader(data))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -267,12 +894,12 @@ func (c *Client) put(ctx context.Context, path string, body any) error {
 	if err != nil {
 		return fmt.Errorf("marshal body: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+path, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, "PUT", c.endpoint(path), bytes.NewReader(data))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -289,24 +916,39 @@ func (c *Client) put(ctx context.Context, path string, body any) error {
 }
 
 func (c *Client) post(ctx context.Context, path string, body any, result any) error {
+	return c.postWithHeader(ctx, path, nil, body, result)
+}
+
+func (c *Client) postWithHeader(ctx context.Context, path string, header http.Header, body any, result any) error {
 	data, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("marshal body: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(path), bytes.NewReader(data))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 	req.Header.Set("Content-Type", "application/json")
 	return c.doRequest(req, result)
 }
 
+// send performs req with the client's token, if it has one.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
 type apiError struct {
 	Error string `json:"error"`
 }
 
 func (c *Client) doRequest(req *http.Request, result any) error {
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -0,0 +1,384 @@
+// Package importer reads accounts and transactions for bulk import from CSV
+// or JSON Lines files. Records are read one at a time, so files of any size
+// can be streamed, and each keeps the line it started on for error reports.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// Format is the layout of an import file.
+type Format string
+
+const (
+	FormatCSV   Format = "csv"   // a header row, then one record (or entry) per row
+	FormatJSONL Format = "jsonl" // one JSON object per line
+)
+
+// FormatOf picks the format from path's extension: .csv, or .jsonl or
+// .ndjson.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("cannot tell the format of %s: use a .csv or .jsonl file", path)
+	}
+}
+
+// Units says how amounts are written in an import file.
+type Units string
+
+const (
+	UnitsMinor Units = "minor" // integer minor units, e.g. 1050 for USD 10.50
+	UnitsMajor Units = "major" // decimal major units, e.g. 10.50
+)
+
+// ParseUnits parses a --units value.
+func ParseUnits(s string) (Units, error) {
+	switch u := Units(s); u {
+	case UnitsMinor, UnitsMajor:
+		return u, nil
+	default:
+		return "", fmt.Errorf("invalid units %q: use minor or major", s)
+	}
+}
+
+// Amount converts s, written in u, to minor units of currency.
+func (u Units) Amount(s, currency string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if u == UnitsMajor {
+		return ledger.ToMinorUnits(s, currency)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a whole number of minor units", ledger.ErrInvalidAmount, s)
+	}
+	return n, nil
+}
+
+// LineError is a problem with the record starting on Line. The reader that
+// returned it can carry on with the next record.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// AccountReader reads accounts. CSV files need id, name and code columns
+// and may have currency and category; JSONL lines are objects with the same
+// fields. A missing currency means USD and a missing category is derived
+// from the code, as on POST /accounts.
+type AccountReader struct {
+	csv   *csvRows
+	jsonl *jsonLines
+}
+
+// NewAccountReader reads accounts in format f from r.
+func NewAccountReader(r io.Reader, f Format) (*AccountReader, error) {
+	if f == FormatJSONL {
+		return &AccountReader{jsonl: newJSONLines(r)}, nil
+	}
+	rows, err := newCSVRows(r, "id", "name", "code")
+	if err != nil {
+		return nil, err
+	}
+	return &AccountReader{csv: rows}, nil
+}
+
+// Next returns the next account and the line it is on, io.EOF at the end
+// of the file, or a *LineError for a record that cannot be read. Any other
+// error is fatal.
+func (r *AccountReader) Next() (int, *ledger.Account, error) {
+	var line int
+	acct := &ledger.Account{}
+	if r.jsonl != nil {
+		var rec struct {
+			ID       string          `json:"id"`
+			Name     string          `json:"name"`
+			Code     int             `json:"code"`
+			Currency string          `json:"currency"`
+			Category ledger.Category `json:"category"`
+		}
+		var err error
+		if line, err = r.jsonl.next(&rec); err != nil {
+			return line, nil, err
+		}
+		acct.ID, acct.Name, acct.Code, acct.Currency, acct.Category = rec.ID, rec.Name, rec.Code, rec.Currency, rec.Category
+	} else {
+		var row csvRow
+		var err error
+		if line, row, err = r.csv.next(); err != nil {
+			return line, nil, err
+		}
+		acct.ID, acct.Name = row.get("id"), row.get("name")
+		acct.Currency, acct.Category = row.get("currency"), ledger.Category(row.get("category"))
+		if acct.Code, err = strconv.Atoi(row.get("code")); err != nil {
+			return line, nil, &LineError{line, fmt.Errorf("%w: %q", ledger.ErrInvalidAccountCode, row.get("code"))}
+		}
+	}
+
+	if acct.Currency == "" {
+		acct.Currency = "USD"
+	}
+	if acct.Category == "" {
+		acct.Category, _ = ledger.CategoryForCode(acct.Code)
+	}
+	return line, acct, nil
+}
+
+// TransactionReader reads transactions.
+//
+// CSV files have one entry per row, with txn, account_id, amount and
+// currency columns and optional description, posted_at and idempotency_key
+// columns. Consecutive rows with the same txn value make up one
+// transaction, whose description, posted_at and idempotency_key are taken
+// from its first row.
+//
+// JSONL lines are POST /transactions bodies: description, posted_at,
+// idempotency_key and entries of account_id, amount and currency. Amounts
+// may be JSON numbers or strings.
+type TransactionReader struct {
+	units Units
+	csv   *csvRows
+	jsonl *jsonLines
+
+	// The first row of the next CSV transaction, read ahead.
+	peek     *csvRow
+	peekLine int
+}
+
+// NewTransactionReader reads transactions in format f from r, with amounts
+// written in units.
+func NewTransactionReader(r io.Reader, f Format, units Units) (*TransactionReader, error) {
+	if f == FormatJSONL {
+		return &TransactionReader{units: units, jsonl: newJSONLines(r)}, nil
+	}
+	rows, err := newCSVRows(r, "txn", "account_id", "amount", "currency")
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionReader{units: units, csv: rows}, nil
+}
+
+// Next returns the next transaction and the line it starts on, io.EOF at
+// the end of the file, or a *LineError for a record that cannot be read.
+// Any other error is fatal.
+func (r *TransactionReader) Next() (int, *ledger.Transaction, error) {
+	if r.jsonl != nil {
+		return r.nextJSON()
+	}
+	return r.nextCSV()
+}
+
+func (r *TransactionReader) nextJSON() (int, *ledger.Transaction, error) {
+	var rec struct {
+		Description    string `json:"description"`
+		PostedAt       string `json:"posted_at"`
+		IdempotencyKey string `json:"idempotency_key"`
+		Entries        []struct {
+			AccountID string      `json:"account_id"`
+			Amount    json.Number `json:"amount"`
+			Currency  string      `json:"currency"`
+		} `json:"entries"`
+	}
+	line, err := r.jsonl.next(&rec)
+	if err != nil {
+		return line, nil, err
+	}
+
+	txn, err := r.header(rec.Description, rec.PostedAt, rec.IdempotencyKey)
+	if err != nil {
+		return line, nil, &LineError{line, err}
+	}
+	for i, e := range rec.Entries {
+		amount, err := r.units.Amount(e.Amount.String(), e.Currency)
+		if err != nil {
+			return line, nil, &LineError{line, fmt.Errorf("entry %d: %w", i, err)}
+		}
+		txn.Entries = append(txn.Entries, ledger.Entry{AccountID: e.AccountID, Amount: amount, Currency: e.Currency})
+	}
+	return line, txn, nil
+}
+
+func (r *TransactionReader) nextCSV() (int, *ledger.Transaction, error) {
+	if r.peek == nil {
+		line, row, err := r.csv.next()
+		if err != nil {
+			return line, nil, err
+		}
+		r.peek, r.peekLine = &row, line
+	}
+	first, line := *r.peek, r.peekLine
+	r.peek = nil
+
+	ref := first.get("txn")
+	txn, err := r.header(first.get("description"), first.get("posted_at"), first.get("idempotency_key"))
+	if ref == "" {
+		err = errors.New("txn is required")
+	}
+	if err != nil {
+		err = &LineError{line, err}
+	}
+
+	// Take rows until the txn column changes, remembering the first bad one.
+	row, rowLine := first, line
+	for {
+		if err == nil {
+			amount, aerr := r.units.Amount(row.get("amount"), row.get("currency"))
+			if aerr != nil {
+				err = &LineError{rowLine, aerr}
+			} else {
+				txn.Entries = append(txn.Entries, ledger.Entry{AccountID: row.get("account_id"), Amount: amount, Currency: row.get("currency")})
+			}
+		}
+		if ref == "" {
+			break
+		}
+
+		nextLine, next, rerr := r.csv.next()
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return nextLine, nil, rerr
+		}
+		if next.get("txn") != ref {
+			r.peek, r.peekLine = &next, nextLine
+			break
+		}
+		row, rowLine = next, nextLine
+	}
+	if err != nil {
+		return line, nil, err
+	}
+	return line, txn, nil
+}
+
+// header starts a transaction from its non-entry fields.
+func (r *TransactionReader) header(description, postedAt, idempotencyKey string) (*ledger.Transaction, error) {
+	txn := &ledger.Transaction{Description: description, IdempotencyKey: idempotencyKey}
+	if postedAt != "" {
+		t, err := ledger.ParseTime(postedAt)
+		if err != nil {
+			return nil, fmt.Errorf("posted_at: %w", err)
+		}
+		txn.PostedAt = t
+	}
+	return txn, nil
+}
+
+// csvRows reads the rows of a CSV file after its header.
+type csvRows struct {
+	r    *csv.Reader
+	cols map[string]int
+}
+
+// newCSVRows reads the header row, which must name the required columns.
+func newCSVRows(r io.Reader, required ...string) (*csvRows, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty file: expected a header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("header has no %s column", name)
+		}
+	}
+	return &csvRows{r: cr, cols: cols}, nil
+}
+
+// next returns the next non-blank row and its line. Malformed CSV is fatal.
+func (c *csvRows) next() (int, csvRow, error) {
+	for {
+		fields, err := c.r.Read()
+		if err == io.EOF {
+			return 0, csvRow{}, io.EOF
+		}
+		if err != nil {
+			return 0, csvRow{}, err
+		}
+		line, _ := c.r.FieldPos(0)
+		if len(fields) == 1 && strings.TrimSpace(fields[0]) == "" {
+			continue
+		}
+		return line, csvRow{fields: fields, cols: c.cols}, nil
+	}
+}
+
+// csvRow is one row, with its values looked up by column name.
+type csvRow struct {
+	fields []string
+	cols   map[string]int
+}
+
+func (r csvRow) get(name string) string {
+	i, ok := r.cols[name]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+// maxLine is the longest JSONL line accepted.
+const maxLine = 1 << 20
+
+// jsonLines reads one JSON object per line, skipping blank lines.
+type jsonLines struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newJSONLines(r io.Reader) *jsonLines {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64<<10), maxLine)
+	return &jsonLines{s: s}
+}
+
+// next decodes the next line into v. Unknown fields are an error, so a
+// misspelt field is not silently dropped.
+func (j *jsonLines) next(v any) (int, error) {
+	for j.s.Scan() {
+		j.line++
+		b := bytes.TrimSpace(j.s.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(v); err != nil {
+			return j.line, &LineError{j.line, fmt.Errorf("invalid JSON: %w", err)}
+		}
+		return j.line, nil
+	}
+	if err := j.s.Err(); err != nil {
+		return j.line + 1, fmt.Errorf("line %d: %w", j.line+1, err)
+	}
+	return j.line, io.EOF
+}
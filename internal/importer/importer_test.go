@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestTransactionReaderCSV(t *testing.T) {
+	const file = `txn,description,account_id,amount,currency
+a,Deposit,<bk:usd>,10.50,USD
+a,,acc_1,-10.50,USD
+
+b,Too precise,<bk:usd>,1.234,USD
+b,,acc_1,-1.234,USD
+c,Fee,acc_1,0.25,USD
+c,,~tax,-0.25,USD
+c,,~tax,0,USD
+`
+	rd, err := NewTransactionReader(strings.NewReader(file), FormatCSV, UnitsMajor)
+	if err != nil {
+		t.Fatalf("NewTransactionReader: %v", err)
+	}
+
+	tests := []struct {
+		line    int
+		entries int
+		errLine int // 0 when the transaction reads cleanly
+	}{
+		{2, 2, 0},
+		{5, 0, 5},
+		{7, 3, 0},
+	}
+	for _, tt := range tests {
+		line, txn, err := rd.Next()
+		if line != tt.line {
+			t.Errorf("transaction on line %d, want %d", line, tt.line)
+		}
+		var lerr *LineError
+		switch {
+		case tt.errLine != 0:
+			if !errors.As(err, &lerr) || lerr.Line != tt.errLine || !errors.Is(err, ledger.ErrInvalidAmount) {
+				t.Errorf("line %d: err = %v, want an invalid amount on line %d", tt.line, err, tt.errLine)
+			}
+		case err != nil:
+			t.Errorf("line %d: %v", tt.line, err)
+		case len(txn.Entries) != tt.entries:
+			t.Errorf("line %d: %d entries, want %d", tt.line, len(txn.Entries), tt.entries)
+		}
+	}
+	if _, _, err := rd.Next(); err != io.EOF {
+		t.Errorf("after the last transaction: %v, want io.EOF", err)
+	}
+}
+
+func TestUnitsAmount(t *testing.T) {
+	tests := []struct {
+		units  Units
+		amount string
+		want   int64
+		ok     bool
+	}{
+		{UnitsMinor, "1050", 1050, true},
+		{UnitsMinor, "-7", -7, true},
+		{UnitsMinor, "10.50", 0, false},
+		{UnitsMajor, "10.50", 1050, true},
+		{UnitsMajor, "-0.01", -1, true},
+		{UnitsMajor, "1.234", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.units.Amount(tt.amount, "USD")
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%s %q = %d, %v; want %d, ok %v", tt.units, tt.amount, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -111,6 +111,16 @@ func ValidateCorrespondentID(code int, id string) error {
 	return nil
 }
 
+// CorrespondentBank returns the bank named by a <bank:ccy> or >bank:ccy<
+// account ID, lowercased.
+func CorrespondentBank(id string) (string, bool) {
+	if !nostroPattern.MatchString(id) && !vostroPattern.MatchString(id) {
+		return "", false
+	}
+	bank, _, _ := strings.Cut(id[1:], ":")
+	return strings.ToLower(bank), true
+}
+
 // Validate checks all account invariants.
 func (a *Account) Validate() error {
 	if a.ID == "" {
@@ -154,7 +164,7 @@ func (a *Account) Validate() error {
 		return fmt.Errorf("%w: customer accounts (2020) must use acc_N format, e.g. acc_1", ErrInvalidAccountID)
 	}
 
-	if a.Currency != "*" && !ValidCurrency(a.Currency) {
+	if _, ok := LookupCurrency(a.Currency); a.Currency != "*" && !ok {
 		return fmt.Errorf("%w: %s", ErrInvalidCurrency, a.Currency)
 	}
 
@@ -0,0 +1,64 @@
+package ledger
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Role is what an API key may do. Each role includes the ones before it.
+//
+// reader — reads accounts, transactions, reports, settings and logs.
+// poster — also posts transactions, creates accounts and sets FX rates.
+// admin  — also changes settings, currencies and periods, deletes accounts,
+// runs revaluations and year-end closings, and creates entities.
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RolePoster Role = "poster"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRank = map[Role]int{RoleReader: 1, RolePoster: 2, RoleAdmin: 3}
+
+// ValidRole checks if r is a known role.
+func ValidRole(r Role) bool {
+	return roleRank[r] > 0
+}
+
+// Allows reports whether r includes the required role.
+func (r Role) Allows(required Role) bool {
+	return roleRank[r] >= roleRank[required]
+}
+
+// APIKey is a credential for the REST API. Only the SHA-256 of its token
+// is stored; the token itself is shown once, when the key is created.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"` // recorded as the actor of the key's changes
+	Role      Role       `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APITokenPrefix starts every API token, so leaked tokens are easy to spot.
+const APITokenPrefix = "ml_"
+
+// NewAPIToken returns a random API token.
+func NewAPIToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return APITokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashAPIToken returns the hex SHA-256 of token, the form keys are stored
+// and looked up in.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
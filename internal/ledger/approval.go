@@ -0,0 +1,42 @@
+package ledger
+
+import "time"
+
+// ApprovalStatus is the state of a transaction in the maker-checker
+// approval queue. A transaction with an entry above its code's
+// APPROVAL_THRESHOLD is written but parked unfinalized until a checker
+// other than its maker decides.
+//
+// pending  — awaiting a checker; it does not count towards balances.
+// approved — approved and finalized, or placed as a hold if one was asked for.
+// rejected — refused; it is never posted.
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+// Approval is a transaction's entry in the approval queue.
+type Approval struct {
+	TransactionID string         `json:"transaction_id"`
+	Status        ApprovalStatus `json:"status"`
+	Reason        string         `json:"reason"` // which entry exceeded which threshold
+	RequestedBy   string         `json:"requested_by"`
+	RequestedAt   time.Time      `json:"requested_at"`
+	DecidedBy     string         `json:"decided_by,omitempty"`
+	DecidedAt     *time.Time     `json:"decided_at,omitempty"`
+	Comment       string         `json:"comment,omitempty"` // the checker's, e.g. why it was rejected
+	Transaction   *Transaction   `json:"transaction,omitempty"`
+}
+
+// ExceedsThreshold reports whether an entry amount needs approval under
+// threshold, in minor units of the entry's currency. A zero threshold
+// means no approval is needed.
+func ExceedsThreshold(amount, threshold int64) bool {
+	if amount < 0 {
+		amount = -amount
+	}
+	return threshold > 0 && amount > threshold
+}
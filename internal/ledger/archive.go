@@ -0,0 +1,35 @@
+package ledger
+
+import "time"
+
+// ArchiveFormat names the export archive format in its manifest, and
+// ArchiveVersion is the layout version written. Readers refuse archives
+// with a newer layout.
+const (
+	ArchiveFormat  = "miniledger-export"
+	ArchiveVersion = 3
+)
+
+// ArchiveManifest describes an export archive: where it came from and each
+// JSON Lines file it holds.
+type ArchiveManifest struct {
+	Format            string    `json:"format"`
+	Version           int       `json:"version"`
+	SchemaVersion     int       `json:"schema_version"` // of the exported ledger
+	ReportingCurrency string    `json:"reporting_currency"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// ChainHead is the hash of the last exported transaction, or
+	// GenesisHash if there are none. A restore reproduces it.
+	ChainHead string `json:"chain_head"`
+
+	Files []ArchiveFile `json:"files"`
+}
+
+// ArchiveFile is one JSON Lines file of an archive, with its number of
+// lines and the hex SHA-256 of its content.
+type ArchiveFile struct {
+	Name   string `json:"name"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
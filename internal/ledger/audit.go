@@ -0,0 +1,143 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxAuditProblems caps the problems an audit check lists; Count still
+// reports how many were found.
+const MaxAuditProblems = 20
+
+// AuditCheck is the outcome of one invariant check.
+type AuditCheck struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Passed      bool     `json:"passed"`
+	Count       int      `json:"count"`              // problems found
+	Problems    []string `json:"problems,omitempty"` // at most MaxAuditProblems
+}
+
+// Problemf records a failure of the check.
+func (c *AuditCheck) Problemf(format string, args ...any) {
+	c.Passed = false
+	c.Count++
+	if len(c.Problems) < MaxAuditProblems {
+		c.Problems = append(c.Problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// AuditReport is the result of verifying a ledger's invariants directly
+// against its database, independent of the triggers that should have
+// enforced them.
+type AuditReport struct {
+	Checks        []AuditCheck `json:"checks"`
+	Passed        bool         `json:"passed"`
+	SchemaVersion int          `json:"schema_version"`
+	GeneratedAt   time.Time    `json:"generated_at"`
+}
+
+// Failed returns the number of checks that did not pass.
+func (r *AuditReport) Failed() int {
+	n := 0
+	for _, c := range r.Checks {
+		if !c.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// Audit log operations.
+const (
+	OpAccountCreate   = "account.create"
+	OpAccountRename   = "account.rename"
+	OpAccountDelete   = "account.delete"
+	OpSettingUpsert   = "setting.upsert"
+	OpSettingDelete   = "setting.delete"
+	OpCurrencyEnable  = "currency.enable"
+	OpCurrencyDisable = "currency.disable"
+	OpPeriodCreate    = "period.create"
+	OpPeriodStatus    = "period.status"
+	OpFXRateSet       = "fx_rate.set"
+	OpAPIKeyCreate    = "api_key.create"
+	OpAPIKeyRevoke    = "api_key.revoke"
+	OpWebhookCreate   = "webhook.create"
+	OpWebhookDelete   = "webhook.delete"
+)
+
+// AuditLogEntry records one change to the ledger's accounts, settings,
+// currencies, periods, rates, API keys or webhooks: who made it, when, and the target's state
+// before and after as JSON. Before is empty for creations, After for
+// deletions. Postings are not logged; the journal and its hash chain are
+// their record.
+type AuditLogEntry struct {
+	ID        int64           `json:"id"`
+	At        time.Time       `json:"at"`
+	Actor     string          `json:"actor"`
+	Operation string          `json:"operation"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditLogPage is one page of the audit log, newest first.
+type AuditLogPage struct {
+	Entries    []AuditLogEntry `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// SettingTarget is the audit log target of a CoA code setting.
+func SettingTarget(code int, name SettingName) string {
+	return fmt.Sprintf("%d/%s", code, name)
+}
+
+// FieldChange is one top-level field that differs between an audit log
+// entry's before and after states.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Changes lists the fields whose values differ between e's before and after
+// states, sorted by name. Values are shown as JSON, strings unquoted; a
+// field missing from one side is empty there.
+func (e AuditLogEntry) Changes() []FieldChange {
+	fields := func(raw json.RawMessage) map[string]json.RawMessage {
+		m := map[string]json.RawMessage{}
+		if len(raw) > 0 {
+			json.Unmarshal(raw, &m)
+		}
+		return m
+	}
+	before, after := fields(e.Before), fields(e.After)
+
+	names := map[string]bool{}
+	for k := range before {
+		names[k] = true
+	}
+	for k := range after {
+		names[k] = true
+	}
+
+	var changes []FieldChange
+	for k := range names {
+		b, a := jsonText(before[k]), jsonText(after[k])
+		if b != a {
+			changes = append(changes, FieldChange{Field: k, Before: b, After: a})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func jsonText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
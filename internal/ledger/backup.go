@@ -0,0 +1,13 @@
+package ledger
+
+import "time"
+
+// Backup describes a verified copy of a ledger database.
+type Backup struct {
+	Path          string    `json:"path"`
+	Size          int64     `json:"size"` // bytes
+	SchemaVersion int       `json:"schema_version"`
+	Transactions  int64     `json:"transactions"` // in the hash chain
+	ChainHead     string    `json:"chain_head"`
+	CreatedAt     time.Time `json:"created_at"`
+}
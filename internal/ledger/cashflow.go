@@ -0,0 +1,88 @@
+package ledger
+
+import "time"
+
+// CashAccountCodes are the nostro (1010) and reserve (1060) codes whose
+// movements make up the cash flow statement.
+var CashAccountCodes = []int{1010, 1060}
+
+// IsCashCode reports whether code is one of CashAccountCodes.
+func IsCashCode(code int) bool {
+	for _, c := range CashAccountCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// CashFlowActivity is the IAS 7 classification of a cash movement.
+type CashFlowActivity string
+
+const (
+	ActivityOperating CashFlowActivity = "operating"
+	ActivityInvesting CashFlowActivity = "investing"
+	ActivityFinancing CashFlowActivity = "financing"
+)
+
+// ActivityForCode classifies a cash movement by the CoA code of its
+// counter-entry: equity and borrowings are financing, PP&E is investing and
+// everything else (revenue, expenses, customer funds, working capital) is
+// operating.
+func ActivityForCode(code int) CashFlowActivity {
+	switch {
+	case code >= 3000 && code < 4000, code == 2040:
+		return ActivityFinancing
+	case code == 1050:
+		return ActivityInvesting
+	default:
+		return ActivityOperating
+	}
+}
+
+// CashFlowLine is the net cash moved against one counter-entry code and
+// currency. Positive amounts are inflows.
+type CashFlowLine struct {
+	Code     int    `json:"code"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// CashFlowSection holds the lines for one activity.
+type CashFlowSection struct {
+	Activity CashFlowActivity `json:"activity"`
+	Lines    []CashFlowLine   `json:"lines"`
+	Totals   map[string]int64 `json:"totals"` // currency → net flow in minor units
+	Total    int64            `json:"total"`  // in the reporting currency
+}
+
+// CashFlowStatement reports cash movements through the cash accounts over
+// [From, To]. Nil bounds are open-ended. Opening and Closing are the cash
+// balances per currency either side of the window.
+type CashFlowStatement struct {
+	From              *time.Time       `json:"from,omitempty"`
+	To                *time.Time       `json:"to,omitempty"`
+	Operating         CashFlowSection  `json:"operating"`
+	Investing         CashFlowSection  `json:"investing"`
+	Financing         CashFlowSection  `json:"financing"`
+	Opening           map[string]int64 `json:"opening"`
+	Closing           map[string]int64 `json:"closing"`
+	NetChange         map[string]int64 `json:"net_change"`
+	NetChangeTotal    int64            `json:"net_change_total"` // in the reporting currency
+	GeneratedAt       time.Time        `json:"generated_at"`
+	ReportingCurrency string           `json:"reporting_currency"`
+	Rates             RateTable        `json:"rates"` // rates effective at To
+}
+
+// Section returns the section for activity a.
+func (cf *CashFlowStatement) Section(a CashFlowActivity) *CashFlowSection {
+	switch a {
+	case ActivityInvesting:
+		return &cf.Investing
+	case ActivityFinancing:
+		return &cf.Financing
+	default:
+		return &cf.Operating
+	}
+}
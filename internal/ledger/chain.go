@@ -0,0 +1,78 @@
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first link in the chain.
+var GenesisHash = strings.Repeat("0", 64)
+
+// chainContent is the canonical form a transaction is hashed in. JSON
+// escaping keeps field boundaries unambiguous.
+type chainContent struct {
+	PrevHash    string       `json:"prev_hash"`
+	ID          string       `json:"id"`
+	Description string       `json:"description"`
+	PostedAt    string       `json:"posted_at"`
+	Entries     []chainEntry `json:"entries"`
+}
+
+type chainEntry struct {
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+}
+
+// ChainHash returns the hex SHA-256 of txn's canonical content — ID,
+// description, UTC posting time and entries sorted by account, currency and
+// amount — chained to prevHash, the hash of the previously finalized
+// transaction.
+func ChainHash(prevHash string, txn *Transaction) string {
+	c := chainContent{
+		PrevHash:    prevHash,
+		ID:          txn.ID,
+		Description: txn.Description,
+		PostedAt:    txn.PostedAt.UTC().Format(time.RFC3339Nano),
+		Entries:     make([]chainEntry, len(txn.Entries)),
+	}
+	for i, e := range txn.Entries {
+		c.Entries[i] = chainEntry{AccountID: e.AccountID, Currency: e.Currency, Amount: e.Amount}
+	}
+	sort.Slice(c.Entries, func(i, j int) bool {
+		a, b := c.Entries[i], c.Entries[j]
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		return a.Amount < b.Amount
+	})
+	data, _ := json.Marshal(c)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainBreak describes the first link of the chain that does not verify.
+type ChainBreak struct {
+	Seq           int64  `json:"seq"`
+	TransactionID string `json:"transaction_id"`
+	Reason        string `json:"reason"`
+	Expected      string `json:"expected,omitempty"`
+	Stored        string `json:"stored,omitempty"`
+}
+
+// ChainVerification is the result of recomputing the hash chain over
+// every finalized transaction.
+type ChainVerification struct {
+	Valid      bool        `json:"valid"`
+	Length     int64       `json:"length"` // links verified before any break
+	Head       string      `json:"head"`   // hash of the last verified link
+	Break      *ChainBreak `json:"break,omitempty"`
+	VerifiedAt time.Time   `json:"verified_at"`
+}
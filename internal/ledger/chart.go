@@ -43,6 +43,7 @@ var PredefinedAccounts = []ChartEntry{
 
 // SystemAccounts are internal accounts created automatically.
 var SystemAccounts = []ChartEntry{
+	{Code: 1096, ID: "~fxreval", Name: "FX Revaluation Adjustment", Category: CategoryAssets, IsSystem: true, Description: "Reporting-currency carrying adjustment on revalued foreign-currency balances"},
 	{Code: 1097, ID: "~fx", Name: "FX Conversion", Category: CategoryAssets, IsSystem: true, Description: "Intermediary for cross-currency transactions"},
 	{Code: 1098, ID: "~settlement", Name: "Settlement", Category: CategoryAssets, IsSystem: true, Description: "Pending settlement with payment processors/banks"},
 	{Code: 1099, ID: "~suspense", Name: "Suspense Account", Category: CategoryAssets, IsSystem: true, Description: "Temporary holding for unclassified entries"},
@@ -51,9 +52,25 @@ var SystemAccounts = []ChartEntry{
 	{Code: 3099, ID: "~capital", Name: "Capital", Category: CategoryEquity, IsSystem: true, Description: "Owner's capital contributions and withdrawals"},
 	{Code: 4099, ID: "~interest", Name: "Interest Income", Category: CategoryRevenue, IsSystem: true, Description: "Interest earned on customer balances or loans"},
 	{Code: 4090, ID: "~fees", Name: "Fee Income", Category: CategoryRevenue, IsSystem: true, Description: "Fee income from customer charges"},
+	{Code: 4091, ID: "~fxgain", Name: "FX Revaluation Gain", Category: CategoryRevenue, IsSystem: true, Description: "Unrealised gains from FX revaluation"},
+	{Code: 5092, ID: "~fxloss", Name: "FX Revaluation Loss", Category: CategoryExpenses, IsSystem: true, Description: "Unrealised losses from FX revaluation"},
 	{Code: 5091, ID: "~writeoff", Name: "Write-offs", Category: CategoryExpenses, IsSystem: true, Description: "Bad debt write-offs, failed payments, irrecoverable amounts"},
 }
 
+// SystemAccountCurrency returns the currency a system account is created in:
+// ~fx takes any currency, the FX revaluation accounts are kept in the
+// ledger's reporting currency and the rest default to USD.
+func SystemAccountCurrency(id, reporting string) string {
+	switch id {
+	case "~fx":
+		return "*"
+	case FXRevaluationAccount, FXGainAccount, FXLossAccount:
+		return reporting
+	default:
+		return "USD"
+	}
+}
+
 // LookupChartEntry finds a chart entry by code (checks predefined and system accounts).
 func LookupChartEntry(code int) *ChartEntry {
 	for i := range PredefinedAccounts {
@@ -5,26 +5,47 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type CurrencyDef struct {
-	Code     string
-	Name     string
-	Exponent int // 2 for USD (100 cents), 0 for JPY
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Exponent int    `json:"exponent"` // 2 for USD (100 cents), 0 for JPY, 3 for KWD
+	Enabled  bool   `json:"enabled"`  // accepted for new accounts and rates
 }
 
-var Currencies = map[string]CurrencyDef{
-	"USD": {Code: "USD", Name: "US Dollar", Exponent: 2},
-	"EUR": {Code: "EUR", Name: "Euro", Exponent: 2},
-	"GBP": {Code: "GBP", Name: "Pound Sterling", Exponent: 2},
-	"GEL": {Code: "GEL", Name: "Georgian Lari", Exponent: 2},
+// currencies indexes ISO4217 by code. Which currencies a ledger accepts,
+// and which one it reports in, live in that ledger's own tables, so one
+// process can serve several ledgers.
+var currencies = func() map[string]CurrencyDef {
+	m := make(map[string]CurrencyDef, len(ISO4217))
+	for _, c := range ISO4217 {
+		m[c.Code] = c
+	}
+	return m
+}()
+
+// LookupCurrency returns the ISO 4217 entry for code. Enabled is always
+// false: enablement is per ledger.
+func LookupCurrency(code string) (CurrencyDef, bool) {
+	c, ok := currencies[code]
+	return c, ok
 }
 
-// ReportingCurrency is the currency used for consolidated reporting.
-const ReportingCurrency = "GEL"
+// DefaultReportingCurrency is the reporting currency of ledgers created
+// without one, including every ledger created before it was configurable.
+const DefaultReportingCurrency = "GEL"
 
-// FXRatesToGEL maps currency codes to their mid-rate in GEL.
-// These are indicative rates; in production you'd fetch live rates.
+// LedgerInfo describes ledger-wide settings fixed when the ledger is created.
+type LedgerInfo struct {
+	ReportingCurrency string `json:"reporting_currency"`
+}
+
+// FXRatesToGEL maps currency codes to their indicative mid-rate in GEL.
+// Crossed into the reporting currency by IndicativeRates, they seed the
+// fx_rates table; reports use the rates stored there, effective at the
+// report date.
 var FXRatesToGEL = map[string]float64{
 	"GEL": 1.0,
 	"USD": 2.70,
@@ -32,26 +53,87 @@ var FXRatesToGEL = map[string]float64{
 	"GBP": 3.40,
 }
 
-// ToGEL converts an amount in minor units of the given currency to GEL minor units.
-func ToGEL(amount int64, currency string) int64 {
-	if currency == "GEL" {
-		return amount
-	}
-	rate, ok := FXRatesToGEL[currency]
+// IndicativeRates crosses FXRatesToGEL into base. A base without an
+// indicative rate gets a table holding only itself.
+func IndicativeRates(base string) RateTable {
+	b, ok := FXRatesToGEL[base]
 	if !ok {
-		return 0
+		return RateTable{base: 1}
+	}
+	t := make(RateTable, len(FXRatesToGEL))
+	for ccy, rate := range FXRatesToGEL {
+		t[ccy] = rate / b
+	}
+	t[base] = 1
+	return t
+}
+
+// FXRate is the mid-rate of one unit of Currency in the reporting currency
+// from EffectiveAt until the next rate for the same currency.
+type FXRate struct {
+	Currency    string    `json:"currency"`
+	Rate        float64   `json:"rate"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+// Validate checks the currency is a known ISO 4217 code and the rate is
+// positive. The store checks the currency is enabled and is not its
+// reporting currency.
+func (r *FXRate) Validate() error {
+	if _, ok := LookupCurrency(r.Currency); !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidCurrency, r.Currency)
+	}
+	if !(r.Rate > 0) || math.IsInf(r.Rate, 0) {
+		return fmt.Errorf("%w: %v", ErrInvalidRate, r.Rate)
 	}
-	return int64(math.Round(float64(amount) * rate))
+	return nil
+}
+
+// RateTable maps currency codes to their mid-rate in the reporting currency
+// at one point in time; the reporting currency itself is 1.
+type RateTable map[string]float64
+
+// Rate returns the rate of one unit of currency in the table's reporting
+// currency, or zero if the table has none.
+func (r RateTable) Rate(currency string) float64 {
+	return r[currency]
+}
+
+// Convert converts an amount in minor units of from into minor units of
+// to, crossing through the table's rates. It returns ErrRateNotFound,
+// naming the currency, when either side has no rate.
+func (r RateTable) Convert(amount int64, from, to string) (int64, error) {
+	if from == to {
+		return amount, nil
+	}
+	for _, ccy := range []string{from, to} {
+		if r.Rate(ccy) == 0 {
+			return 0, fmt.Errorf("%w: %s", ErrRateNotFound, ccy)
+		}
+	}
+	return int64(math.Round(float64(amount) * r.Rate(from) / r.Rate(to) * MinorUnitScale(from, to))), nil
+}
+
+// MinorUnitScale returns the factor that turns minor units of from into
+// minor units of to at a rate of 1, e.g. 0.01 from USD cents to whole JPY.
+func MinorUnitScale(from, to string) float64 {
+	return math.Pow10(exponent(to) - exponent(from))
 }
 
-func ValidCurrency(code string) bool {
-	_, ok := Currencies[code]
-	return ok
+// exponent returns the currency's minor-unit exponent, assuming 2 for
+// unregistered codes.
+func exponent(code string) int {
+	if c, ok := LookupCurrency(code); ok {
+		return c.Exponent
+	}
+	return 2
 }
 
 // ToMinorUnits converts a decimal string like "10.50" to 1050 for USD.
+// Digits beyond the currency's exponent must be zero, so "1.5" is rejected
+// for JPY rather than truncated.
 func ToMinorUnits(amount string, currency string) (int64, error) {
-	cur, ok := Currencies[currency]
+	cur, ok := LookupCurrency(currency)
 	if !ok {
 		return 0, fmt.Errorf("%w: %s", ErrInvalidCurrency, currency)
 	}
@@ -65,31 +147,23 @@ func ToMinorUnits(amount string, currency string) (int64, error) {
 		amount = amount[1:]
 	}
 
-	parts := strings.SplitN(amount, ".", 2)
-	wholePart := parts[0]
-	fracPart := ""
-	if len(parts) == 2 {
-		fracPart = parts[1]
+	wholePart, fracPart, _ := strings.Cut(amount, ".")
+	if wholePart == "" && fracPart == "" || !isDigits(wholePart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
 	}
-
-	whole, err := strconv.ParseInt(wholePart, 10, 64)
-	if err != nil {
-		return 0, fmt.Errorf("invalid amount: %w", err)
-	}
-
-	multiplier := int64(math.Pow10(cur.Exponent))
-	result := whole * multiplier
-
-	if cur.Exponent > 0 && fracPart != "" {
-		for len(fracPart) < cur.Exponent {
-			fracPart += "0"
+	if len(fracPart) > cur.Exponent {
+		if strings.Trim(fracPart[cur.Exponent:], "0") != "" {
+			return 0, fmt.Errorf("%w: %s has %d decimal places", ErrInvalidAmount, currency, cur.Exponent)
 		}
 		fracPart = fracPart[:cur.Exponent]
-		frac, err := strconv.ParseInt(fracPart, 10, 64)
-		if err != nil {
-			return 0, fmt.Errorf("invalid fractional amount: %w", err)
-		}
-		result += frac
+	}
+	for len(fracPart) < cur.Exponent {
+		fracPart += "0"
+	}
+
+	result, err := strconv.ParseInt(wholePart+fracPart, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
 	}
 
 	if negative {
@@ -98,10 +172,19 @@ func ToMinorUnits(amount string, currency string) (int64, error) {
 	return result, nil
 }
 
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // FormatAmount converts minor units to a display string with comma grouping.
 // E.g. 100000050 USD -> "1,000,000.50".
 func FormatAmount(amount int64, currency string) string {
-	cur, ok := Currencies[currency]
+	cur, ok := LookupCurrency(currency)
 	if !ok {
 		return fmt.Sprintf("%d %s", amount, currency)
 	}
@@ -150,19 +233,3 @@ func commaInt(n int64) string {
 	}
 	return b.String()
 }
-
-// CurrencyCodes returns a sorted list of supported currency codes.
-func CurrencyCodes() []string {
-	codes := make([]string, 0, len(Currencies))
-	for code := range Currencies {
-		codes = append(codes, code)
-	}
-	for i := 0; i < len(codes); i++ {
-		for j := i + 1; j < len(codes); j++ {
-			if codes[i] > codes[j] {
-				codes[i], codes[j] = codes[j], codes[i]
-			}
-		}
-	}
-	return codes
-}
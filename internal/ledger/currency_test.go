@@ -0,0 +1,164 @@
+package ledger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestToMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+	}{
+		{"10.50", "USD", 1050},
+		{"10.5", "USD", 1050},
+		{"10", "USD", 1000},
+		{"10.", "USD", 1000},
+		{".05", "USD", 5},
+		{"-0.01", "USD", -1},
+		{"+3.20", "USD", 320},
+		{"10.500", "USD", 1050}, // trailing zeros beyond the exponent are fine
+		{"1500", "JPY", 1500},
+		{"1500.", "JPY", 1500},
+		{"1500.00", "JPY", 1500},
+		{"-7", "KRW", -7},
+		{"1.234", "KWD", 1234},
+		{"1.2", "KWD", 1200},
+		{"0.001", "BHD", 1},
+		{"-12.345", "OMR", -12345},
+		{"1.2345", "CLF", 12345},
+		{"0.0001", "UYW", 1},
+	}
+	for _, tt := range tests {
+		got, err := ToMinorUnits(tt.amount, tt.currency)
+		if err != nil {
+			t.Errorf("ToMinorUnits(%q, %s): %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ToMinorUnits(%q, %s) = %d, want %d", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestToMinorUnitsRejects(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     error
+	}{
+		{"1.5", "JPY", ErrInvalidAmount},
+		{"0.001", "USD", ErrInvalidAmount},
+		{"1.2345", "KWD", ErrInvalidAmount},
+		{"", "USD", ErrInvalidAmount},
+		{"-", "USD", ErrInvalidAmount},
+		{".", "USD", ErrInvalidAmount},
+		{"1.-5", "USD", ErrInvalidAmount},
+		{"--5", "USD", ErrInvalidAmount},
+		{"1,000", "USD", ErrInvalidAmount},
+		{"abc", "USD", ErrInvalidAmount},
+		{"99999999999999999999", "USD", ErrInvalidAmount},
+		{"10", "XXX", ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		_, err := ToMinorUnits(tt.amount, tt.currency)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("ToMinorUnits(%q, %s) error = %v, want %v", tt.amount, tt.currency, err, tt.want)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{100000050, "USD", "1,000,000.50"},
+		{5, "USD", "0.05"},
+		{-1, "USD", "-0.01"},
+		{0, "USD", "0.00"},
+		{1234567, "JPY", "1,234,567"},
+		{-1234, "JPY", "-1,234"},
+		{0, "JPY", "0"},
+		{1234567, "KWD", "1,234.567"},
+		{-5, "KWD", "-0.005"},
+		{12345, "CLF", "1.2345"},
+		{42, "XXX", "42 XXX"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatAmount(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, c := range ISO4217 {
+		for _, minor := range []int64{0, 1, -1, 999, 123456789} {
+			s := FormatAmount(minor, c.Code)
+			got, err := ToMinorUnits(strings.ReplaceAll(s, ",", ""), c.Code)
+			if err != nil || got != minor {
+				t.Errorf("%s: %d -> %q -> %d (%v)", c.Code, minor, s, got, err)
+			}
+		}
+	}
+}
+
+func TestLookupCurrency(t *testing.T) {
+	if c, ok := LookupCurrency("JPY"); !ok || c.Exponent != 0 || c.Enabled {
+		t.Errorf("LookupCurrency(JPY) = %+v, %v", c, ok)
+	}
+	if c, ok := LookupCurrency("KWD"); !ok || c.Exponent != 3 {
+		t.Errorf("LookupCurrency(KWD) = %+v, %v", c, ok)
+	}
+	if _, ok := LookupCurrency("XXX"); ok {
+		t.Error("LookupCurrency(XXX) found a currency")
+	}
+}
+
+func TestRateTableConvert(t *testing.T) {
+	rates := RateTable{"EUR": 1, "USD": 0.9, "JPY": 0.0062, "GBP": 1.15}
+	tests := []struct {
+		amount   int64
+		from, to string
+		want     int64
+	}{
+		{1000, "EUR", "EUR", 1000},
+		{10000, "USD", "EUR", 9000},
+		{9000, "EUR", "USD", 10000},
+		{1000000, "JPY", "EUR", 620000}, // 1,000,000 JPY -> 6,200.00 EUR
+		{620000, "EUR", "JPY", 1000000},
+		{10000, "GBP", "USD", 12778}, // crossed through EUR
+	}
+	for _, tt := range tests {
+		got, err := rates.Convert(tt.amount, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("Convert(%d, %s, %s): %v", tt.amount, tt.from, tt.to, err)
+		} else if got != tt.want {
+			t.Errorf("Convert(%d, %s, %s) = %d, want %d", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	for _, pair := range [][2]string{{"CHF", "EUR"}, {"EUR", "CHF"}} {
+		_, err := rates.Convert(10000, pair[0], pair[1])
+		if !errors.Is(err, ErrRateNotFound) || !strings.Contains(err.Error(), "CHF") {
+			t.Errorf("Convert(10000, %s, %s) error = %v, want ErrRateNotFound naming CHF", pair[0], pair[1], err)
+		}
+	}
+}
+
+func TestIndicativeRates(t *testing.T) {
+	eur := IndicativeRates("EUR")
+	if eur["EUR"] != 1 {
+		t.Errorf("base rate = %v, want 1", eur["EUR"])
+	}
+	if got, want := eur["USD"], FXRatesToGEL["USD"]/FXRatesToGEL["EUR"]; got != want {
+		t.Errorf("USD in EUR = %v, want %v", got, want)
+	}
+	if jpy := IndicativeRates("JPY"); len(jpy) != 1 || jpy["JPY"] != 1 {
+		t.Errorf("IndicativeRates(JPY) = %v", jpy)
+	}
+}
@@ -0,0 +1,151 @@
+package ledger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Entity is a named ledger served alongside the default one. Each entity
+// has its own database, chart of accounts and reporting currency.
+type Entity struct {
+	Name              string `json:"name"`
+	ReportingCurrency string `json:"reporting_currency"`
+}
+
+var entityNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// ValidateEntityName checks that name can be used as an entity name. Names
+// double as the bank part of nostro and vostro IDs, so intercompany
+// accounts between entities are named <entity:ccy> and >entity:ccy<.
+func ValidateEntityName(name string) error {
+	if !entityNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q must be 1-32 lowercase letters, digits, _ or -, starting with a letter or digit", ErrInvalidEntity, name)
+	}
+	return nil
+}
+
+// IntercompanyElimination pairs one entity's nostro with the vostro its
+// counterparty holds for it. Both balances are removed from the
+// consolidated balance sheet; a non-zero Difference means the two sides
+// disagree, usually because a payment is booked on one side only.
+type IntercompanyElimination struct {
+	Currency      string `json:"currency"`
+	NostroEntity  string `json:"nostro_entity"`
+	NostroAccount string `json:"nostro_account"`
+	NostroBalance int64  `json:"nostro_balance"`
+	VostroEntity  string `json:"vostro_entity"`
+	VostroAccount string `json:"vostro_account"`
+	VostroBalance int64  `json:"vostro_balance"`
+	Difference    int64  `json:"difference"` // nostro + vostro; zero when they agree
+}
+
+// ConsolidatedBalanceSheet combines the balance sheets of several entities
+// after eliminating their balances with each other. Lines keep their
+// entity and original currency; totals are in ReportingCurrency, each
+// entity's lines converted at that entity's own rates.
+type ConsolidatedBalanceSheet struct {
+	Entities          []string                  `json:"entities"`
+	Assets            []BalanceSheetLine        `json:"assets"`
+	Liabilities       []BalanceSheetLine        `json:"liabilities"`
+	Equity            []BalanceSheetLine        `json:"equity"`
+	Eliminations      []IntercompanyElimination `json:"eliminations"`
+	TotalAssets       int64                     `json:"total_assets"`
+	TotalLiabilities  int64                     `json:"total_liabilities"`
+	TotalEquity       int64                     `json:"total_equity"`
+	Balanced          bool                      `json:"balanced"`
+	GeneratedAt       time.Time                 `json:"generated_at"`
+	AsOf              *time.Time                `json:"as_of,omitempty"` // nil means current state
+	ReportingCurrency string                    `json:"reporting_currency"`
+}
+
+// Consolidate builds a consolidated balance sheet in currency from the
+// entities' own balance sheets. A nostro <B:ccy> held by entity A is
+// eliminated against the vostro >A:ccy< held by entity B. Every entity must
+// have a rate for currency.
+func Consolidate(sheets map[string]*BalanceSheet, currency string) (*ConsolidatedBalanceSheet, error) {
+	names := make([]string, 0, len(sheets))
+	for name := range sheets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cbs := &ConsolidatedBalanceSheet{
+		Entities:          names,
+		GeneratedAt:       time.Now().UTC(),
+		ReportingCurrency: currency,
+		Balanced:          true,
+	}
+
+	type key struct{ entity, account, currency string }
+	eliminated := map[key]bool{}
+	for _, a := range names {
+		for _, nostro := range sheets[a].Assets {
+			b, ok := CorrespondentBank(nostro.AccountID)
+			if !ok || nostro.Code != 1010 || sheets[b] == nil || b == a {
+				continue
+			}
+			for _, vostro := range sheets[b].Liabilities {
+				if vostro.Code != 2010 || vostro.Currency != nostro.Currency {
+					continue
+				}
+				if bank, _ := CorrespondentBank(vostro.AccountID); bank != a {
+					continue
+				}
+				e := IntercompanyElimination{
+					Currency:      nostro.Currency,
+					NostroEntity:  a,
+					NostroAccount: nostro.AccountID,
+					NostroBalance: nostro.Balance,
+					VostroEntity:  b,
+					VostroAccount: vostro.AccountID,
+					VostroBalance: vostro.Balance,
+					Difference:    nostro.Balance + vostro.Balance,
+				}
+				cbs.Eliminations = append(cbs.Eliminations, e)
+				eliminated[key{a, nostro.AccountID, nostro.Currency}] = true
+				eliminated[key{b, vostro.AccountID, vostro.Currency}] = true
+				if e.Difference != 0 {
+					cbs.Balanced = false
+				}
+				break
+			}
+		}
+	}
+
+	for _, name := range names {
+		bs := sheets[name]
+		if _, ok := bs.Rates[currency]; !ok {
+			return nil, fmt.Errorf("%w: entity %s has no %s rate", ErrRateNotFound, name, currency)
+		}
+		if !bs.Balanced {
+			cbs.Balanced = false
+		}
+		add := func(lines []BalanceSheetLine, dst *[]BalanceSheetLine, total *int64) error {
+			for _, line := range lines {
+				if eliminated[key{name, line.AccountID, line.Currency}] {
+					continue
+				}
+				line.Entity = name
+				*dst = append(*dst, line)
+				amt, err := bs.Rates.Convert(line.Balance, line.Currency, currency)
+				if err != nil {
+					return fmt.Errorf("entity %s: %w", name, err)
+				}
+				*total += amt
+			}
+			return nil
+		}
+		if err := add(bs.Assets, &cbs.Assets, &cbs.TotalAssets); err != nil {
+			return nil, err
+		}
+		if err := add(bs.Liabilities, &cbs.Liabilities, &cbs.TotalLiabilities); err != nil {
+			return nil, err
+		}
+		if err := add(bs.Equity, &cbs.Equity, &cbs.TotalEquity); err != nil {
+			return nil, err
+		}
+	}
+	return cbs, nil
+}
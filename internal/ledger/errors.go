@@ -13,6 +13,7 @@ var (
 	ErrTooFewEntries         = errors.New("transaction must have at least 2 entries")
 	ErrEmptyDescription      = errors.New("transaction description is required")
 	ErrInvalidCurrency       = errors.New("invalid or unsupported currency code")
+	ErrInvalidAmount         = errors.New("invalid amount")
 	ErrCurrencyMismatch      = errors.New("entry currency does not match account currency")
 	ErrAccountNotFound       = errors.New("account not found")
 	ErrTransactionNotFound   = errors.New("transaction not found")
@@ -20,4 +21,43 @@ var (
 	ErrInvertedBalance         = errors.New("transaction would create inverted balance")
 	ErrEntryDirectionViolation = errors.New("entry violates direction constraint")
 	ErrInvalidCorrespondentID  = errors.New("invalid correspondent account ID")
+	ErrAlreadyReversed         = errors.New("transaction has already been reversed")
+	ErrIdempotencyConflict     = errors.New("idempotency key was already used with a different payload")
+	ErrPeriodClosed            = errors.New("posting date falls in a closed period")
+	ErrPeriodNotFound          = errors.New("period not found")
+	ErrInvalidPeriod           = errors.New("invalid period")
+	ErrPeriodOverlap           = errors.New("period overlaps an existing period")
+	ErrInvalidPeriodTransition = errors.New("invalid period status transition")
+	ErrYearAlreadyClosed       = errors.New("fiscal year already closed")
+	ErrNothingToClose          = errors.New("no revenue or expense balances to close")
+	ErrYearOutOfOrder          = errors.New("an earlier fiscal year is not closed")
+	ErrNoRetainedEarnings      = errors.New("no retained earnings account (3010) for currency")
+	ErrInvalidCursor           = errors.New("invalid pagination cursor")
+	ErrNotPending              = errors.New("transaction is not pending")
+	ErrTransactionPending      = errors.New("transaction is still pending")
+	ErrInvalidExpiry           = errors.New("hold expiry must be in the future")
+	ErrInvalidRate             = errors.New("invalid FX rate")
+	ErrRateNotFound            = errors.New("no FX rate effective for currency")
+	ErrRevaluationOutOfOrder   = errors.New("FX revaluation must be after the last revaluation")
+	ErrNothingToRevalue        = errors.New("no foreign-currency monetary balances to revalue")
+	ErrRevaluationNotFound     = errors.New("FX revaluation not found")
+	ErrInvalidEntity           = errors.New("invalid entity name")
+	ErrEntityNotFound          = errors.New("entity not found")
+	ErrEntityExists            = errors.New("entity already exists")
+	ErrUnauthorized            = errors.New("missing or invalid API key")
+	ErrForbidden               = errors.New("API key role does not allow this operation")
+	ErrAuthRequired            = errors.New("operation needs an API key, and the server runs without them")
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrAPIKeyNotFound          = errors.New("API key not found")
+	ErrApprovalNotFound        = errors.New("transaction is not in the approval queue")
+	ErrNotAwaitingApproval     = errors.New("transaction is not awaiting approval")
+	ErrSelfApproval            = errors.New("a transaction must be approved by someone other than its maker")
+	ErrHoldExpired             = errors.New("hold expired while awaiting approval")
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrInvalidArchive          = errors.New("invalid export archive")
+	ErrLedgerNotEmpty          = errors.New("ledger already has accounts or transactions")
+	ErrBackupExists            = errors.New("backup file already exists")
+	ErrBackupCorrupt           = errors.New("backup failed its integrity check")
+	ErrBackupsDisabled         = errors.New("server has no backup directory")
 )
@@ -0,0 +1,65 @@
+package ledger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType names what happened in an Event.
+type EventType string
+
+// EventTransactionPosted is emitted when a transaction is finalized:
+// posted directly, captured from a hold or approved. Its data is the
+// transaction with its entries and chain hash.
+const EventTransactionPosted EventType = "transaction.posted"
+
+// Event is an entry in the ledger's transactional outbox, written in the
+// same SQL transaction as the change it describes. IDs increase in the
+// order events were written.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      EventType       `json:"type"`
+	Entity    string          `json:"entity,omitempty"` // set on webhook deliveries from an entity's ledger
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Webhook is a URL every new event is POSTed to. Deliveries are signed
+// with Secret, which is only returned when the webhook is created.
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Delivery counts: queued or being retried, delivered, and given up.
+	Pending   int `json:"pending"`
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
+
+// WebhookSecretPrefix starts every generated webhook secret.
+const WebhookSecretPrefix = "whsec_"
+
+// NewWebhookSecret returns a random webhook signing secret.
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+	return WebhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// SignWebhook returns the signature of a webhook body: "sha256=" and the
+// hex HMAC-SHA256 of body keyed with secret. Receivers recompute it over
+// the raw request body and compare it with hmac.Equal.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,34 @@
+package ledger
+
+import "time"
+
+// PendingStatus is the lifecycle state of a two-phase (authorize/capture)
+// transaction. Its entries are written when the hold is created but only
+// count towards balances once the transaction is finalized.
+//
+// pending  — authorized; the hold reduces available balance.
+// posted   — captured and finalized.
+// voided   — released before capture.
+// expired  — not captured before ExpiresAt and released automatically.
+type PendingStatus string
+
+const (
+	StatusPending PendingStatus = "pending"
+	StatusPosted  PendingStatus = "posted"
+	StatusVoided  PendingStatus = "voided"
+	StatusExpired PendingStatus = "expired"
+)
+
+// DefaultHoldTTL is how long a pending transaction stays open when the
+// caller does not set an expiry.
+const DefaultHoldTTL = 7 * 24 * time.Hour
+
+// AvailableBalance adjusts a ledger balance for pending holds. Only holds
+// that move the account away from its normal side count, so an incoming
+// pending credit does not make funds available before it is captured.
+func AvailableBalance(cat Category, balance, pendingDebits, pendingCredits int64) int64 {
+	if cat == CategoryAssets || cat == CategoryExpenses {
+		return balance + pendingCredits
+	}
+	return balance + pendingDebits
+}
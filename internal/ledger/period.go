@@ -0,0 +1,109 @@
+package ledger
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodStatus is the lifecycle state of an accounting period.
+//
+// open    — postings are accepted.
+// closing — books are under review; postings (adjustments) are still accepted.
+// closed  — books are locked; postings dated inside the period are rejected.
+type PeriodStatus string
+
+const (
+	PeriodOpen    PeriodStatus = "open"
+	PeriodClosing PeriodStatus = "closing"
+	PeriodClosed  PeriodStatus = "closed"
+)
+
+// Period is a fiscal period covering [StartsAt, EndsAt).
+type Period struct {
+	ID        string       `json:"id"`
+	StartsAt  time.Time    `json:"starts_at"`
+	EndsAt    time.Time    `json:"ends_at"`
+	Status    PeriodStatus `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+	ClosedAt  *time.Time   `json:"closed_at,omitempty"`
+}
+
+// Validate checks the period has an ID and a non-empty date range.
+func (p *Period) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidPeriod)
+	}
+	if p.StartsAt.IsZero() || p.EndsAt.IsZero() {
+		return fmt.Errorf("%w: start and end are required", ErrInvalidPeriod)
+	}
+	if !p.EndsAt.After(p.StartsAt) {
+		return fmt.Errorf("%w: end must be after start", ErrInvalidPeriod)
+	}
+	return nil
+}
+
+// Contains reports whether t falls inside the period.
+func (p *Period) Contains(t time.Time) bool {
+	return !t.Before(p.StartsAt) && t.Before(p.EndsAt)
+}
+
+// CanTransition reports whether a period may move from one status to another.
+// Closed is terminal: locked books cannot be reopened.
+func CanTransition(from, to PeriodStatus) bool {
+	switch from {
+	case PeriodOpen:
+		return to == PeriodClosing || to == PeriodClosed
+	case PeriodClosing:
+		return to == PeriodOpen || to == PeriodClosed
+	default:
+		return false
+	}
+}
+
+// ParseTime accepts either a calendar date (2006-01-02, taken as midnight UTC)
+// or an RFC 3339 timestamp.
+func ParseTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD or RFC 3339", s)
+	}
+	return t.UTC(), nil
+}
+
+// ParseAsOf parses a report as-of bound. A bare date means the end of that
+// day, so 2025-03-31 includes everything posted on the 31st.
+func ParseAsOf(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return EndOfDay(t), nil
+	}
+	return ParseTime(s)
+}
+
+// EndOfDay returns the last millisecond of t's calendar day in UTC.
+// Millisecond precision keeps the bound exact under SQLite julianday().
+func EndOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC).Add(-time.Millisecond)
+}
+
+// RetainedEarningsCode is the equity code that year-end closing sweeps
+// revenue and expense balances into.
+const RetainedEarningsCode = 3010
+
+// YearClosing links a fiscal year to the closing transaction posted for one
+// currency.
+type YearClosing struct {
+	Year          int       `json:"year"`
+	Currency      string    `json:"currency"`
+	TransactionID string    `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// YearEnd returns the posting time used for a year's closing entries: the
+// last second of the calendar year, UTC.
+func YearEnd(year int) time.Time {
+	return time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+}
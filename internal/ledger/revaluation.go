@@ -0,0 +1,66 @@
+package ledger
+
+import "time"
+
+// System accounts that receive FX revaluation postings, all in the
+// reporting currency.
+const (
+	FXRevaluationAccount = "~fxreval"
+	FXGainAccount        = "~fxgain"
+	FXLossAccount        = "~fxloss"
+)
+
+// nonMonetaryCodes are asset codes carried at historical cost, which an FX
+// revaluation leaves alone.
+var nonMonetaryCodes = map[int]bool{
+	1030: true, // Inventory
+	1040: true, // Prepaid Expenses
+	1050: true, // Property, Plant & Equipment
+}
+
+// IsMonetary reports whether balances on an account with this code and
+// category are monetary items that are revalued at the closing FX rate.
+// Equity, revenue and expenses stay at their historical rates.
+func IsMonetary(code int, category Category) bool {
+	switch category {
+	case CategoryAssets, CategoryLiabilities:
+		return !nonMonetaryCodes[code]
+	default:
+		return false
+	}
+}
+
+// FXRevaluation records one revaluation run: the adjustment in the
+// reporting currency booked for each foreign-currency monetary balance and
+// the rates used.
+type FXRevaluation struct {
+	ID                string              `json:"id"`
+	AsOf              time.Time           `json:"as_of"`
+	TransactionID     string              `json:"transaction_id,omitempty"` // empty when nothing moved
+	Gain              int64               `json:"gain"`                     // reporting-currency minor units
+	Loss              int64               `json:"loss"`                     // reporting-currency minor units, positive
+	ReportingCurrency string              `json:"reporting_currency"`
+	Lines             []FXRevaluationLine `json:"lines,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+}
+
+// Net returns the revaluation result in reporting-currency minor units;
+// positive is a gain.
+func (r *FXRevaluation) Net() int64 {
+	return r.Gain - r.Loss
+}
+
+// FXRevaluationLine is one account and currency in a revaluation run.
+// CarryingAmount is the reporting-currency value before the run: each
+// entry at the rate effective when it was posted, plus earlier revaluation
+// adjustments.
+type FXRevaluationLine struct {
+	AccountID       string    `json:"account_id"`
+	Currency        string    `json:"currency"`
+	Balance         int64     `json:"balance"`
+	Rate            float64   `json:"rate"`
+	RateEffectiveAt time.Time `json:"rate_effective_at"`
+	CarryingAmount  int64     `json:"carrying_amount"`
+	RevaluedAmount  int64     `json:"revalued_amount"`
+	Adjustment      int64     `json:"adjustment"` // RevaluedAmount - CarryingAmount
+}
@@ -6,6 +6,7 @@ type SettingName string
 const (
 	SettingBlockInverted SettingName = "BLOCK_NORMAL_INVERTED"
 	SettingEntryDirection SettingName = "ENTRY_DIRECTION"
+	SettingApprovalThreshold SettingName = "APPROVAL_THRESHOLD"
 )
 
 // EntryDirection controls which entry directions are allowed.
@@ -29,6 +30,10 @@ type CodeSettings struct {
 	Code           int            `json:"code"`
 	BlockInverted  bool           `json:"block_inverted"`
 	EntryDirection EntryDirection `json:"entry_direction"`
+
+	// ApprovalThreshold is the largest entry, in minor units, that posts
+	// without maker-checker approval. Zero means no approval is needed.
+	ApprovalThreshold int64 `json:"approval_threshold"`
 }
 
 // DefaultCodeSettings returns settings with default values for a given code.
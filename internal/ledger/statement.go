@@ -0,0 +1,31 @@
+package ledger
+
+import "time"
+
+// StatementLine is one entry on an account statement. Balance is the running
+// balance in the entry's currency after this entry.
+type StatementLine struct {
+	EntryID        int64     `json:"entry_id"`
+	TransactionID  string    `json:"transaction_id"`
+	PostedAt       time.Time `json:"posted_at"`
+	Description    string    `json:"description"`
+	Counterparties []string  `json:"counterparties"`
+	Amount         int64     `json:"amount"`
+	Currency       string    `json:"currency"`
+	Balance        int64     `json:"balance"`
+}
+
+// AccountStatement lists an account's entries over [From, To] with opening
+// and closing balances. Balances are keyed by currency so wildcard accounts
+// such as ~fx are covered; ordinary accounts have a single key.
+type AccountStatement struct {
+	AccountID   string           `json:"account_id"`
+	AccountName string           `json:"account_name"`
+	Currency    string           `json:"currency"`
+	From        *time.Time       `json:"from,omitempty"`
+	To          *time.Time       `json:"to,omitempty"`
+	Opening     map[string]int64 `json:"opening"`
+	Lines       []StatementLine  `json:"lines"`
+	Closing     map[string]int64 `json:"closing"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
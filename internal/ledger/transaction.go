@@ -20,6 +20,43 @@ type Transaction struct {
 	Entries     []Entry   `json:"entries"`
 	Finalized   bool      `json:"finalized"`
 	PostedAt    time.Time `json:"posted_at"`
+	ReversalOf  string    `json:"reversal_of,omitempty"` // original transaction this one reverses
+	ReversedBy  string    `json:"reversed_by,omitempty"` // reversing transaction, if any
+
+	// Hash is the transaction's link in the tamper-evident chain, set once
+	// it is finalized.
+	Hash string `json:"hash,omitempty"`
+
+	// IdempotencyKey is an optional client-supplied key. Replaying a create
+	// with the same key and payload returns the original transaction.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// RequestHash fingerprints the request that was made with
+	// IdempotencyKey. Only export archives carry it, so that a restored
+	// ledger still recognises replays.
+	RequestHash string `json:"request_hash,omitempty"`
+
+	// Status and ExpiresAt are set only on two-phase transactions. Create
+	// with Status pending to place a hold instead of posting immediately.
+	Status    PendingStatus `json:"status,omitempty"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+
+	// ApprovalStatus is set only on transactions that needed maker-checker
+	// approval; while pending they are written but not finalized.
+	ApprovalStatus ApprovalStatus `json:"approval_status,omitempty"`
+}
+
+// TransactionPage is one page of a transaction listing. NextCursor is empty
+// on the last page; otherwise pass it back to fetch the following page.
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
+
+// EntryPage is one page of an account's entries, newest first.
+type EntryPage struct {
+	Entries    []Entry `json:"entries"`
+	NextCursor string  `json:"next_cursor,omitempty"`
 }
 
 // Validate checks transaction invariants: at least 2 entries, per-currency sum is zero.
@@ -43,23 +80,50 @@ func (t *Transaction) Validate() error {
 	return nil
 }
 
+// Reversal builds the mirror of t: same accounts and currencies with every
+// amount negated. The caller is responsible for posting it.
+func (t *Transaction) Reversal(reason string) *Transaction {
+	desc := "Reversal: " + t.Description
+	if reason != "" {
+		desc += " (" + reason + ")"
+	}
+	rev := &Transaction{
+		Description: desc,
+		Entries:     make([]Entry, len(t.Entries)),
+		ReversalOf:  t.ID,
+	}
+	for i, e := range t.Entries {
+		rev.Entries[i] = Entry{
+			AccountID: e.AccountID,
+			Amount:    -e.Amount,
+			Currency:  e.Currency,
+		}
+	}
+	return rev
+}
+
 // BalanceSheet represents the balance sheet report.
 type BalanceSheetLine struct {
+	Entity      string `json:"entity,omitempty"` // set on consolidated balance sheets
 	AccountID   string `json:"account_id"`
 	AccountName string `json:"account_name"`
+	Code        int    `json:"code"`
 	Balance     int64  `json:"balance"`
 	Currency    string `json:"currency"`
 }
 
 type BalanceSheet struct {
-	Assets           []BalanceSheetLine `json:"assets"`
-	Liabilities      []BalanceSheetLine `json:"liabilities"`
-	Equity           []BalanceSheetLine `json:"equity"`
-	TotalAssets      int64              `json:"total_assets"`
-	TotalLiabilities int64              `json:"total_liabilities"`
-	TotalEquity      int64              `json:"total_equity"`
-	Balanced         bool               `json:"balanced"`
-	GeneratedAt      time.Time          `json:"generated_at"`
+	Assets            []BalanceSheetLine `json:"assets"`
+	Liabilities       []BalanceSheetLine `json:"liabilities"`
+	Equity            []BalanceSheetLine `json:"equity"`
+	TotalAssets       int64              `json:"total_assets"`
+	TotalLiabilities  int64              `json:"total_liabilities"`
+	TotalEquity       int64              `json:"total_equity"`
+	Balanced          bool               `json:"balanced"`
+	GeneratedAt       time.Time          `json:"generated_at"`
+	AsOf              *time.Time         `json:"as_of,omitempty"` // nil means current state
+	ReportingCurrency string             `json:"reporting_currency"`
+	Rates             RateTable          `json:"rates"` // rates effective at AsOf
 }
 
 // TrialBalanceLine represents a single line in the trial balance.
@@ -71,23 +135,66 @@ type TrialBalanceLine struct {
 	Currency    string `json:"currency"`
 }
 
+// TrialBalance lists account balances in their own currencies; the totals
+// are converted to the reporting currency.
 type TrialBalance struct {
-	Lines       []TrialBalanceLine `json:"lines"`
-	TotalDebit  int64              `json:"total_debit"`
-	TotalCredit int64              `json:"total_credit"`
-	Balanced    bool               `json:"balanced"`
-	GeneratedAt time.Time          `json:"generated_at"`
+	Lines             []TrialBalanceLine `json:"lines"`
+	TotalDebit        int64              `json:"total_debit"`
+	TotalCredit       int64              `json:"total_credit"`
+	Balanced          bool               `json:"balanced"` // every currency nets to zero
+	GeneratedAt       time.Time          `json:"generated_at"`
+	AsOf              *time.Time         `json:"as_of,omitempty"` // nil means current state
+	ReportingCurrency string             `json:"reporting_currency"`
+	Rates             RateTable          `json:"rates"` // rates effective at AsOf
+}
+
+// IncomeStatementLine is one account's activity over the reporting window.
+// Amount is shown in the account's normal direction: revenue and expenses
+// are both positive when they have their usual balance.
+type IncomeStatementLine struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+}
+
+// IncomeStatementGroup collects the lines booked under one CoA code.
+type IncomeStatementGroup struct {
+	Code      int                   `json:"code"`
+	Name      string                `json:"name"`
+	Lines     []IncomeStatementLine `json:"lines"`
+	Subtotals map[string]int64      `json:"subtotals"` // currency → amount in minor units
+	Total     int64                 `json:"total"`     // in the reporting currency
+}
+
+// IncomeStatement is the profit and loss report for [From, To]. Nil bounds
+// are open-ended. Totals are converted to the reporting currency.
+type IncomeStatement struct {
+	From              *time.Time             `json:"from,omitempty"`
+	To                *time.Time             `json:"to,omitempty"`
+	Revenue           []IncomeStatementGroup `json:"revenue"`
+	Expenses          []IncomeStatementGroup `json:"expenses"`
+	TotalRevenue      int64                  `json:"total_revenue"`
+	TotalExpenses     int64                  `json:"total_expenses"`
+	NetIncome         int64                  `json:"net_income"`
+	GeneratedAt       time.Time              `json:"generated_at"`
+	ReportingCurrency string                 `json:"reporting_currency"`
+	Rates             RateTable              `json:"rates"` // rates effective at To
 }
 
-// RegulatoryRatios holds key prudential metrics.
+// RegulatoryRatios holds key prudential metrics. Amounts are in the
+// reporting currency.
 type RegulatoryRatios struct {
-	CapitalAdequacy  float64 `json:"capital_adequacy"`
-	LeverageRatio    float64 `json:"leverage_ratio"`
-	ReserveRatio     float64 `json:"reserve_ratio"`
-	Equity           int64   `json:"equity"`            // absolute value (negated from credit-normal)
-	TotalAssets      int64   `json:"total_assets"`
-	Reserves         int64   `json:"reserves"`           // code 1060 balances
-	CustomerDeposits int64   `json:"customer_deposits"`  // absolute value (negated from credit-normal)
+	CapitalAdequacy   float64    `json:"capital_adequacy"`
+	LeverageRatio     float64    `json:"leverage_ratio"`
+	ReserveRatio      float64    `json:"reserve_ratio"`
+	Equity            int64      `json:"equity"` // absolute value (negated from credit-normal)
+	TotalAssets       int64      `json:"total_assets"`
+	Reserves          int64      `json:"reserves"`          // code 1060 balances
+	CustomerDeposits  int64      `json:"customer_deposits"` // absolute value (negated from credit-normal)
+	AsOf              *time.Time `json:"as_of,omitempty"`   // nil means current state
+	ReportingCurrency string     `json:"reporting_currency"`
+	Rates             RateTable  `json:"rates"` // rates effective at AsOf
 }
 
 // ProjectRatios computes projected ratios after applying proposed entries.
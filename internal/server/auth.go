@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+// anonymousActor is recorded as the actor of every change on a server
+// that does not require API keys. Callers cannot name themselves, so
+// nobody can pass for someone else in the audit log or the approval queue.
+const anonymousActor = "anonymous"
+
+// APIKeyHeader carries an API token, as an alternative to an
+// Authorization: Bearer header.
+const APIKeyHeader = "X-API-Key"
+
+// RequireAuth makes every request present an API key of the default
+// ledger. Reads need the reader role and other requests at least poster;
+// routes that change configuration need admin. The key's name is recorded
+// as the actor of the request's changes.
+func (s *Server) RequireAuth() {
+	s.auth = true
+}
+
+type identityKey struct{}
+
+// identity returns the API key that authenticated the request, or nil when
+// the server does not require API keys.
+func identity(ctx context.Context) *ledger.APIKey {
+	key, _ := ctx.Value(identityKey{}).(*ledger.APIKey)
+	return key
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !s.auth {
+			next.ServeHTTP(w, r.WithContext(store.WithActor(ctx, anonymousActor)))
+			return
+		}
+
+		token := r.Header.Get(APIKeyHeader)
+		if v := r.Header.Get("Authorization"); token == "" && v != "" {
+			scheme, rest, _ := strings.Cut(v, " ")
+			if strings.EqualFold(scheme, "Bearer") {
+				token = strings.TrimSpace(rest)
+			}
+		}
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, ledger.ErrUnauthorized.Error())
+			return
+		}
+		key, err := s.store.Authenticate(ctx, token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, mapError(err), err.Error())
+			return
+		}
+
+		required := ledger.RolePoster
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			required = ledger.RoleReader
+		}
+		if !key.Role.Allows(required) {
+			writeError(w, http.StatusForbidden, forbidden(key, required))
+			return
+		}
+
+		ctx = context.WithValue(ctx, identityKey{}, key)
+		next.ServeHTTP(w, r.WithContext(store.WithActor(ctx, key.Name)))
+	})
+}
+
+// require rejects requests whose API key lacks role. It lets every request
+// through when the server does not require API keys.
+func require(role ledger.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := identity(r.Context()); key != nil && !key.Role.Allows(role) {
+				writeError(w, http.StatusForbidden, forbidden(key, role))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireKey rejects requests on a server that does not require API keys,
+// for operations that depend on knowing who the caller is.
+func requireKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if identity(r.Context()) == nil {
+			writeError(w, mapError(ledger.ErrAuthRequired), ledger.ErrAuthRequired.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func forbidden(key *ledger.APIKey, required ledger.Role) string {
+	return fmt.Sprintf("%s: %s key %s needs %s", ledger.ErrForbidden, key.Role, key.ID, required)
+}
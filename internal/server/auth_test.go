@@ -0,0 +1,32 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+// TestApprovalsNeedKeys checks a server without API keys refuses to decide
+// approvals, whatever name the caller claims.
+func TestApprovalsNeedKeys(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "ledger.db"), store.Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+	srv := New(st, nil, "")
+
+	for _, decision := range []string{"approve", "reject"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/approvals/txn-1/"+decision, strings.NewReader("{}"))
+		req.Header.Set("X-Actor", "checker")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s without auth: status %d, want %d: %s", decision, rec.Code, http.StatusForbidden, rec.Body)
+		}
+	}
+}
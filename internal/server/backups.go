@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+// backupTimeFormat stamps backup file names; it sorts chronologically.
+const backupTimeFormat = "20060102T150405.000Z"
+
+// defaultBackupName prefixes the backup files of the default ledger.
+// Entity backups are prefixed with the entity name and kept in an
+// entities subdirectory.
+const defaultBackupName = "ledger"
+
+// EnableBackups lets admins back up a ledger into dir with POST
+// /admin/backup. When every is positive, the default ledger and every
+// entity opened so far are also backed up that often. After each backup,
+// only the newest keep backups of that ledger are kept; keep 0 keeps them
+// all.
+func (s *Server) EnableBackups(dir string, keep int, every time.Duration) {
+	s.backupDir = dir
+	s.backupName = defaultBackupName
+	s.backupKeep = keep
+	s.backupEvery = every
+}
+
+// entityBackups configures the server scoped to entity name to back up
+// alongside s.
+func (s *Server) entityBackups(scoped *Server, name string) {
+	if s.backupDir != "" {
+		scoped.backupDir = filepath.Join(s.backupDir, "entities")
+		scoped.backupName = name
+		scoped.backupKeep = s.backupKeep
+	}
+}
+
+// backup writes a verified backup of the ledger into the backup directory.
+func (s *Server) backup(w http.ResponseWriter, r *http.Request) {
+	if s.backupDir == "" {
+		writeError(w, mapError(ledger.ErrBackupsDisabled), ledger.ErrBackupsDisabled.Error())
+		return
+	}
+	b, err := backupInto(r.Context(), s.backupDir, s.backupName, s.backupKeep, s.store)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, b)
+}
+
+// scheduleBackups backs up the default ledger and every entity opened so
+// far every s.backupEvery.
+func (s *Server) scheduleBackups() {
+	ticker := time.NewTicker(s.backupEvery)
+	defer ticker.Stop()
+	for range ticker.C {
+		scheduledBackup(s.backupDir, "", s.store, s.backupKeep)
+		if s.entities != nil {
+			for name, st := range s.entities.Loaded() {
+				scheduledBackup(filepath.Join(s.backupDir, "entities"), name, st, s.backupKeep)
+			}
+		}
+	}
+}
+
+func scheduledBackup(dir, entity string, st *store.Store, keep int) {
+	prefix, name := "", defaultBackupName
+	if entity != "" {
+		prefix, name = "entity "+entity+": ", entity
+	}
+	b, err := backupInto(context.Background(), dir, name, keep, st)
+	if err != nil {
+		log.Printf("%sbackup: %v", prefix, err)
+		return
+	}
+	log.Printf("%sbacked up to %s (%d bytes, %d transactions)", prefix, b.Path, b.Size, b.Transactions)
+}
+
+// backupInto backs st up to a new time-stamped file named after name in
+// dir, then removes all but the newest keep backups of name.
+func backupInto(ctx context.Context, dir, name string, keep int, st *store.Store) (*ledger.Backup, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name+"-"+time.Now().UTC().Format(backupTimeFormat)+".db")
+	b, err := st.Backup(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if keep > 0 {
+		pruneBackups(dir, name, keep)
+	}
+	return b, nil
+}
+
+// pruneBackups removes all but the newest keep backups of name in dir.
+func pruneBackups(dir, name string, keep int) {
+	files, err := filepath.Glob(filepath.Join(dir, name+"-*.db"))
+	if err != nil {
+		return
+	}
+	// Entity names may contain hyphens, so check the time stamp parses
+	// rather than trusting the glob not to match another ledger's backups.
+	var backups []string
+	for _, f := range files {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(f), name+"-"), ".db")
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, f)
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > keep {
+		if err := os.Remove(backups[0]); err != nil {
+			log.Printf("remove old backup: %v", err)
+		}
+		backups = backups[1:]
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"acme-20260101T000000.000Z.db",
+		"acme-20260102T000000.000Z.db",
+		"acme-20260103T000000.000Z.db",
+		"acme-eu-20260101T000000.000Z.db", // another entity's backup
+		"acme-notes.db",                   // not a backup
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruneBackups(dir, "acme", 2)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{
+		"acme-20260102T000000.000Z.db",
+		"acme-20260103T000000.000Z.db",
+		"acme-eu-20260101T000000.000Z.db",
+		"acme-notes.db",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("after pruning: %v, want %v", got, want)
+	}
+}
@@ -19,13 +19,9 @@ type createAccountRequest struct {
 	IsSystem bool            `json:"is_system,omitempty"`
 }
 
-func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
-	var req createAccountRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
-		return
-	}
-
+// account builds the account req describes, defaulting its currency to
+// USD and its category to the one its code belongs to.
+func (req createAccountRequest) account() (*ledger.Account, error) {
 	if req.Currency == "" {
 		req.Currency = "USD"
 	}
@@ -34,19 +30,31 @@ func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
 	if req.Category == "" && !req.IsSystem {
 		cat, err := ledger.CategoryForCode(req.Code)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
-			return
+			return nil, err
 		}
 		req.Category = cat
 	}
 
-	acct := &ledger.Account{
+	return &ledger.Account{
 		ID:       req.ID,
 		Name:     req.Name,
 		Code:     req.Code,
 		Category: req.Category,
 		Currency: req.Currency,
 		IsSystem: req.IsSystem,
+	}, nil
+}
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
+	var req createAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	acct, err := req.account()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	if err := s.store.CreateAccount(r.Context(), acct); err != nil {
@@ -97,18 +105,42 @@ func (s *Server) getAccount(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) getAccountBalance(w http.ResponseWriter, r *http.Request) {
 	id, _ := url.PathUnescape(chi.URLParam(r, "id"))
-	balance, currency, err := s.store.AccountBalance(r.Context(), id)
+	asOf, err := asOfParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	balance, currency, err := s.store.AccountBalance(r.Context(), id, asOf)
 	if err != nil {
 		writeError(w, mapError(err), err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"account_id": id,
 		"balance":    balance,
 		"currency":   currency,
 		"formatted":  ledger.FormatAmount(balance, currency),
-	})
+	}
+
+	// Holds only affect the current position, not historical balances.
+	if asOf.IsZero() {
+		acct, err := s.store.GetAccount(r.Context(), id)
+		if err != nil {
+			writeError(w, mapError(err), err.Error())
+			return
+		}
+		debits, credits, err := s.store.PendingAmounts(r.Context(), id)
+		if err != nil {
+			writeError(w, mapError(err), err.Error())
+			return
+		}
+		available := ledger.AvailableBalance(acct.Category, balance, debits, credits)
+		resp["pending"] = debits + credits
+		resp["available"] = available
+		resp["available_formatted"] = ledger.FormatAmount(available, currency)
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) renameAccount(w http.ResponseWriter, r *http.Request) {
@@ -144,13 +176,38 @@ func (s *Server) deleteAccount(w http.ResponseWriter, r *http.Request) {
 func (s *Server) listAccountEntries(w http.ResponseWriter, r *http.Request) {
 	id, _ := url.PathUnescape(chi.URLParam(r, "id"))
 
-	entries, err := s.store.ListEntriesByAccount(r.Context(), id, store.EntryFilter{Limit: 100})
+	limit, err := limitParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := s.store.ListEntriesByAccount(r.Context(), id, store.EntryFilter{
+		Limit:  limit,
+		Cursor: r.URL.Query().Get("cursor"),
+	})
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) getAccountStatement(w http.ResponseWriter, r *http.Request) {
+	id, _ := url.PathUnescape(chi.URLParam(r, "id"))
+	from, to, err := rangeParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	st, err := s.store.AccountStatement(r.Context(), id, from, to)
 	if err != nil {
 		writeError(w, mapError(err), err.Error())
 		return
 	}
-	if entries == nil {
-		entries = []ledger.Entry{}
+	if st.Lines == nil {
+		st.Lines = []ledger.StatementLine{}
 	}
-	writeJSON(w, http.StatusOK, entries)
+	writeJSON(w, http.StatusOK, st)
 }
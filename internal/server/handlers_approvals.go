@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// listApprovals returns the approval queue, pending requests by default.
+// ?status=all lists decided requests too.
+func (s *Server) listApprovals(w http.ResponseWriter, r *http.Request) {
+	status := ledger.ApprovalStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = ledger.ApprovalPending
+	case "all":
+		status = ""
+	case ledger.ApprovalPending, ledger.ApprovalApproved, ledger.ApprovalRejected:
+	default:
+		writeError(w, http.StatusBadRequest, "status must be pending, approved, rejected or all")
+		return
+	}
+
+	approvals, err := s.store.ListApprovals(r.Context(), status)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, approvals)
+}
+
+func (s *Server) getApproval(w http.ResponseWriter, r *http.Request) {
+	a, err := s.store.GetApproval(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+type decideApprovalRequest struct {
+	Comment string `json:"comment"`
+}
+
+// approveTransaction approves a parked transaction as the caller, who
+// must not be the one who posted it.
+func (s *Server) approveTransaction(w http.ResponseWriter, r *http.Request) {
+	var req decideApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	a, err := s.store.ApproveTransaction(r.Context(), chi.URLParam(r, "id"), req.Comment)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func (s *Server) rejectTransaction(w http.ResponseWriter, r *http.Request) {
+	var req decideApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	a, err := s.store.RejectTransaction(r.Context(), chi.URLParam(r, "id"), req.Comment)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// Batch modes of POST /transactions/batch.
+const (
+	batchAtomic     = "atomic"      // all or nothing
+	batchBestEffort = "best_effort" // post what can be posted
+)
+
+// MaxBatchSize caps the transactions or accounts of one batch, since the
+// batch holds the writer lock until it commits.
+const MaxBatchSize = 5000
+
+type createTransactionsRequest struct {
+	Mode         string                     `json:"mode"` // atomic (default) or best_effort
+	Transactions []createTransactionRequest `json:"transactions"`
+}
+
+// batchResult is the outcome of one transaction of a batch, with the
+// status it would have had if posted on its own.
+type batchResult struct {
+	Status      int                 `json:"status"`
+	Transaction *ledger.Transaction `json:"transaction,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Error   string        `json:"error,omitempty"`
+	Mode    string        `json:"mode"`
+	Created int           `json:"created"`
+	Failed  int           `json:"failed"`
+	Results []batchResult `json:"results"` // in request order
+}
+
+// createTransactions posts many transactions under one writer lock. An
+// atomic batch answers 201 or, if any transaction fails, that
+// transaction's error status and posts nothing. A best_effort batch answers
+// 200 with a result per transaction.
+func (s *Server) createTransactions(w http.ResponseWriter, r *http.Request) {
+	var req createTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = batchAtomic
+	}
+	if req.Mode != batchAtomic && req.Mode != batchBestEffort {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid mode %q: use %s or %s", req.Mode, batchAtomic, batchBestEffort))
+		return
+	}
+	if len(req.Transactions) == 0 {
+		writeError(w, http.StatusBadRequest, "transactions is empty")
+		return
+	}
+	if len(req.Transactions) > MaxBatchSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("batch of %d transactions exceeds the limit of %d", len(req.Transactions), MaxBatchSize))
+		return
+	}
+
+	txns := make([]*ledger.Transaction, len(req.Transactions))
+	for i := range req.Transactions {
+		txn, err := req.Transactions[i].transaction()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("transaction %d: %s", i, err))
+			return
+		}
+		txns[i] = txn
+	}
+
+	atomic := req.Mode == batchAtomic
+	errs, err := s.store.CreateTransactions(r.Context(), txns, atomic)
+	resp := batchResponse{Mode: req.Mode, Results: make([]batchResult, len(txns))}
+	for i, txn := range txns {
+		switch {
+		case errs[i] != nil:
+			resp.Results[i] = batchResult{Status: mapError(errs[i]), Error: errs[i].Error()}
+			resp.Failed++
+		case err != nil:
+			resp.Results[i] = batchResult{Status: http.StatusFailedDependency, Error: "not posted: the batch failed"}
+		default:
+			resp.Results[i] = batchResult{Status: createdStatus(txn), Transaction: txn}
+			resp.Created++
+		}
+	}
+
+	status := http.StatusOK
+	switch {
+	case err != nil:
+		resp.Error = err.Error()
+		status = mapError(err)
+	case atomic:
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, resp)
+}
+
+type createAccountsRequest struct {
+	Accounts []createAccountRequest `json:"accounts"`
+}
+
+// accountBatchResult is the outcome of one account of a batch, with the
+// status it would have had if created on its own.
+type accountBatchResult struct {
+	Status  int             `json:"status"`
+	Account *ledger.Account `json:"account,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type accountBatchResponse struct {
+	Error   string               `json:"error,omitempty"`
+	Created int                  `json:"created"`
+	Failed  int                  `json:"failed"`
+	Results []accountBatchResult `json:"results"` // in request order
+}
+
+// createAccounts creates many accounts under one writer lock, each on its
+// own, and answers 200 with a result per account.
+func (s *Server) createAccounts(w http.ResponseWriter, r *http.Request) {
+	var req createAccountsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if len(req.Accounts) == 0 {
+		writeError(w, http.StatusBadRequest, "accounts is empty")
+		return
+	}
+	if len(req.Accounts) > MaxBatchSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("batch of %d accounts exceeds the limit of %d", len(req.Accounts), MaxBatchSize))
+		return
+	}
+
+	accts := make([]*ledger.Account, len(req.Accounts))
+	for i := range req.Accounts {
+		acct, err := req.Accounts[i].account()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("account %d: %s", i, err))
+			return
+		}
+		accts[i] = acct
+	}
+
+	errs, err := s.store.CreateAccounts(r.Context(), accts)
+	resp := accountBatchResponse{Results: make([]accountBatchResult, len(accts))}
+	for i, acct := range accts {
+		switch {
+		case errs[i] != nil:
+			resp.Results[i] = accountBatchResult{Status: mapError(errs[i]), Error: errs[i].Error()}
+			resp.Failed++
+		case err != nil:
+			resp.Results[i] = accountBatchResult{Status: http.StatusFailedDependency, Error: "not created: the batch failed"}
+		default:
+			resp.Results[i] = accountBatchResult{Status: http.StatusCreated, Account: acct}
+			resp.Created++
+		}
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		resp.Error = err.Error()
+		status = mapError(err)
+	}
+	writeJSON(w, status, resp)
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// getLedgerInfo returns the settings fixed when the ledger was created.
+func (s *Server) getLedgerInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.Info())
+}
+
+// listCurrencies returns the currency registry; ?enabled=true lists only
+// currencies accepted for new accounts.
+func (s *Server) listCurrencies(w http.ResponseWriter, r *http.Request) {
+	defs, err := s.store.ListCurrencies(r.Context(), r.URL.Query().Get("enabled") == "true")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if defs == nil {
+		defs = []ledger.CurrencyDef{}
+	}
+	writeJSON(w, http.StatusOK, defs)
+}
+
+func (s *Server) enableCurrency(w http.ResponseWriter, r *http.Request) {
+	s.setCurrencyEnabled(w, r, true)
+}
+
+func (s *Server) disableCurrency(w http.ResponseWriter, r *http.Request) {
+	s.setCurrencyEnabled(w, r, false)
+}
+
+func (s *Server) setCurrencyEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	code := strings.ToUpper(chi.URLParam(r, "code"))
+	c, err := s.store.SetCurrencyEnabled(r.Context(), code, enabled)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
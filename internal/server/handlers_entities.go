@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+type createEntityRequest struct {
+	Name              string `json:"name"`
+	ReportingCurrency string `json:"reporting_currency"`
+}
+
+func (s *Server) createEntity(w http.ResponseWriter, r *http.Request) {
+	var req createEntityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	st, err := s.entities.Create(req.Name, store.Options{ReportingCurrency: req.ReportingCurrency})
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, ledger.Entity{Name: req.Name, ReportingCurrency: st.ReportingCurrency()})
+}
+
+func (s *Server) listEntities(w http.ResponseWriter, r *http.Request) {
+	entities, err := s.entities.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, entities)
+}
+
+// consolidatedBalanceSheet serves the balance sheet of ?entities=a,b (all
+// entities if omitted) in ?currency= as of ?as_of=, with their nostro and
+// vostro balances with each other eliminated.
+func (s *Server) consolidatedBalanceSheet(w http.ResponseWriter, r *http.Request) {
+	asOf, err := asOfParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var names []string
+	for _, name := range strings.Split(r.URL.Query().Get("entities"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		entities, err := s.entities.List()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		for _, e := range entities {
+			names = append(names, e.Name)
+		}
+	}
+	cbs, err := s.entities.ConsolidatedBalanceSheet(r.Context(), names, r.URL.Query().Get("currency"), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cbs)
+}
+
+// serveEntity routes a request under /entities/{entity} to the entity's
+// own ledger API, opening the entity on first use.
+func (s *Server) serveEntity(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "entity")
+	st, err := s.entities.Get(name)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	h, ok := s.scoped[name]
+	if !ok {
+		router := chi.NewRouter()
+		scoped := &Server{store: st, router: router}
+		s.entityBackups(scoped, name)
+		scoped.routes(router)
+		h = router
+		s.scoped[name] = h
+	}
+	s.mu.Unlock()
+
+	h.ServeHTTP(w, r)
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+type fxRateRequest struct {
+	Currency    string  `json:"currency"`
+	Rate        float64 `json:"rate"`
+	EffectiveAt string  `json:"effective_at,omitempty"` // YYYY-MM-DD or RFC 3339, defaults to now
+}
+
+func (req fxRateRequest) toRate() (ledger.FXRate, error) {
+	r := ledger.FXRate{Currency: strings.ToUpper(req.Currency), Rate: req.Rate}
+	if req.EffectiveAt != "" {
+		t, err := ledger.ParseTime(req.EffectiveAt)
+		if err != nil {
+			return r, fmt.Errorf("effective_at: %w", err)
+		}
+		r.EffectiveAt = t
+	}
+	return r, nil
+}
+
+func (s *Server) setFXRate(w http.ResponseWriter, r *http.Request) {
+	var req fxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	rate, err := req.toRate()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	created, err := s.store.SetRate(r.Context(), rate.Currency, rate.Rate, rate.EffectiveAt)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// importFXRates stores a JSON array of rates in one transaction. Rates
+// without effective_at are rejected, since an import is historical.
+func (s *Server) importFXRates(w http.ResponseWriter, r *http.Request) {
+	var reqs []fxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	rates := make([]ledger.FXRate, len(reqs))
+	for i, req := range reqs {
+		if req.EffectiveAt == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("rate %d: effective_at is required", i+1))
+			return
+		}
+		rate, err := req.toRate()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("rate %d: %s", i+1, err))
+			return
+		}
+		rates[i] = rate
+	}
+
+	if err := s.store.ImportRates(r.Context(), rates); err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]int{"imported": len(rates)})
+}
+
+func (s *Server) listFXRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := s.store.ListRates(r.Context(), strings.ToUpper(r.URL.Query().Get("currency")))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if rates == nil {
+		rates = []ledger.FXRate{}
+	}
+	writeJSON(w, http.StatusOK, rates)
+}
+
+// effectiveFXRates returns every currency's rate effective at ?as_of=
+// (default now).
+func (s *Server) effectiveFXRates(w http.ResponseWriter, r *http.Request) {
+	asOf, err := asOfParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rates, err := s.store.RatesAt(r.Context(), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rates)
+}
+
+func (s *Server) getFXRate(w http.ResponseWriter, r *http.Request) {
+	asOf, err := asOfParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rate, err := s.store.RateAt(r.Context(), strings.ToUpper(chi.URLParam(r, "currency")), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rate)
+}
+
+type revalueFXRequest struct {
+	AsOf string `json:"as_of,omitempty"` // YYYY-MM-DD (end of day) or RFC 3339, defaults to now
+}
+
+func (s *Server) revalueFX(w http.ResponseWriter, r *http.Request) {
+	var req revalueFXRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	var asOf time.Time
+	if req.AsOf != "" {
+		var err error
+		if asOf, err = ledger.ParseAsOf(req.AsOf); err != nil {
+			writeError(w, http.StatusBadRequest, "as_of: "+err.Error())
+			return
+		}
+	}
+
+	rev, err := s.store.RevalueFX(r.Context(), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, rev)
+}
+
+func (s *Server) listFXRevaluations(w http.ResponseWriter, r *http.Request) {
+	revs, err := s.store.ListFXRevaluations(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if revs == nil {
+		revs = []ledger.FXRevaluation{}
+	}
+	writeJSON(w, http.StatusOK, revs)
+}
+
+func (s *Server) getFXRevaluation(w http.ResponseWriter, r *http.Request) {
+	rev, err := s.store.GetFXRevaluation(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rev)
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+type createPeriodRequest struct {
+	ID       string `json:"id"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+func (s *Server) createPeriod(w http.ResponseWriter, r *http.Request) {
+	var req createPeriodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	p := &ledger.Period{ID: req.ID}
+	var err error
+	if p.StartsAt, err = ledger.ParseTime(req.StartsAt); err != nil {
+		writeError(w, http.StatusBadRequest, "starts_at: "+err.Error())
+		return
+	}
+	if p.EndsAt, err = ledger.ParseTime(req.EndsAt); err != nil {
+		writeError(w, http.StatusBadRequest, "ends_at: "+err.Error())
+		return
+	}
+
+	if err := s.store.CreatePeriod(r.Context(), p); err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+
+	created, err := s.store.GetPeriod(r.Context(), p.ID)
+	if err != nil {
+		writeJSON(w, http.StatusCreated, p)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) listPeriods(w http.ResponseWriter, r *http.Request) {
+	periods, err := s.store.ListPeriods(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if periods == nil {
+		periods = []ledger.Period{}
+	}
+	writeJSON(w, http.StatusOK, periods)
+}
+
+func (s *Server) getPeriod(w http.ResponseWriter, r *http.Request) {
+	p, err := s.store.GetPeriod(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+type closePeriodRequest struct {
+	// Soft moves the period to "closing" instead of locking it.
+	Soft bool `json:"soft"`
+}
+
+func (s *Server) closePeriod(w http.ResponseWriter, r *http.Request) {
+	var req closePeriodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	status := ledger.PeriodClosed
+	if req.Soft {
+		status = ledger.PeriodClosing
+	}
+	p, err := s.store.SetPeriodStatus(r.Context(), chi.URLParam(r, "id"), status)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) reopenPeriod(w http.ResponseWriter, r *http.Request) {
+	p, err := s.store.SetPeriodStatus(r.Context(), chi.URLParam(r, "id"), ledger.PeriodOpen)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+type closeYearRequest struct {
+	Year int `json:"year"`
+}
+
+func (s *Server) closeYear(w http.ResponseWriter, r *http.Request) {
+	var req closeYearRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.Year < 1 {
+		writeError(w, http.StatusBadRequest, "year is required")
+		return
+	}
+
+	closings, err := s.store.CloseYear(r.Context(), req.Year)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, closings)
+}
+
+func (s *Server) listYearClosings(w http.ResponseWriter, r *http.Request) {
+	closings, err := s.store.ListYearClosings(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if closings == nil {
+		closings = []ledger.YearClosing{}
+	}
+	writeJSON(w, http.StatusOK, closings)
+}
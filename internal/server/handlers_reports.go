@@ -1,38 +1,169 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
 )
 
 func (s *Server) balanceSheet(w http.ResponseWriter, r *http.Request) {
-	bs, err := s.store.BalanceSheet(r.Context())
+	asOf, err := asOfParam(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bs, err := s.store.BalanceSheet(r.Context(), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
 		return
 	}
 	writeJSON(w, http.StatusOK, bs)
 }
 
-func (s *Server) trialBalance(w http.ResponseWriter, r *http.Request) {
-	tb, err := s.store.TrialBalance(r.Context())
+// audit verifies the ledger's invariants. A failing audit is still a
+// successful request; the report says which checks failed.
+func (s *Server) audit(w http.ResponseWriter, r *http.Request) {
+	report, err := s.store.Verify(r.Context())
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// listAuditLog pages through the audit log, newest first, filtered by
+// actor, operation (exact or family, e.g. setting), target, from, to, limit
+// and cursor.
+func (s *Server) listAuditLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := store.AuditLogFilter{
+		Actor:     q.Get("actor"),
+		Operation: q.Get("operation"),
+		Target:    q.Get("target"),
+		Cursor:    q.Get("cursor"),
+	}
+	var err error
+	if filter.From, filter.To, err = rangeParams(r); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Limit, err = limitParam(r); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := s.store.ListAuditLog(r.Context(), filter)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// verifyChain recomputes the transaction hash chain and reports the first
+// broken link, if any.
+func (s *Server) verifyChain(w http.ResponseWriter, r *http.Request) {
+	v, err := s.store.VerifyChain(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) trialBalance(w http.ResponseWriter, r *http.Request) {
+	asOf, err := asOfParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tb, err := s.store.TrialBalance(r.Context(), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
 	writeJSON(w, http.StatusOK, tb)
 }
 
 func (s *Server) regulatoryRatios(w http.ResponseWriter, r *http.Request) {
-	ratios, err := s.store.RegulatoryRatios(r.Context())
+	asOf, err := asOfParam(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ratios, err := s.store.RegulatoryRatios(r.Context(), asOf)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
 		return
 	}
 	writeJSON(w, http.StatusOK, ratios)
 }
 
+// incomeStatement serves the P&L for ?from=&to=.
+func (s *Server) incomeStatement(w http.ResponseWriter, r *http.Request) {
+	from, to, err := rangeParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	is, err := s.store.IncomeStatement(r.Context(), from, to)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, is)
+}
+
+// cashFlowStatement serves the cash flow statement for ?from=&to=.
+func (s *Server) cashFlowStatement(w http.ResponseWriter, r *http.Request) {
+	from, to, err := rangeParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cf, err := s.store.CashFlowStatement(r.Context(), from, to)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cf)
+}
+
+// rangeParams reads the optional ?from=&to= window of the period reports.
+// A bare date in from means the start of that day and in to the end of it.
+func rangeParams(r *http.Request) (from, to time.Time, err error) {
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = ledger.ParseTime(v); err != nil {
+			return from, to, fmt.Errorf("from: %w", err)
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = ledger.ParseAsOf(v); err != nil {
+			return from, to, fmt.Errorf("to: %w", err)
+		}
+	}
+	return from, to, nil
+}
+
+// asOfParam reads the optional ?as_of= bound shared by the report routes.
+// It returns the zero time when absent, meaning the current state.
+func asOfParam(r *http.Request) (time.Time, error) {
+	v := r.URL.Query().Get("as_of")
+	if v == "" {
+		return time.Time{}, nil
+	}
+	asOf, err := ledger.ParseAsOf(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("as_of: %w", err)
+	}
+	return asOf, nil
+}
+
 func (s *Server) getChart(w http.ResponseWriter, r *http.Request) {
 	all := make([]ledger.ChartEntry, 0, len(ledger.PredefinedAccounts)+len(ledger.SystemAccounts))
 	all = append(all, ledger.PredefinedAccounts...)
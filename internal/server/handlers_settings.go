@@ -50,7 +50,7 @@ func (s *Server) upsertSetting(w http.ResponseWriter, r *http.Request) {
 	}
 
 	setting := ledger.SettingName(chi.URLParam(r, "setting"))
-	if setting != ledger.SettingBlockInverted && setting != ledger.SettingEntryDirection {
+	if setting != ledger.SettingBlockInverted && setting != ledger.SettingEntryDirection && setting != ledger.SettingApprovalThreshold {
 		writeError(w, http.StatusBadRequest, "unknown setting: "+string(setting))
 		return
 	}
@@ -75,6 +75,11 @@ func (s *Server) upsertSetting(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "ENTRY_DIRECTION must be BOTH, DEBIT_ONLY, or CREDIT_ONLY")
 			return
 		}
+	case ledger.SettingApprovalThreshold:
+		if n, err := strconv.ParseInt(req.Value, 10, 64); err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "APPROVAL_THRESHOLD must be a non-negative amount in minor units")
+			return
+		}
 	}
 
 	cs := ledger.CoASetting{Code: code, Setting: setting, Value: req.Value}
@@ -94,7 +99,7 @@ func (s *Server) deleteSetting(w http.ResponseWriter, r *http.Request) {
 	}
 
 	setting := ledger.SettingName(chi.URLParam(r, "setting"))
-	if setting != ledger.SettingBlockInverted && setting != ledger.SettingEntryDirection {
+	if setting != ledger.SettingBlockInverted && setting != ledger.SettingEntryDirection && setting != ledger.SettingApprovalThreshold {
 		writeError(w, http.StatusBadRequest, "unknown setting: "+string(setting))
 		return
 	}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/simonvc/miniledger/internal/ledger"
@@ -10,8 +13,12 @@ import (
 )
 
 type createTransactionRequest struct {
-	Description string `json:"description"`
-	Entries     []struct {
+	Description    string `json:"description"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	PostedAt       string `json:"posted_at,omitempty"` // optional backdating, YYYY-MM-DD or RFC 3339
+	Pending        bool   `json:"pending,omitempty"`    // place a hold; finalize or void it later
+	ExpiresAt      string `json:"expires_at,omitempty"` // hold expiry, defaults to ledger.DefaultHoldTTL
+	Entries        []struct {
 		AccountID string `json:"account_id"`
 		Amount    int64  `json:"amount"`
 		Currency  string `json:"currency"`
@@ -25,15 +32,15 @@ func (s *Server) createTransaction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn := &ledger.Transaction{
-		Description: req.Description,
+	// The Idempotency-Key header takes precedence over the body field.
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		req.IdempotencyKey = key
 	}
-	for _, e := range req.Entries {
-		txn.Entries = append(txn.Entries, ledger.Entry{
-			AccountID: e.AccountID,
-			Amount:    e.Amount,
-			Currency:  e.Currency,
-		})
+
+	txn, err := req.transaction()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	if err := s.store.CreateTransaction(r.Context(), txn); err != nil {
@@ -41,30 +48,135 @@ func (s *Server) createTransaction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	status := createdStatus(txn)
+
 	// Fetch back the full transaction
 	created, err := s.store.GetTransaction(r.Context(), txn.ID)
 	if err != nil {
-		writeJSON(w, http.StatusCreated, txn)
+		writeJSON(w, status, txn)
 		return
 	}
-	writeJSON(w, http.StatusCreated, created)
+	writeJSON(w, status, created)
+}
+
+// transaction converts the request into an unsaved transaction.
+func (req *createTransactionRequest) transaction() (*ledger.Transaction, error) {
+	txn := &ledger.Transaction{
+		Description:    req.Description,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	if req.PostedAt != "" {
+		postedAt, err := ledger.ParseTime(req.PostedAt)
+		if err != nil {
+			return nil, fmt.Errorf("posted_at: %w", err)
+		}
+		txn.PostedAt = postedAt
+	}
+	if req.Pending {
+		txn.Status = ledger.StatusPending
+	}
+	if req.ExpiresAt != "" {
+		if !req.Pending {
+			return nil, fmt.Errorf("expires_at requires pending")
+		}
+		expiresAt, err := ledger.ParseTime(req.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("expires_at: %w", err)
+		}
+		txn.ExpiresAt = &expiresAt
+	}
+	for _, e := range req.Entries {
+		txn.Entries = append(txn.Entries, ledger.Entry{
+			AccountID: e.AccountID,
+			Amount:    e.Amount,
+			Currency:  e.Currency,
+		})
+	}
+	return txn, nil
+}
+
+// createdStatus is the status of a created transaction: a transaction
+// parked for approval is accepted but not yet posted.
+func createdStatus(txn *ledger.Transaction) int {
+	if txn.ApprovalStatus == ledger.ApprovalPending {
+		return http.StatusAccepted
+	}
+	return http.StatusCreated
 }
 
 func (s *Server) listTransactions(w http.ResponseWriter, r *http.Request) {
-	filter := store.TxnFilter{}
-	if aid := r.URL.Query().Get("account_id"); aid != "" {
-		filter.AccountID = aid
+	filter, err := txnFilterParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	txns, err := s.store.ListTransactions(r.Context(), filter)
+	page, err := s.store.ListTransactions(r.Context(), filter)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, mapError(err), err.Error())
 		return
 	}
-	if txns == nil {
-		txns = []ledger.Transaction{}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// txnFilterParams reads the transaction listing query: account_id, from,
+// to, q (description), currency, code, min_amount, max_amount (minor
+// units), pending, limit and cursor.
+func txnFilterParams(r *http.Request) (store.TxnFilter, error) {
+	q := r.URL.Query()
+	filter := store.TxnFilter{
+		AccountID:   q.Get("account_id"),
+		Description: q.Get("q"),
+		Currency:    q.Get("currency"),
+		Cursor:      q.Get("cursor"),
+		Pending:     q.Get("pending") == "true",
+	}
+
+	var err error
+	if filter.From, filter.To, err = rangeParams(r); err != nil {
+		return filter, err
+	}
+	if filter.MinAmount, err = amountParam(r, "min_amount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = amountParam(r, "max_amount"); err != nil {
+		return filter, err
+	}
+	if v := q.Get("code"); v != "" {
+		if filter.Code, err = strconv.Atoi(v); err != nil {
+			return filter, fmt.Errorf("invalid code: %q", v)
+		}
 	}
-	writeJSON(w, http.StatusOK, txns)
+	if filter.Limit, err = limitParam(r); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// amountParam reads an optional non-negative amount in minor units.
+func amountParam(r *http.Request, name string) (int64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, v)
+	}
+	return n, nil
+}
+
+// limitParam reads the optional ?limit= page size.
+func limitParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid limit: %q", v)
+	}
+	return n, nil
 }
 
 func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request) {
@@ -76,3 +188,51 @@ func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request) {
 	}
 	writeJSON(w, http.StatusOK, txn)
 }
+
+type reverseTransactionRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (s *Server) reverseTransaction(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	// The body is optional; an empty POST reverses without a reason.
+	var req reverseTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	rev, err := s.store.ReverseTransaction(r.Context(), id, req.Reason)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+
+	created, err := s.store.GetTransaction(r.Context(), rev.ID)
+	if err != nil {
+		writeJSON(w, createdStatus(rev), rev)
+		return
+	}
+	writeJSON(w, createdStatus(created), created)
+}
+
+// finalizeTransaction captures a pending transaction.
+func (s *Server) finalizeTransaction(w http.ResponseWriter, r *http.Request) {
+	txn, err := s.store.FinalizeTransaction(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, txn)
+}
+
+// voidTransaction releases a pending transaction without posting it.
+func (s *Server) voidTransaction(w http.ResponseWriter, r *http.Request) {
+	txn, err := s.store.VoidTransaction(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, txn)
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type createWebhookRequest struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"` // optional; generated when empty
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var req createWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+
+	hook, err := s.store.CreateWebhook(r.Context(), req.URL, req.Secret)
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, hook)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := s.store.ListWebhooks(r.Context())
+	if err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, hooks)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if _, err := s.store.DeleteWebhook(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeError(w, mapError(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const (
+	// eventsPollInterval is how often an event stream checks the outbox.
+	eventsPollInterval = time.Second
+	// eventsHeartbeat is how often an idle stream sends a comment so
+	// proxies keep the connection open.
+	eventsHeartbeat = 15 * time.Second
+)
+
+// streamEvents serves the outbox as server-sent events. It starts after
+// the event given by the Last-Event-ID header or ?after=, or with the next
+// new event when neither is given.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	ctx := r.Context()
+	after := r.Header.Get("Last-Event-ID")
+	if after == "" {
+		after = r.URL.Query().Get("after")
+	}
+	var last int64
+	if after != "" {
+		n, err := strconv.ParseInt(after, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid event id: "+after)
+			return
+		}
+		last = n
+	} else {
+		n, err := s.store.LastEventID(ctx)
+		if err != nil {
+			writeError(w, mapError(err), err.Error())
+			return
+		}
+		last = n
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	poll := time.NewTicker(eventsPollInterval)
+	defer poll.Stop()
+	idleSince := time.Now()
+	for {
+		events, err := s.store.ListEvents(ctx, last, 0)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", err.Error())
+				flusher.Flush()
+			}
+			return
+		}
+		for _, e := range events {
+			data, _ := json.Marshal(e)
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+			last = e.ID
+		}
+		if len(events) > 0 {
+			flusher.Flush()
+			idleSince = time.Now()
+			continue
+		}
+		if time.Since(idleSince) >= eventsHeartbeat {
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+			idleSince = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		}
+	}
+}
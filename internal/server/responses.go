@@ -24,9 +24,30 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 
 func mapError(err error) int {
 	switch {
-	case errors.Is(err, ledger.ErrAccountNotFound), errors.Is(err, ledger.ErrTransactionNotFound):
+	case errors.Is(err, ledger.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ledger.ErrForbidden), errors.Is(err, ledger.ErrSelfApproval), errors.Is(err, ledger.ErrAuthRequired):
+		return http.StatusForbidden
+	case errors.Is(err, ledger.ErrAccountNotFound), errors.Is(err, ledger.ErrTransactionNotFound),
+		errors.Is(err, ledger.ErrPeriodNotFound), errors.Is(err, ledger.ErrRateNotFound),
+		errors.Is(err, ledger.ErrRevaluationNotFound), errors.Is(err, ledger.ErrEntityNotFound),
+		errors.Is(err, ledger.ErrAPIKeyNotFound), errors.Is(err, ledger.ErrApprovalNotFound),
+		errors.Is(err, ledger.ErrWebhookNotFound), errors.Is(err, ledger.ErrBackupsDisabled):
 		return http.StatusNotFound
-	case errors.Is(err, ledger.ErrDuplicateAccount):
+	case errors.Is(err, ledger.ErrDuplicateAccount),
+		errors.Is(err, ledger.ErrAlreadyReversed),
+		errors.Is(err, ledger.ErrIdempotencyConflict),
+		errors.Is(err, ledger.ErrPeriodOverlap),
+		errors.Is(err, ledger.ErrInvalidPeriodTransition),
+		errors.Is(err, ledger.ErrYearAlreadyClosed),
+		errors.Is(err, ledger.ErrYearOutOfOrder),
+		errors.Is(err, ledger.ErrRevaluationOutOfOrder),
+		errors.Is(err, ledger.ErrEntityExists),
+		errors.Is(err, ledger.ErrNotPending),
+		errors.Is(err, ledger.ErrTransactionPending),
+		errors.Is(err, ledger.ErrNotAwaitingApproval),
+		errors.Is(err, ledger.ErrHoldExpired),
+		errors.Is(err, ledger.ErrBackupExists):
 		return http.StatusConflict
 	case errors.Is(err, ledger.ErrUnbalancedTransaction),
 		errors.Is(err, ledger.ErrTooFewEntries),
@@ -36,12 +57,24 @@ func mapError(err error) int {
 		errors.Is(err, ledger.ErrInvalidCategory),
 		errors.Is(err, ledger.ErrCodeCategoryMismatch),
 		errors.Is(err, ledger.ErrInvalidCurrency),
+		errors.Is(err, ledger.ErrInvalidAmount),
 		errors.Is(err, ledger.ErrCurrencyMismatch),
 		errors.Is(err, ledger.ErrSystemAccountPrefix),
-		errors.Is(err, ledger.ErrNonSystemAccountTilde):
+		errors.Is(err, ledger.ErrNonSystemAccountTilde),
+		errors.Is(err, ledger.ErrInvalidPeriod),
+		errors.Is(err, ledger.ErrInvalidCursor),
+		errors.Is(err, ledger.ErrInvalidExpiry),
+		errors.Is(err, ledger.ErrInvalidRate),
+		errors.Is(err, ledger.ErrInvalidEntity),
+		errors.Is(err, ledger.ErrInvalidRole),
+		errors.Is(err, ledger.ErrInvalidWebhook):
 		return http.StatusBadRequest
 	case errors.Is(err, ledger.ErrInvertedBalance),
-		errors.Is(err, ledger.ErrEntryDirectionViolation):
+		errors.Is(err, ledger.ErrEntryDirectionViolation),
+		errors.Is(err, ledger.ErrPeriodClosed),
+		errors.Is(err, ledger.ErrNothingToClose),
+		errors.Is(err, ledger.ErrNothingToRevalue),
+		errors.Is(err, ledger.ErrNoRetainedEarnings):
 		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
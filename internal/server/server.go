@@ -1,70 +1,199 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/simonvc/miniledger/internal/ledger"
 	"github.com/simonvc/miniledger/internal/store"
 )
 
 type Server struct {
-	store  *store.Store
-	router chi.Router
-	addr   string
+	store    *store.Store
+	entities *store.Registry
+	router   chi.Router
+	addr     string
+	auth     bool // require an API key on every request
+
+	// Backups; see EnableBackups.
+	backupDir   string
+	backupName  string // file name prefix of this ledger's backups
+	backupKeep  int
+	backupEvery time.Duration
+
+	mu     sync.Mutex
+	scoped map[string]http.Handler // per-entity routers, by entity name
 }
 
-func New(st *store.Store, addr string) *Server {
+// New serves the default ledger st under /api/v1 and each entity in
+// entities under /api/v1/entities/{entity}. A nil registry serves the
+// default ledger only.
+func New(st *store.Store, entities *store.Registry, addr string) *Server {
 	r := chi.NewRouter()
+	s := &Server{store: st, entities: entities, router: r, addr: addr, scoped: map[string]http.Handler{}}
 	r.Use(middleware.Recoverer)
-
-	s := &Server{store: st, router: r, addr: addr}
+	r.Use(s.authenticate)
 
 	r.Route("/api/v1", func(r chi.Router) {
-		// Accounts
-		r.Post("/accounts", s.createAccount)
-		r.Get("/accounts", s.listAccounts)
-		r.Get("/accounts/{id}", s.getAccount)
-		r.Get("/accounts/{id}/balance", s.getAccountBalance)
-		r.Get("/accounts/{id}/entries", s.listAccountEntries)
-		r.Patch("/accounts/{id}", s.renameAccount)
-		r.Delete("/accounts/{id}", s.deleteAccount)
-
-		// Transactions
-		r.Post("/transactions", s.createTransaction)
-		r.Get("/transactions", s.listTransactions)
-		r.Get("/transactions/{id}", s.getTransaction)
-
-		// Reports
-		r.Get("/reports/balance-sheet", s.balanceSheet)
-		r.Get("/reports/trial-balance", s.trialBalance)
-		r.Get("/reports/ratios", s.regulatoryRatios)
-
-		// Chart of accounts reference
-		r.Get("/chart", s.getChart)
-
-		// CoA code settings
-		r.Get("/settings", s.listSettings)
-		r.Get("/settings/{code}", s.getCodeSettings)
-		r.Put("/settings/{code}/{setting}", s.upsertSetting)
-		r.Delete("/settings/{code}/{setting}", s.deleteSetting)
+		s.routes(r)
+
+		if entities != nil {
+			// Entities, each a ledger with the same routes as the default one
+			r.With(require(ledger.RoleAdmin)).Post("/entities", s.createEntity)
+			r.Get("/entities", s.listEntities)
+			r.Get("/reports/consolidated-balance-sheet", s.consolidatedBalanceSheet)
+			r.Mount("/entities/{entity}", http.HandlerFunc(s.serveEntity))
+		}
 	})
 
 	return s
 }
 
+// routes registers the ledger API of s.store on r.
+func (s *Server) routes(r chi.Router) {
+	// Accounts
+	r.Post("/accounts", s.createAccount)
+	r.Post("/accounts/batch", s.createAccounts)
+	r.Get("/accounts", s.listAccounts)
+	r.Get("/accounts/{id}", s.getAccount)
+	r.Get("/accounts/{id}/balance", s.getAccountBalance)
+	r.Get("/accounts/{id}/entries", s.listAccountEntries)
+	r.Get("/accounts/{id}/statement", s.getAccountStatement)
+	r.Patch("/accounts/{id}", s.renameAccount)
+	r.With(require(ledger.RoleAdmin)).Delete("/accounts/{id}", s.deleteAccount)
+
+	// Transactions
+	r.Post("/transactions", s.createTransaction)
+	r.Post("/transactions/batch", s.createTransactions)
+	r.Get("/transactions", s.listTransactions)
+	r.Get("/transactions/{id}", s.getTransaction)
+	r.Post("/transactions/{id}/reverse", s.reverseTransaction)
+	r.Post("/transactions/{id}/finalize", s.finalizeTransaction)
+	r.Post("/transactions/{id}/void", s.voidTransaction)
+
+	// Approvals
+	r.Get("/approvals", s.listApprovals)
+	r.Get("/approvals/{id}", s.getApproval)
+	r.With(requireKey, require(ledger.RoleAdmin)).Post("/approvals/{id}/approve", s.approveTransaction)
+	r.With(requireKey, require(ledger.RoleAdmin)).Post("/approvals/{id}/reject", s.rejectTransaction)
+
+	// Reports
+	r.Get("/reports/balance-sheet", s.balanceSheet)
+	r.Get("/reports/trial-balance", s.trialBalance)
+	r.Get("/reports/ratios", s.regulatoryRatios)
+	r.Get("/reports/income-statement", s.incomeStatement)
+	r.Get("/reports/cash-flow", s.cashFlowStatement)
+
+	// Accounting periods
+	r.With(require(ledger.RoleAdmin)).Post("/periods", s.createPeriod)
+	r.Get("/periods", s.listPeriods)
+	r.Get("/periods/{id}", s.getPeriod)
+	r.With(require(ledger.RoleAdmin)).Post("/periods/{id}/close", s.closePeriod)
+	r.With(require(ledger.RoleAdmin)).Post("/periods/{id}/reopen", s.reopenPeriod)
+	r.With(require(ledger.RoleAdmin)).Post("/closings", s.closeYear)
+	r.Get("/closings", s.listYearClosings)
+
+	// FX rates to the reporting currency
+	r.Post("/fx/rates", s.setFXRate)
+	r.Post("/fx/rates/import", s.importFXRates)
+	r.Get("/fx/rates", s.listFXRates)
+	r.Get("/fx/rates/effective", s.effectiveFXRates)
+	r.Get("/fx/rates/{currency}", s.getFXRate)
+	r.With(require(ledger.RoleAdmin)).Post("/fx/revaluations", s.revalueFX)
+	r.Get("/fx/revaluations", s.listFXRevaluations)
+	r.Get("/fx/revaluations/{id}", s.getFXRevaluation)
+
+	// Audit log, invariant audit and hash chain
+	r.Get("/audit", s.listAuditLog)
+	r.Get("/audit/invariants", s.audit)
+	r.Get("/audit/chain", s.verifyChain)
+
+	// Event stream and webhooks
+	r.Get("/events", s.streamEvents)
+	r.With(require(ledger.RoleAdmin)).Post("/webhooks", s.createWebhook)
+	r.With(require(ledger.RoleAdmin)).Get("/webhooks", s.listWebhooks)
+	r.With(require(ledger.RoleAdmin)).Delete("/webhooks/{id}", s.deleteWebhook)
+
+	// Chart of accounts reference
+	r.Get("/chart", s.getChart)
+
+	// Online backups
+	r.With(require(ledger.RoleAdmin)).Post("/admin/backup", s.backup)
+
+	// Ledger-wide settings
+	r.Get("/ledger", s.getLedgerInfo)
+
+	// Currency registry
+	r.Get("/currencies", s.listCurrencies)
+	r.With(require(ledger.RoleAdmin)).Post("/currencies/{code}/enable", s.enableCurrency)
+	r.With(require(ledger.RoleAdmin)).Post("/currencies/{code}/disable", s.disableCurrency)
+
+	// CoA code settings
+	r.Get("/settings", s.listSettings)
+	r.Get("/settings/{code}", s.getCodeSettings)
+	r.With(require(ledger.RoleAdmin)).Put("/settings/{code}/{setting}", s.upsertSetting)
+	r.With(require(ledger.RoleAdmin)).Delete("/settings/{code}/{setting}", s.deleteSetting)
+}
+
 func (s *Server) ListenAndServe() error {
 	log.Printf("miniledger server listening on %s", s.addr)
+	go s.expirePending()
+	go s.dispatchWebhooks()
+	if s.backupDir != "" && s.backupEvery > 0 {
+		go s.scheduleBackups()
+	}
 	return http.ListenAndServe(s.addr, s.router)
 }
 
 func (s *Server) Serve(ln net.Listener) error {
 	log.Printf("miniledger server listening on %s", ln.Addr())
+	go s.expirePending()
+	go s.dispatchWebhooks()
+	if s.backupDir != "" && s.backupEvery > 0 {
+		go s.scheduleBackups()
+	}
 	return http.Serve(ln, s.router)
 }
 
+// expirePendingInterval is how often stale holds are swept. Balances
+// ignore expired holds immediately; the sweep only records their status.
+const expirePendingInterval = time.Minute
+
+// expirePending periodically releases pending transactions past their
+// expiry, in the default ledger and every entity opened so far.
+func (s *Server) expirePending() {
+	ticker := time.NewTicker(expirePendingInterval)
+	defer ticker.Stop()
+	for {
+		expirePendingIn("", s.store)
+		if s.entities != nil {
+			for name, st := range s.entities.Loaded() {
+				expirePendingIn(name, st)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+func expirePendingIn(entity string, st *store.Store) {
+	prefix := ""
+	if entity != "" {
+		prefix = "entity " + entity + ": "
+	}
+	n, err := st.ExpirePending(context.Background())
+	if err != nil {
+		log.Printf("%sexpire pending transactions: %v", prefix, err)
+	} else if n > 0 {
+		log.Printf("%sexpired %d pending transaction(s)", prefix, n)
+	}
+}
+
 func (s *Server) Handler() http.Handler {
 	return s.router
 }
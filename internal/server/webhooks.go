@@ -0,0 +1,190 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	EventHeader     = "X-Miniledger-Event"
+	DeliveryHeader  = "X-Miniledger-Delivery" // the event ID; redeliveries repeat it
+	SignatureHeader = "X-Miniledger-Signature"
+)
+
+const (
+	// webhookInterval is how often due deliveries are looked for.
+	webhookInterval = 2 * time.Second
+	// webhookTimeout bounds one delivery attempt.
+	webhookTimeout = 10 * time.Second
+	// webhookMaxAttempts is how many times a delivery is tried before it is
+	// marked failed.
+	webhookMaxAttempts = 10
+	// webhookBatch is how many due deliveries per webhook are fetched at a
+	// time.
+	webhookBatch = 100
+)
+
+// webhookBackoff is the wait after the given number of failed attempts:
+// 5s doubling each time, capped at an hour.
+func webhookBackoff(attempts int) time.Duration {
+	d := 5 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
+
+// dispatchWebhooks periodically delivers outbox events to webhooks, for
+// the default ledger and every entity opened so far.
+func (s *Server) dispatchWebhooks() {
+	wd := newWebhookDispatcher(&http.Client{Timeout: webhookTimeout})
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+	for {
+		wd.dispatch(context.Background(), "", s.store)
+		if s.entities != nil {
+			for name, st := range s.entities.Loaded() {
+				wd.dispatch(context.Background(), name, st)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// webhookDispatcher delivers to each webhook from its own goroutine, so a
+// slow or unreachable endpoint only holds up its own deliveries.
+type webhookDispatcher struct {
+	client *http.Client
+
+	mu   sync.Mutex
+	busy map[string]bool // "entity/webhook ID" of deliveries in flight
+}
+
+func newWebhookDispatcher(client *http.Client) *webhookDispatcher {
+	return &webhookDispatcher{client: client, busy: map[string]bool{}}
+}
+
+// dispatch starts delivering st's due deliveries, one goroutine per
+// webhook, skipping webhooks still busy with an earlier dispatch. It
+// returns a func that waits for the goroutines it started and reports how
+// many deliveries they made.
+func (wd *webhookDispatcher) dispatch(ctx context.Context, entity string, st *store.Store) (wait func() int) {
+	prefix := ""
+	if entity != "" {
+		prefix = "entity " + entity + ": "
+	}
+	var wg sync.WaitGroup
+	var delivered atomic.Int64
+	wait = func() int {
+		wg.Wait()
+		return int(delivered.Load())
+	}
+
+	due, err := st.DueDeliveries(ctx, webhookBatch)
+	if err != nil {
+		log.Printf("%swebhook deliveries: %v", prefix, err)
+		return wait
+	}
+	var hooks []string
+	byHook := map[string][]store.WebhookDelivery{}
+	for _, d := range due {
+		if byHook[d.WebhookID] == nil {
+			hooks = append(hooks, d.WebhookID)
+		}
+		byHook[d.WebhookID] = append(byHook[d.WebhookID], d)
+	}
+
+	for _, id := range hooks {
+		key := entity + "/" + id
+		wd.mu.Lock()
+		if wd.busy[key] {
+			wd.mu.Unlock()
+			continue
+		}
+		wd.busy[key] = true
+		wd.mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delivered.Add(int64(wd.deliverAll(ctx, prefix, entity, st, byHook[id])))
+			wd.mu.Lock()
+			delete(wd.busy, key)
+			wd.mu.Unlock()
+		}()
+	}
+	return wait
+}
+
+// deliverAll attempts one webhook's due deliveries in event order,
+// recording each success, and returns how many were delivered. It stops at
+// the first failure and schedules its retry; the rest wait behind it until
+// it is delivered or given up on, so the webhook never sees a later event
+// before an earlier one.
+func (wd *webhookDispatcher) deliverAll(ctx context.Context, prefix, entity string, st *store.Store, due []store.WebhookDelivery) int {
+	delivered := 0
+	for _, d := range due {
+		d.Event.Entity = entity
+		if err := deliver(ctx, wd.client, d); err != nil {
+			attempts := d.Attempts + 1
+			var retryAt time.Time
+			if attempts < webhookMaxAttempts {
+				retryAt = time.Now().Add(webhookBackoff(attempts))
+			} else {
+				log.Printf("%swebhook %s: giving up on event %d after %d attempts: %v", prefix, d.WebhookID, d.Event.ID, attempts, err)
+			}
+			if err := st.MarkFailed(ctx, d, err.Error(), retryAt); err != nil {
+				log.Printf("%swebhook %s: %v", prefix, d.WebhookID, err)
+			}
+			return delivered
+		}
+		if err := st.MarkDelivered(ctx, d); err != nil {
+			log.Printf("%swebhook %s: %v", prefix, d.WebhookID, err)
+			return delivered
+		}
+		delivered++
+	}
+	return delivered
+}
+
+// deliver POSTs d's event to its webhook, signed with the webhook's
+// secret. Any 2xx response counts as delivered.
+func deliver(ctx context.Context, client *http.Client, d store.WebhookDelivery) error {
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "miniledger-webhook")
+	req.Header.Set(EventHeader, string(d.Event.Type))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.Event.ID, 10))
+	req.Header.Set(SignatureHeader, ledger.SignWebhook(d.Secret, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+	"github.com/simonvc/miniledger/internal/store"
+)
+
+// receiver is a webhook endpoint that records what it is sent.
+type receiver struct {
+	mu     sync.Mutex
+	status int
+	reqs   []*http.Request
+	bodies [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.reqs = append(rc.reqs, r)
+	rc.bodies = append(rc.bodies, body)
+	w.WriteHeader(rc.status)
+}
+
+func postTestTransaction(t *testing.T, st *store.Store) *ledger.Transaction {
+	t.Helper()
+	txn := &ledger.Transaction{
+		Description: "webhook test",
+		Entries: []ledger.Entry{
+			{AccountID: "~settlement", Amount: 2500, Currency: "USD"},
+			{AccountID: "~tax", Amount: -2500, Currency: "USD"},
+		},
+	}
+	if err := st.CreateTransaction(context.Background(), txn); err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+	return txn
+}
+
+func TestDeliverDue(t *testing.T) {
+	ctx := context.Background()
+	st, err := store.Open(filepath.Join(t.TempDir(), "ledger.db"), store.Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	ok := &receiver{status: http.StatusNoContent}
+	okSrv := httptest.NewServer(ok)
+	defer okSrv.Close()
+	down := &receiver{status: http.StatusServiceUnavailable}
+	downSrv := httptest.NewServer(down)
+	defer downSrv.Close()
+
+	hook, err := st.CreateWebhook(ctx, okSrv.URL, "")
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if _, err := st.CreateWebhook(ctx, downSrv.URL, "s3cret"); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	txn := postTestTransaction(t, st)
+
+	wd := newWebhookDispatcher(okSrv.Client())
+	if n := wd.dispatch(ctx, "acme", st)(); n != 1 {
+		t.Fatalf("delivered %d, want 1", n)
+	}
+	if len(ok.reqs) != 1 || len(down.reqs) != 1 {
+		t.Fatalf("receivers got %d and %d requests, want 1 each", len(ok.reqs), len(down.reqs))
+	}
+
+	req, body := ok.reqs[0], ok.bodies[0]
+	if got, want := req.Header.Get(SignatureHeader), ledger.SignWebhook(hook.Secret, body); got != want {
+		t.Errorf("signature %q, want %q", got, want)
+	}
+	if got := req.Header.Get(EventHeader); got != string(ledger.EventTransactionPosted) {
+		t.Errorf("event header %q", got)
+	}
+	var event ledger.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	var posted ledger.Transaction
+	if err := json.Unmarshal(event.Data, &posted); err != nil {
+		t.Fatalf("decode event data: %v", err)
+	}
+	if event.Entity != "acme" || posted.ID != txn.ID || len(posted.Entries) != 2 || posted.Hash == "" {
+		t.Errorf("event = %+v, data = %+v", event, posted)
+	}
+
+	// The failed delivery waits for its backoff instead of being retried at once.
+	if n := wd.dispatch(ctx, "acme", st)(); n != 0 || len(down.reqs) != 1 {
+		t.Errorf("retried before backoff: delivered %d, %d requests", n, len(down.reqs))
+	}
+	hooks, err := st.ListWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("ListWebhooks: %v", err)
+	}
+	for _, h := range hooks {
+		want := [3]int{1, 0, 0} // delivered, pending, failed
+		if h.URL == downSrv.URL {
+			want = [3]int{0, 1, 0}
+		}
+		if got := [3]int{h.Delivered, h.Pending, h.Failed}; got != want {
+			t.Errorf("webhook %s counts %v, want %v", h.URL, got, want)
+		}
+	}
+}
+
+// A webhook that is slow to answer must not hold up the others.
+func TestDispatchPerWebhook(t *testing.T) {
+	ctx := context.Background()
+	st, err := store.Open(filepath.Join(t.TempDir(), "ledger.db"), store.Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	release := make(chan struct{})
+	var slowReqs atomic.Int32
+	slowSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slowReqs.Add(1)
+		<-release
+	}))
+	defer slowSrv.Close()
+	ok := &receiver{status: http.StatusOK}
+	okSrv := httptest.NewServer(ok)
+	defer okSrv.Close()
+
+	for _, u := range []string{slowSrv.URL, okSrv.URL} {
+		if _, err := st.CreateWebhook(ctx, u, ""); err != nil {
+			t.Fatalf("CreateWebhook: %v", err)
+		}
+	}
+	postTestTransaction(t, st)
+	postTestTransaction(t, st)
+
+	wd := newWebhookDispatcher(&http.Client{Timeout: time.Minute})
+	wait := wd.dispatch(ctx, "", st)
+	defer func() {
+		close(release)
+		wait()
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ok.mu.Lock()
+		n := len(ok.reqs)
+		ok.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("fast webhook got %d of 2 events while the slow one was blocked", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The slow webhook is still busy with its first event, so a second
+	// dispatch leaves it alone.
+	wd.dispatch(ctx, "", st)()
+	if n := slowReqs.Load(); n != 1 {
+		t.Errorf("slow webhook got %d requests, want 1", n)
+	}
+}
+
+// Events after a failed delivery wait behind it, so the webhook receives
+// them in order once the retry goes through.
+func TestDeliverInOrderAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	st, err := store.Open(filepath.Join(t.TempDir(), "ledger.db"), store.Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	var mu sync.Mutex
+	var arrived []string // delivery IDs, in arrival order
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		arrived = append(arrived, r.Header.Get(DeliveryHeader))
+		if len(arrived) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	hook, err := st.CreateWebhook(ctx, srv.URL, "")
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	for range 3 {
+		postTestTransaction(t, st)
+	}
+
+	wd := newWebhookDispatcher(srv.Client())
+	if n := wd.dispatch(ctx, "", st)(); n != 0 {
+		t.Fatalf("delivered %d with the first event failing, want 0", n)
+	}
+	// The first event is backing off, and the later ones are due but must
+	// not overtake it.
+	if n := wd.dispatch(ctx, "", st)(); n != 0 || len(arrived) != 1 {
+		t.Fatalf("delivered %d (%d requests) while the first event backs off, want none", n, len(arrived))
+	}
+
+	// Make the retry due.
+	first, _ := strconv.ParseInt(arrived[0], 10, 64)
+	retry := store.WebhookDelivery{WebhookID: hook.ID, Event: ledger.Event{ID: first}}
+	if err := st.MarkFailed(ctx, retry, "test", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	if n := wd.dispatch(ctx, "", st)(); n != 3 {
+		t.Fatalf("delivered %d after the retry, want 3", n)
+	}
+	want := []string{arrived[0], arrived[0], strconv.FormatInt(first+1, 10), strconv.FormatInt(first+2, 10)}
+	if !slices.Equal(arrived, want) {
+		t.Errorf("deliveries arrived in order %v, want %v", arrived, want)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{9, 1280 * time.Second},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -11,18 +11,50 @@ import (
 )
 
 func (s *Store) CreateAccount(ctx context.Context, acct *ledger.Account) error {
+	if err := s.checkAccount(ctx, acct); err != nil {
+		return err
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertAccount(ctx, tx, acct); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkAccount validates acct and checks that its currency is enabled.
+func (s *Store) checkAccount(ctx context.Context, acct *ledger.Account) error {
 	if err := acct.Validate(); err != nil {
 		return err
 	}
+	if acct.Currency != "*" {
+		return s.requireEnabled(ctx, acct.Currency)
+	}
+	return nil
+}
 
-	_, err := s.writer.ExecContext(ctx,
+// insertAccount creates acct in tx, setting its CreatedAt, and records it
+// in the audit log.
+func insertAccount(ctx context.Context, tx *sql.Tx, acct *ledger.Account) error {
+	_, err := tx.ExecContext(ctx,
 		`INSERT INTO accounts (id, name, code, category, currency, is_system) VALUES (?, ?, ?, ?, ?, ?)`,
 		acct.ID, acct.Name, acct.Code, string(acct.Category), acct.Currency, boolToInt(acct.IsSystem),
 	)
 	if err != nil {
 		return fmt.Errorf("insert account: %w", err)
 	}
-	return nil
+	created, err := scanAccount(tx.QueryRowContext(ctx,
+		`SELECT id, name, code, category, currency, is_system, created_at FROM accounts WHERE id = ?`, acct.ID))
+	if err != nil {
+		return err
+	}
+	acct.CreatedAt = created.CreatedAt
+	return logChange(ctx, tx, ledger.OpAccountCreate, acct.ID, nil, created)
 }
 
 func (s *Store) GetAccount(ctx context.Context, id string) (*ledger.Account, error) {
@@ -74,27 +106,46 @@ func (s *Store) RenameAccount(ctx context.Context, id, newName string) error {
 	if strings.TrimSpace(newName) == "" {
 		return fmt.Errorf("name cannot be empty")
 	}
-	_, err := s.GetAccount(ctx, id)
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := scanAccount(tx.QueryRowContext(ctx,
+		`SELECT id, name, code, category, currency, is_system, created_at FROM accounts WHERE id = ?`, id))
 	if err != nil {
 		return err
 	}
-	_, err = s.writer.ExecContext(ctx, `UPDATE accounts SET name = ? WHERE id = ?`, newName, id)
+	_, err = tx.ExecContext(ctx, `UPDATE accounts SET name = ? WHERE id = ?`, newName, id)
 	if err != nil {
 		return fmt.Errorf("rename account: %w", err)
 	}
-	return nil
+	after := *before
+	after.Name = newName
+	if err := logChange(ctx, tx, ledger.OpAccountRename, id, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (s *Store) DeleteAccount(ctx context.Context, id string) error {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Check account exists
-	_, err := s.GetAccount(ctx, id)
+	before, err := scanAccount(tx.QueryRowContext(ctx,
+		`SELECT id, name, code, category, currency, is_system, created_at FROM accounts WHERE id = ?`, id))
 	if err != nil {
 		return err
 	}
 
 	// Refuse if account has any entries
 	var count int
-	err = s.reader.QueryRowContext(ctx,
+	err = tx.QueryRowContext(ctx,
 		`SELECT COUNT(*) FROM entries WHERE account_id = ?`, id).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("check entries: %w", err)
@@ -103,11 +154,14 @@ func (s *Store) DeleteAccount(ctx context.Context, id string) error {
 		return fmt.Errorf("cannot delete account %s: has %d entries", id, count)
 	}
 
-	_, err = s.writer.ExecContext(ctx, `DELETE FROM accounts WHERE id = ?`, id)
+	_, err = tx.ExecContext(ctx, `DELETE FROM accounts WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("delete account: %w", err)
 	}
-	return nil
+	if err := logChange(ctx, tx, ledger.OpAccountDelete, id, before, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func scanAccount(row *sql.Row) (*ledger.Account, error) {
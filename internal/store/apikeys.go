@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// CreateAPIKey issues a key named name with role. It returns the key and
+// its token, which is not stored and cannot be recovered later.
+func (s *Store) CreateAPIKey(ctx context.Context, name string, role ledger.Role) (*ledger.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("api key name is required")
+	}
+	if !ledger.ValidRole(role) {
+		return nil, "", fmt.Errorf("%w: %q (use reader, poster or admin)", ledger.ErrInvalidRole, role)
+	}
+	token, err := ledger.NewAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+	hash := ledger.HashAPIToken(token)
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The ID is shown in listings and the audit log, so it must not give
+	// away any of the hash.
+	id := uuid.Must(uuid.NewV7()).String()
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO api_keys (id, name, role, token_hash) VALUES (?, ?, ?, ?)`,
+		id, name, string(role), hash)
+	if err != nil {
+		return nil, "", fmt.Errorf("insert api key: %w", err)
+	}
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, apiKeySelect+` WHERE id = ?`, id))
+	if err != nil {
+		return nil, "", err
+	}
+	if err := logChange(ctx, tx, ledger.OpAPIKeyCreate, id, nil, key); err != nil {
+		return nil, "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("commit: %w", err)
+	}
+	return key, token, nil
+}
+
+// ListAPIKeys returns every key, revoked ones included, oldest first.
+func (s *Store) ListAPIKeys(ctx context.Context) ([]ledger.APIKey, error) {
+	rows, err := s.reader.QueryContext(ctx, apiKeySelect+` ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []ledger.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey stops key id from authenticating. Revoking a revoked key is
+// a no-op.
+func (s *Store) RevokeAPIKey(ctx context.Context, id string) (*ledger.APIKey, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := scanAPIKey(tx.QueryRowContext(ctx, apiKeySelect+` WHERE id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	if before.RevokedAt != nil {
+		return before, nil
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339Nano), id)
+	if err != nil {
+		return nil, fmt.Errorf("revoke api key: %w", err)
+	}
+	after, err := scanAPIKey(tx.QueryRowContext(ctx, apiKeySelect+` WHERE id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := logChange(ctx, tx, ledger.OpAPIKeyRevoke, id, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return after, nil
+}
+
+// Authenticate returns the unrevoked key whose token is token.
+func (s *Store) Authenticate(ctx context.Context, token string) (*ledger.APIKey, error) {
+	key, err := scanAPIKey(s.reader.QueryRowContext(ctx,
+		apiKeySelect+` WHERE token_hash = ?`, ledger.HashAPIToken(token)))
+	if err == ledger.ErrAPIKeyNotFound {
+		return nil, ledger.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: key %s was revoked", ledger.ErrUnauthorized, key.ID)
+	}
+	return key, nil
+}
+
+const apiKeySelect = `SELECT id, name, role, created_at, revoked_at FROM api_keys`
+
+func scanAPIKey(row rowScanner) (*ledger.APIKey, error) {
+	var key ledger.APIKey
+	var createdAt string
+	var revokedAt sql.NullString
+	if err := row.Scan(&key.ID, &key.Name, &key.Role, &createdAt, &revokedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ledger.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("scan api key: %w", err)
+	}
+	key.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	if revokedAt.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, revokedAt.String)
+		key.RevokedAt = &t
+	}
+	return &key, nil
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestAPIKeyIDHidesHash(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t)
+
+	key, token, err := st.CreateAPIKey(ctx, "ops", ledger.RoleAdmin)
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	hash := ledger.HashAPIToken(token)
+	if strings.Contains(hash, key.ID) || strings.Contains(token, key.ID) {
+		t.Errorf("key ID %s is part of its token or hash", key.ID)
+	}
+	got, err := st.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if got.ID != key.ID {
+		t.Errorf("Authenticate returned key %s, want %s", got.ID, key.ID)
+	}
+}
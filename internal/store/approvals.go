@@ -0,0 +1,262 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// approvalReason returns why txn needs a second user's approval, or "" if
+// none of its entries exceeds the APPROVAL_THRESHOLD of its account's code.
+func approvalReason(ctx context.Context, tx *sql.Tx, txn *ledger.Transaction, rc *ruleCache) (string, error) {
+	rules, err := rc.rules(ctx, tx, txn.Entries)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range txn.Entries {
+		rule := rules[e.AccountID]
+		if !ledger.ExceedsThreshold(e.Amount, rule.settings.ApprovalThreshold) {
+			continue
+		}
+		amount := e.Amount
+		if amount < 0 {
+			amount = -amount
+		}
+		return fmt.Sprintf("%s %s on %s (code %d) exceeds the approval threshold of %s",
+			ledger.FormatAmount(amount, e.Currency), e.Currency, e.AccountID, rule.code,
+			ledger.FormatAmount(rule.settings.ApprovalThreshold, e.Currency)+" "+e.Currency), nil
+	}
+	return "", nil
+}
+
+// insertApproval queues the unfinalized txn for approval, remembering
+// whether it should become a hold once approved.
+func insertApproval(ctx context.Context, tx *sql.Tx, txn *ledger.Transaction, reason string) error {
+	var hold int
+	var expiresAt sql.NullString
+	if txn.Status == ledger.StatusPending {
+		hold = 1
+		if txn.ExpiresAt != nil {
+			expiresAt = sql.NullString{String: txn.ExpiresAt.UTC().Format(time.RFC3339Nano), Valid: true}
+		}
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO approvals (transaction_id, reason, requested_by, hold, hold_expires_at) VALUES (?, ?, ?, ?, ?)`,
+		txn.ID, reason, actorFrom(ctx), hold, expiresAt)
+	if err != nil {
+		return fmt.Errorf("insert approval: %w", err)
+	}
+	return nil
+}
+
+// ListApprovals returns the approval queue, oldest request first. An empty
+// status lists every request, decided ones included.
+func (s *Store) ListApprovals(ctx context.Context, status ledger.ApprovalStatus) ([]ledger.Approval, error) {
+	query := approvalSelect
+	args := []any{}
+	if status != "" {
+		query += ` WHERE status = ?`
+		args = append(args, string(status))
+	}
+	query += ` ORDER BY requested_at, transaction_id`
+
+	rows, err := s.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list approvals: %w", err)
+	}
+	approvals := []ledger.Approval{}
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		approvals = append(approvals, *a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range approvals {
+		approvals[i].Transaction, err = s.GetTransaction(ctx, approvals[i].TransactionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return approvals, nil
+}
+
+// GetApproval returns the approval request for transaction id.
+func (s *Store) GetApproval(ctx context.Context, id string) (*ledger.Approval, error) {
+	a, err := scanApproval(s.reader.QueryRowContext(ctx, approvalSelect+` WHERE transaction_id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	a.Transaction, err = s.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ApproveTransaction approves the parked transaction id as the context's
+// actor, who must not be the one who posted it. The transaction is then
+// re-checked and finalized at its original posting time, or placed as a
+// hold if one was asked for. A hold whose expiry passed while it waited is
+// rejected instead, and ErrHoldExpired returned.
+func (s *Store) ApproveTransaction(ctx context.Context, id, comment string) (*ledger.Approval, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	req, err := pendingApproval(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	actor := actorFrom(ctx)
+	if actor == req.requestedBy {
+		return nil, fmt.Errorf("%w: %s posted %s", ledger.ErrSelfApproval, actor, id)
+	}
+
+	var expiresAt *time.Time
+	if req.hold && req.holdExpiresAt.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, req.holdExpiresAt.String)
+		expiresAt = &t
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		// Placing the hold now would release it straight away, so take it
+		// off the queue instead of leaving it there for good.
+		lapsed := fmt.Sprintf("the hold expired at %s before it was approved", expiresAt.UTC().Format(time.RFC3339))
+		if err := decideApproval(ctx, tx, id, ledger.ApprovalRejected, actor, lapsed); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit: %w", err)
+		}
+		return nil, fmt.Errorf("%w: %s, so it was rejected", ledger.ErrHoldExpired, lapsed)
+	}
+
+	if err := decideApproval(ctx, tx, id, ledger.ApprovalApproved, actor, comment); err != nil {
+		return nil, err
+	}
+
+	if req.hold {
+		txn, err := recheckTransaction(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		txn.ExpiresAt = expiresAt
+		if err := insertHold(ctx, tx, txn); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := checkPeriodOpen(ctx, tx, req.postedAt); err != nil {
+			return nil, err
+		}
+		if err := finalizeExisting(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetApproval(ctx, id)
+}
+
+// RejectTransaction refuses the parked transaction id. Its entries stay
+// unfinalized for good. The maker may reject their own request to
+// withdraw it. A rejected reversal is unlinked from the transaction it
+// would have reversed, so that one can be reversed again.
+func (s *Store) RejectTransaction(ctx context.Context, id, comment string) (*ledger.Approval, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := pendingApproval(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := decideApproval(ctx, tx, id, ledger.ApprovalRejected, actorFrom(ctx), comment); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM transaction_reversals WHERE reversal_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("unlink reversal: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetApproval(ctx, id)
+}
+
+// approvalRequest is what deciding an approval needs to know about it.
+type approvalRequest struct {
+	requestedBy   string
+	postedAt      time.Time
+	hold          bool
+	holdExpiresAt sql.NullString
+}
+
+// pendingApproval loads the approval request for id, which must still be
+// awaiting a decision.
+func pendingApproval(ctx context.Context, tx *sql.Tx, id string) (*approvalRequest, error) {
+	var req approvalRequest
+	var status ledger.ApprovalStatus
+	var postedAt string
+	err := tx.QueryRowContext(ctx,
+		`SELECT a.status, a.requested_by, a.hold, a.hold_expires_at, t.posted_at
+		FROM approvals a
+		JOIN transactions t ON t.id = a.transaction_id
+		WHERE a.transaction_id = ?`, id,
+	).Scan(&status, &req.requestedBy, &req.hold, &req.holdExpiresAt, &postedAt)
+	if err == sql.ErrNoRows {
+		return nil, ledger.ErrApprovalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup approval: %w", err)
+	}
+	if status != ledger.ApprovalPending {
+		return nil, fmt.Errorf("%w: %s was already %s", ledger.ErrNotAwaitingApproval, id, status)
+	}
+	req.postedAt, _ = time.Parse(time.RFC3339Nano, postedAt)
+	return &req, nil
+}
+
+func decideApproval(ctx context.Context, tx *sql.Tx, id string, to ledger.ApprovalStatus, actor, comment string) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE approvals SET status = ?, decided_by = ?, decided_at = ?, comment = ? WHERE transaction_id = ?`,
+		string(to), actor, time.Now().UTC().Format(time.RFC3339Nano), comment, id)
+	if err != nil {
+		return fmt.Errorf("decide approval: %w", err)
+	}
+	return nil
+}
+
+const approvalSelect = `SELECT transaction_id, status, reason, requested_by, requested_at,
+	COALESCE(decided_by, ''), decided_at, COALESCE(comment, '') FROM approvals`
+
+func scanApproval(row rowScanner) (*ledger.Approval, error) {
+	var a ledger.Approval
+	var requestedAt string
+	var decidedAt sql.NullString
+	if err := row.Scan(&a.TransactionID, &a.Status, &a.Reason, &a.RequestedBy, &requestedAt,
+		&a.DecidedBy, &decidedAt, &a.Comment); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ledger.ErrApprovalNotFound
+		}
+		return nil, fmt.Errorf("scan approval: %w", err)
+	}
+	a.RequestedAt, _ = time.Parse(time.RFC3339Nano, requestedAt)
+	if decidedAt.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, decidedAt.String)
+		a.DecidedAt = &t
+	}
+	return &a, nil
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// decision approves or rejects the parked transaction id as actor.
+type decision struct {
+	actor   string
+	approve bool
+}
+
+func (d decision) apply(st *Store, id string) error {
+	ctx := WithActor(context.Background(), d.actor)
+	var err error
+	if d.approve {
+		_, err = st.ApproveTransaction(ctx, id, "")
+	} else {
+		_, err = st.RejectTransaction(ctx, id, "")
+	}
+	return err
+}
+
+// parkTransaction posts a 1000 cent transaction as alice that waits for
+// approval, as a hold if hold is set.
+func parkTransaction(t *testing.T, st *Store, hold bool) string {
+	t.Helper()
+	requireApproval(t, st, 500)
+	txn := testTransaction("large", 1000)
+	if hold {
+		txn = testHold("large", 1000)
+	}
+	if err := st.CreateTransaction(WithActor(context.Background(), "alice"), txn); err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+	if txn.ApprovalStatus != ledger.ApprovalPending || txn.Finalized {
+		t.Fatalf("transaction was not parked: %+v", txn)
+	}
+	return txn.ID
+}
+
+func TestApprovalDecisions(t *testing.T) {
+	var (
+		bobApproves   = decision{"bob", true}
+		bobRejects    = decision{"bob", false}
+		aliceApproves = decision{"alice", true}
+		aliceRejects  = decision{"alice", false}
+	)
+	tests := []struct {
+		name      string
+		hold      bool
+		decisions []decision
+		want      error // of the last decision
+		status    ledger.ApprovalStatus
+		settled   int64
+		pending   int64 // open hold debits on ~settlement
+	}{
+		{"approved", false, []decision{bobApproves}, nil, ledger.ApprovalApproved, 1000, 0},
+		{"rejected", false, []decision{bobRejects}, nil, ledger.ApprovalRejected, 0, 0},
+		{"approved by its maker", false, []decision{aliceApproves}, ledger.ErrSelfApproval, ledger.ApprovalPending, 0, 0},
+		{"withdrawn by its maker", false, []decision{aliceRejects}, nil, ledger.ApprovalRejected, 0, 0},
+		{"approved twice", false, []decision{bobApproves, bobApproves}, ledger.ErrNotAwaitingApproval, ledger.ApprovalApproved, 1000, 0},
+		{"rejected after approval", false, []decision{bobApproves, bobRejects}, ledger.ErrNotAwaitingApproval, ledger.ApprovalApproved, 1000, 0},
+		{"approved after rejection", false, []decision{bobRejects, bobApproves}, ledger.ErrNotAwaitingApproval, ledger.ApprovalRejected, 0, 0},
+		{"hold approved", true, []decision{bobApproves}, nil, ledger.ApprovalApproved, 0, 1000},
+		{"hold rejected", true, []decision{bobRejects}, nil, ledger.ApprovalRejected, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := openTestStore(t)
+			id := parkTransaction(t, st, tt.hold)
+
+			var err error
+			for _, d := range tt.decisions {
+				err = d.apply(st, id)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			checkApproval(t, st, id, tt.status, tt.settled, tt.pending)
+		})
+	}
+}
+
+// A hold that expires while it waits for approval is rejected when
+// someone tries to approve it, instead of staying in the queue.
+func TestApproveLapsedHold(t *testing.T) {
+	st := openTestStore(t)
+	id := parkTransaction(t, st, true)
+	exec(t, st, `UPDATE approvals SET hold_expires_at = ? WHERE transaction_id = ?`,
+		time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano), id)
+
+	err := decision{"bob", true}.apply(st, id)
+	if !errors.Is(err, ledger.ErrHoldExpired) {
+		t.Fatalf("error = %v, want ErrHoldExpired", err)
+	}
+	checkApproval(t, st, id, ledger.ApprovalRejected, 0, 0)
+	if err := (decision{"bob", true}).apply(st, id); !errors.Is(err, ledger.ErrNotAwaitingApproval) {
+		t.Errorf("approving again: error = %v, want ErrNotAwaitingApproval", err)
+	}
+}
+
+// Concurrent decisions on one transaction: exactly one wins, and the
+// ledger reflects that one.
+func TestApprovalRace(t *testing.T) {
+	for _, hold := range []bool{false, true} {
+		st := openTestStore(t)
+		id := parkTransaction(t, st, hold)
+
+		const n = 8
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = decision{"bob", i%2 == 0}.apply(st, id)
+			}()
+		}
+		wg.Wait()
+
+		winner := -1
+		for i, err := range errs {
+			switch {
+			case err == nil && winner >= 0:
+				t.Fatalf("decisions %d and %d both succeeded", winner, i)
+			case err == nil:
+				winner = i
+			case !errors.Is(err, ledger.ErrNotAwaitingApproval):
+				t.Errorf("decision %d: %v", i, err)
+			}
+		}
+		if winner < 0 {
+			t.Fatal("no decision succeeded")
+		}
+
+		status, settled, pending := ledger.ApprovalRejected, int64(0), int64(0)
+		if winner%2 == 0 {
+			status = ledger.ApprovalApproved
+			if hold {
+				pending = 1000
+			} else {
+				settled = 1000
+			}
+		}
+		checkApproval(t, st, id, status, settled, pending)
+	}
+}
+
+// checkApproval checks the approval status of id and the posted and held
+// amounts on ~settlement.
+func checkApproval(t *testing.T, st *Store, id string, status ledger.ApprovalStatus, settledWant, pendingWant int64) {
+	t.Helper()
+	ctx := context.Background()
+	a, err := st.GetApproval(ctx, id)
+	if err != nil {
+		t.Fatalf("GetApproval: %v", err)
+	}
+	if a.Status != status {
+		t.Errorf("approval status %s, want %s", a.Status, status)
+	}
+	if got := settled(t, st); got != settledWant {
+		t.Errorf("~settlement = %d, want %d", got, settledWant)
+	}
+	debits, _, err := st.PendingAmounts(ctx, "~settlement")
+	if err != nil {
+		t.Fatalf("PendingAmounts: %v", err)
+	}
+	if debits != pendingWant {
+		t.Errorf("pending debits = %d, want %d", debits, pendingWant)
+	}
+}
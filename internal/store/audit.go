@@ -0,0 +1,429 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// integrityTriggers are the triggers the migrations install to enforce the
+// ledger's invariants on write.
+var integrityTriggers = []string{
+	"trg_check_balance",
+	"trg_immutable_entries_insert",
+	"trg_immutable_entries_delete",
+	"trg_immutable_entries_update",
+	"trg_entry_currency_match",
+	"trg_entry_direction",
+	"trg_block_inverted_balance",
+	"trg_period_closed",
+	"trg_period_locked",
+	"trg_pending_finalize",
+	"trg_pending_resolved",
+	"trg_chain_immutable_update",
+	"trg_chain_immutable_delete",
+	"trg_audit_log_immutable_update",
+	"trg_audit_log_immutable_delete",
+	"trg_approval_finalize",
+	"trg_approval_decided",
+	"trg_outbox_immutable_update",
+	"trg_outbox_immutable_delete",
+}
+
+// auditCheck is one invariant Verify checks, reporting failures on c.
+type auditCheck struct {
+	name        string
+	description string
+	run         func(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error
+}
+
+// Verify checks the ledger's invariants against the data itself rather
+// than trusting the triggers to have enforced them: the ledger may predate
+// a trigger, or have been edited outside miniledger. All checks read one
+// consistent snapshot.
+func (s *Store) Verify(ctx context.Context) (*ledger.AuditReport, error) {
+	tx, err := s.reader.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	report := &ledger.AuditReport{GeneratedAt: time.Now().UTC(), Passed: true}
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM schema_version`,
+	).Scan(&report.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("read schema version: %w", err)
+	}
+
+	checks := []auditCheck{
+		{"schema_version", fmt.Sprintf("Schema is at version %d with every migration recorded", schemaVersion), checkSchemaVersion},
+		{"triggers", "Integrity triggers are installed", checkTriggers},
+		{"transaction_balance", "Every finalized transaction has two or more entries and balances per currency", checkTransactionBalance},
+		{"ledger_balance", "Every currency sums to zero across all finalized entries", checkLedgerBalance},
+		{"orphan_entries", "Every entry belongs to an existing transaction and account", checkOrphanEntries},
+		{"unfinalized_entries", "Only open, voided or expired holds and transactions awaiting or refused approval leave entries unfinalized", checkUnfinalizedEntries},
+		{"entry_currency", "Every entry is in its account's currency and a registered currency", checkEntryCurrency},
+		{"account_codes", "Every account's category matches its chart of accounts code", checkAccountCodes},
+		{"system_accounts", "Every system account exists", checkSystemAccounts},
+		{"account_balances", "Served account balances and the trial balance match the entries of the hash chain's transactions, as do FX revaluations' recorded balances", checkAccountBalances},
+	}
+	for _, ch := range checks {
+		c := ledger.AuditCheck{Name: ch.name, Description: ch.description, Passed: true}
+		if err := ch.run(ctx, tx, &c); err != nil {
+			return nil, fmt.Errorf("audit %s: %w", ch.name, err)
+		}
+		if !c.Passed {
+			report.Passed = false
+		}
+		report.Checks = append(report.Checks, c)
+	}
+	return report, nil
+}
+
+func checkSchemaVersion(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	rows, err := tx.QueryContext(ctx, `SELECT version FROM schema_version ORDER BY version`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	recorded := map[int]bool{}
+	latest := 0
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return err
+		}
+		recorded[v] = true
+		latest = v
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if latest != schemaVersion {
+		c.Problemf("schema is at version %d, expected %d", latest, schemaVersion)
+	}
+	for v := 1; v <= latest; v++ {
+		if !recorded[v] {
+			c.Problemf("migration %d is not recorded", v)
+		}
+	}
+	return nil
+}
+
+func checkTriggers(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'trigger'`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	installed := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		installed[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, name := range integrityTriggers {
+		if !installed[name] {
+			c.Problemf("trigger %s is missing", name)
+		}
+	}
+	return nil
+}
+
+func checkTransactionBalance(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	err := eachRow(ctx, tx,
+		`SELECT t.id, COUNT(e.id)
+		FROM transactions t
+		LEFT JOIN entries e ON e.transaction_id = t.id
+		WHERE t.finalized = 1
+		GROUP BY t.id
+		HAVING COUNT(e.id) < 2`,
+		func(rows *sql.Rows) error {
+			var id string
+			var n int
+			if err := rows.Scan(&id, &n); err != nil {
+				return err
+			}
+			c.Problemf("transaction %s has %d entries", id, n)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	return eachRow(ctx, tx,
+		`SELECT e.transaction_id, e.currency, SUM(e.amount)
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+		GROUP BY e.transaction_id, e.currency
+		HAVING SUM(e.amount) != 0
+		ORDER BY e.transaction_id, e.currency`,
+		func(rows *sql.Rows) error {
+			var id, ccy string
+			var sum int64
+			if err := rows.Scan(&id, &ccy, &sum); err != nil {
+				return err
+			}
+			c.Problemf("transaction %s is out of balance by %s %s", id, ledger.FormatAmount(sum, ccy), ccy)
+			return nil
+		})
+}
+
+func checkLedgerBalance(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	sub, args := postedEntries(time.Time{})
+	return eachRow(ctx, tx,
+		`SELECT e.currency, SUM(e.amount) FROM (`+sub+`) e
+		GROUP BY e.currency
+		HAVING SUM(e.amount) != 0
+		ORDER BY e.currency`,
+		func(rows *sql.Rows) error {
+			var ccy string
+			var sum int64
+			if err := rows.Scan(&ccy, &sum); err != nil {
+				return err
+			}
+			c.Problemf("%s entries sum to %s", ccy, ledger.FormatAmount(sum, ccy))
+			return nil
+		}, args...)
+}
+
+func checkOrphanEntries(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	return eachRow(ctx, tx,
+		`SELECT e.id, e.transaction_id, e.account_id, t.id IS NULL, a.id IS NULL
+		FROM entries e
+		LEFT JOIN transactions t ON t.id = e.transaction_id
+		LEFT JOIN accounts a ON a.id = e.account_id
+		WHERE t.id IS NULL OR a.id IS NULL
+		ORDER BY e.id`,
+		func(rows *sql.Rows) error {
+			var id int64
+			var txnID, acctID string
+			var noTxn, noAcct bool
+			if err := rows.Scan(&id, &txnID, &acctID, &noTxn, &noAcct); err != nil {
+				return err
+			}
+			if noTxn {
+				c.Problemf("entry %d references missing transaction %s", id, txnID)
+			}
+			if noAcct {
+				c.Problemf("entry %d references missing account %s", id, acctID)
+			}
+			return nil
+		})
+}
+
+func checkUnfinalizedEntries(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	return eachRow(ctx, tx,
+		`SELECT t.id, t.finalized, COALESCE(p.status, ''), COALESCE(a.status, ''), COUNT(e.id)
+		FROM transactions t
+		LEFT JOIN pending_transactions p ON p.transaction_id = t.id
+		LEFT JOIN approvals a ON a.transaction_id = t.id
+		LEFT JOIN entries e ON e.transaction_id = t.id
+		WHERE (t.finalized = 0 AND (p.status IS NULL OR p.status = 'posted') AND COALESCE(a.status, 'approved') = 'approved')
+		   OR (t.finalized = 1 AND p.status IS NOT NULL AND p.status != 'posted')
+		   OR (t.finalized = 1 AND a.status IS NOT NULL AND a.status != 'approved')
+		GROUP BY t.id
+		ORDER BY t.id`,
+		func(rows *sql.Rows) error {
+			var id, status, approval string
+			var finalized bool
+			var n int
+			if err := rows.Scan(&id, &finalized, &status, &approval, &n); err != nil {
+				return err
+			}
+			switch {
+			case finalized && approval != "" && approval != string(ledger.ApprovalApproved):
+				c.Problemf("transaction %s is finalized but its approval is %s", id, approval)
+			case finalized:
+				c.Problemf("transaction %s is finalized but its hold is %s", id, status)
+			case status == "" && approval != "":
+				c.Problemf("transaction %s was approved but is not finalized", id)
+			case status == "":
+				c.Problemf("transaction %s is not finalized and is not a hold (%d entries)", id, n)
+			default:
+				c.Problemf("transaction %s was captured but is not finalized", id)
+			}
+			return nil
+		})
+}
+
+func checkEntryCurrency(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	return eachRow(ctx, tx,
+		`SELECT e.id, e.account_id, e.currency, a.currency, cur.code IS NULL
+		FROM entries e
+		JOIN accounts a ON a.id = e.account_id
+		LEFT JOIN currencies cur ON cur.code = e.currency
+		WHERE (a.currency != '*' AND e.currency != a.currency) OR cur.code IS NULL
+		ORDER BY e.id`,
+		func(rows *sql.Rows) error {
+			var id int64
+			var acctID, ccy, acctCcy string
+			var unknown bool
+			if err := rows.Scan(&id, &acctID, &ccy, &acctCcy, &unknown); err != nil {
+				return err
+			}
+			if unknown {
+				c.Problemf("entry %d is in unregistered currency %q", id, ccy)
+			}
+			if acctCcy != "*" && ccy != acctCcy {
+				c.Problemf("entry %d is in %s but account %s is in %s", id, ccy, acctID, acctCcy)
+			}
+			return nil
+		})
+}
+
+func checkAccountCodes(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	return eachRow(ctx, tx,
+		`SELECT id, code, category FROM accounts ORDER BY code, id`,
+		func(rows *sql.Rows) error {
+			var id, category string
+			var code int
+			if err := rows.Scan(&id, &code, &category); err != nil {
+				return err
+			}
+			want, err := ledger.CategoryForCode(code)
+			if err != nil {
+				c.Problemf("account %s has code %d outside the chart of accounts", id, code)
+			} else if ledger.Category(category) != want {
+				c.Problemf("account %s has code %d but category %s, expected %s", id, code, category, want)
+			}
+			return nil
+		})
+}
+
+func checkSystemAccounts(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	for _, sa := range ledger.SystemAccounts {
+		var code int
+		var isSystem bool
+		err := tx.QueryRowContext(ctx,
+			`SELECT code, is_system FROM accounts WHERE id = ?`, sa.ID,
+		).Scan(&code, &isSystem)
+		switch {
+		case err == sql.ErrNoRows:
+			c.Problemf("system account %s is missing", sa.ID)
+		case err != nil:
+			return err
+		case !isSystem:
+			c.Problemf("account %s is not marked as a system account", sa.ID)
+		case code != sa.Code:
+			c.Problemf("system account %s has code %d, expected %d", sa.ID, code, sa.Code)
+		}
+	}
+	return nil
+}
+
+// checkAccountBalances recomputes every account's balance from the entries
+// of the transactions in the hash chain, leaving out holds that were not
+// captured and transactions that were not approved, and compares it with
+// the balance the API serves and the trial balance, which both go by the
+// finalized flag. It also compares the balances FX revaluation runs
+// recorded at their as-of dates with the entries.
+func checkAccountBalances(ctx context.Context, tx *sql.Tx, c *ledger.AuditCheck) error {
+	walked := map[string]int64{}
+	err := eachRow(ctx, tx,
+		`SELECT e.account_id, e.amount, COALESCE(p.status, ''), COALESCE(ap.status, '')
+		FROM transaction_chain ch
+		JOIN entries e ON e.transaction_id = ch.transaction_id
+		LEFT JOIN pending_transactions p ON p.transaction_id = ch.transaction_id
+		LEFT JOIN approvals ap ON ap.transaction_id = ch.transaction_id
+		ORDER BY ch.seq, e.id`,
+		func(rows *sql.Rows) error {
+			var acctID, hold, approval string
+			var amount int64
+			if err := rows.Scan(&acctID, &amount, &hold, &approval); err != nil {
+				return err
+			}
+			if (hold == "" || hold == string(ledger.StatusPosted)) && (approval == "" || approval == string(ledger.ApprovalApproved)) {
+				walked[acctID] += amount
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	trial := map[string]int64{}
+	lines, err := trialBalanceLines(ctx, tx, time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		trial[l.AccountID] = l.Debit - l.Credit
+	}
+
+	var accounts []*ledger.Account
+	err = eachRow(ctx, tx,
+		`SELECT id, name, code, category, currency, is_system, created_at FROM accounts ORDER BY code, id`,
+		func(rows *sql.Rows) error {
+			acct, err := scanAccountRow(rows)
+			if err != nil {
+				return err
+			}
+			accounts = append(accounts, acct)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	for _, acct := range accounts {
+		want := walked[acct.ID]
+		served, err := accountBalance(ctx, tx, acct.ID, time.Time{})
+		if err != nil {
+			return err
+		}
+		if served != want {
+			c.Problemf("account %s has a balance of %s but its posted entries sum to %s",
+				acct.ID, ledger.FormatAmount(served, acct.Currency), ledger.FormatAmount(want, acct.Currency))
+		}
+		if trial[acct.ID] != want {
+			c.Problemf("account %s shows %s in the trial balance but its posted entries sum to %s",
+				acct.ID, ledger.FormatAmount(trial[acct.ID], acct.Currency), ledger.FormatAmount(want, acct.Currency))
+		}
+	}
+
+	return eachRow(ctx, tx,
+		`SELECT l.revaluation_id, r.as_of, l.account_id, l.currency, l.balance,
+			COALESCE((SELECT SUM(e.amount) FROM entries e
+				JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+				WHERE e.account_id = l.account_id AND e.currency = l.currency
+				  AND julianday(t.posted_at) <= julianday(r.as_of)), 0)
+		FROM fx_revaluation_lines l
+		JOIN fx_revaluations r ON r.id = l.revaluation_id
+		ORDER BY julianday(r.as_of), l.account_id, l.currency`,
+		func(rows *sql.Rows) error {
+			var revID, asOf, acctID, ccy string
+			var recorded, actual int64
+			if err := rows.Scan(&revID, &asOf, &acctID, &ccy, &recorded, &actual); err != nil {
+				return err
+			}
+			if recorded != actual {
+				c.Problemf("revaluation %s recorded %s %s on %s as of %s, but its entries now sum to %s",
+					revID, ledger.FormatAmount(recorded, ccy), ccy, acctID, asOf, ledger.FormatAmount(actual, ccy))
+			}
+			return nil
+		})
+}
+
+// eachRow runs query and calls fn for every row.
+func eachRow(ctx context.Context, tx *sql.Tx, query string, fn func(*sql.Rows) error, args ...any) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
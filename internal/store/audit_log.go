@@ -0,0 +1,147 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context whose changes are recorded in the audit log
+// as made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFrom returns the actor set by WithActor, or "system" for changes
+// the ledger makes on its own behalf.
+func actorFrom(ctx context.Context) string {
+	if actor, _ := ctx.Value(actorKey{}).(string); actor != "" {
+		return actor
+	}
+	return "system"
+}
+
+// AuditLogFilter narrows an audit log listing. Zero values are ignored.
+type AuditLogFilter struct {
+	Actor     string
+	Operation string // an operation such as setting.upsert, or a family such as setting
+	Target    string
+	From      time.Time
+	To        time.Time
+
+	Limit  int
+	Cursor string // opaque, from a previous page's NextCursor
+}
+
+// logChange appends an audit log entry for a change made inside tx. A nil
+// before or after, including a nil pointer, is stored as NULL.
+func logChange(ctx context.Context, tx *sql.Tx, op, target string, before, after any) error {
+	enc := func(v any) (any, error) {
+		var buf bytes.Buffer
+		e := json.NewEncoder(&buf)
+		e.SetEscapeHTML(false) // account IDs such as <nbg:gel> stay readable
+		if err := e.Encode(v); err != nil {
+			return nil, err
+		}
+		data := strings.TrimSpace(buf.String())
+		if data == "null" {
+			return nil, nil
+		}
+		return data, nil
+	}
+	b, err := enc(before)
+	if err != nil {
+		return fmt.Errorf("encode audit before: %w", err)
+	}
+	a, err := enc(after)
+	if err != nil {
+		return fmt.Errorf("encode audit after: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO audit_log (at, actor, operation, target, before, after) VALUES (?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339Nano), actorFrom(ctx), op, target, b, a)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLog returns one page of the audit log, newest first.
+func (s *Store) ListAuditLog(ctx context.Context, filter AuditLogFilter) (*ledger.AuditLogPage, error) {
+	query := `SELECT id, at, actor, operation, target, before, after FROM audit_log WHERE 1=1`
+	args := []any{}
+
+	if filter.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, filter.Actor)
+	}
+	if filter.Operation != "" {
+		query += ` AND (operation = ? OR operation LIKE ? || '.%')`
+		args = append(args, filter.Operation, filter.Operation)
+	}
+	if filter.Target != "" {
+		query += ` AND target = ?`
+		args = append(args, filter.Target)
+	}
+	if !filter.From.IsZero() {
+		query += ` AND julianday(at) >= julianday(?)`
+		args = append(args, filter.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.To.IsZero() {
+		query += ` AND julianday(at) <= julianday(?)`
+		args = append(args, filter.To.UTC().Format(time.RFC3339Nano))
+	}
+	if filter.Cursor != "" {
+		id, err := decodeEntryCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND id < ?`
+		args = append(args, id)
+	}
+
+	limit := pageSize(filter.Limit)
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := s.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	page := &ledger.AuditLogPage{Entries: []ledger.AuditLogEntry{}}
+	for rows.Next() {
+		var e ledger.AuditLogEntry
+		var at string
+		var before, after sql.NullString
+		if err := rows.Scan(&e.ID, &at, &e.Actor, &e.Operation, &e.Target, &before, &after); err != nil {
+			return nil, fmt.Errorf("scan audit log: %w", err)
+		}
+		e.At, _ = time.Parse(time.RFC3339Nano, at)
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		page.Entries = append(page.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Entries) > limit {
+		page.Entries = page.Entries[:limit]
+		page.NextCursor = encodeEntryCursor(page.Entries[limit-1].ID)
+	}
+	return page, nil
+}
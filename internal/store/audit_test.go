@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestVerifyReportsCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, st *Store, posted, hold *ledger.Transaction)
+		failed  string // the check that must fail; empty when all pass
+	}{
+		{
+			name:    "intact",
+			corrupt: func(*testing.T, *Store, *ledger.Transaction, *ledger.Transaction) {},
+		},
+		{
+			name: "posted transaction unfinalized",
+			corrupt: func(t *testing.T, st *Store, posted, _ *ledger.Transaction) {
+				exec(t, st, `UPDATE transactions SET finalized = 0 WHERE id = ?`, posted.ID)
+			},
+			failed: "account_balances",
+		},
+		{
+			name: "hold finalized without being captured",
+			corrupt: func(t *testing.T, st *Store, _, hold *ledger.Transaction) {
+				exec(t, st, `DROP TRIGGER trg_pending_finalize`)
+				exec(t, st, `UPDATE transactions SET finalized = 1 WHERE id = ?`, hold.ID)
+			},
+			failed: "account_balances",
+		},
+		{
+			name: "entry amount changed",
+			corrupt: func(t *testing.T, st *Store, posted, _ *ledger.Transaction) {
+				exec(t, st, `DROP TRIGGER trg_immutable_entries_update`)
+				exec(t, st, `UPDATE entries SET amount = amount + 1 WHERE transaction_id = ? AND amount > 0`, posted.ID)
+			},
+			failed: "transaction_balance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			st := openTestStore(t)
+			posted := mustCreate(t, st, testTransaction("posted", 2500))
+			hold := testTransaction("hold", 700)
+			hold.Status = ledger.StatusPending
+			mustCreate(t, st, hold)
+
+			tt.corrupt(t, st, posted, hold)
+
+			report, err := st.Verify(ctx)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			for _, c := range report.Checks {
+				if c.Name == tt.failed && c.Passed {
+					t.Errorf("check %s passed, want it to report the corruption", c.Name)
+				}
+				if c.Name != tt.failed && !c.Passed && tt.failed == "" {
+					t.Errorf("check %s failed on an intact ledger: %v", c.Name, c.Problems)
+				}
+			}
+			if report.Passed != (tt.failed == "") {
+				t.Errorf("report passed = %v", report.Passed)
+			}
+		})
+	}
+}
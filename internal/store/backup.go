@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// Backup writes a consistent copy of the ledger to path with VACUUM INTO
+// and checks the copy's integrity. The copy is taken on the reader
+// connection, so the server keeps posting meanwhile, and is made under a
+// temporary name that only becomes path once it has been verified. path
+// must not exist.
+func (s *Store) Backup(ctx context.Context, path string) (*ledger.Backup, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrBackupExists, path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	defer os.Remove(tmp)
+
+	createdAt := time.Now().UTC()
+	if _, err := s.reader.ExecContext(ctx, `VACUUM INTO ?`, tmp); err != nil {
+		return nil, fmt.Errorf("back up to %s: %w", path, err)
+	}
+	b, err := verifyBackup(ctx, tmp)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	b.Path = path
+	b.Size = info.Size()
+	b.CreatedAt = createdAt
+	return b, nil
+}
+
+// verifyBackup runs SQLite's integrity check over the database copy at
+// path and reads its schema version and hash chain head.
+func verifyBackup(ctx context.Context, path string) (*ledger.Backup, error) {
+	db, err := sql.Open("sqlite", fileDSN(path, "mode=ro"))
+	if err != nil {
+		return nil, fmt.Errorf("open backup: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("check backup: %w", err)
+	}
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("check backup: %w", err)
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("check backup: %w", err)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrBackupCorrupt, strings.Join(problems, "; "))
+	}
+
+	b := &ledger.Backup{ChainHead: ledger.GenesisHash}
+	if err := db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&b.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("read backup schema version: %w", err)
+	}
+	if b.SchemaVersion != schemaVersion {
+		return nil, fmt.Errorf("%w: schema version %d, want %d", ledger.ErrBackupCorrupt, b.SchemaVersion, schemaVersion)
+	}
+	err = db.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE((SELECT hash FROM transaction_chain ORDER BY seq DESC LIMIT 1), '') FROM transaction_chain`,
+	).Scan(&b.Transactions, &b.ChainHead)
+	if err != nil {
+		return nil, fmt.Errorf("read backup chain head: %w", err)
+	}
+	if b.ChainHead == "" {
+		b.ChainHead = ledger.GenesisHash
+	}
+	return b, nil
+}
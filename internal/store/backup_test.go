@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Paths with URI metacharacters must open the file they name.
+func TestBackupOddPaths(t *testing.T) {
+	ctx := context.Background()
+	for _, name := range []string{"plain", "what?mode=rw", "hash#tag", "per%41cent", "sp ace"} {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), name)
+			if err := os.Mkdir(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			st, err := Open(filepath.Join(dir, "ledger.db"), Options{})
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer st.Close()
+			mustCreate(t, st, testTransaction("settle", 10000))
+
+			path := filepath.Join(dir, "backup.db")
+			b, err := st.Backup(ctx, path)
+			if err != nil {
+				t.Fatalf("Backup: %v", err)
+			}
+			if b.ChainHead == "" {
+				t.Errorf("backup has no chain head: %+v", b)
+			}
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("backup not written: %v", err)
+			}
+		})
+	}
+}
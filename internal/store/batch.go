@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// CreateTransactions posts txns in order in one SQL transaction, sharing
+// account and code settings lookups between them. Each is booked as
+// CreateTransaction would book it, under its own savepoint, and errs[i] is
+// the error of txns[i].
+//
+// When atomic, the batch stops at the first failing transaction and nothing
+// is committed; err then names it. Otherwise the failing transactions are
+// rolled back on their own and the rest are committed, and err is only set
+// when the batch as a whole fails.
+func (s *Store) CreateTransactions(ctx context.Context, txns []*ledger.Transaction, atomic bool) (errs []error, err error) {
+	errs = make([]error, len(txns))
+	hashes := make([]string, len(txns))
+	for i, txn := range txns {
+		hashes[i] = requestHash(txn)
+		if errs[i] = prepareTransaction(txn); errs[i] != nil && atomic {
+			return errs, fmt.Errorf("transaction %d: %w", i, errs[i])
+		}
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return errs, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	rc := newRuleCache()
+	replays := map[int]string{}
+	for i, txn := range txns {
+		if errs[i] != nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_item`); err != nil {
+			return errs, fmt.Errorf("savepoint: %w", err)
+		}
+		origID, err := bookTransaction(ctx, tx, txn, hashes[i], rc)
+		if err != nil {
+			if atomic {
+				errs[i] = err
+				return errs, fmt.Errorf("transaction %d: %w", i, err)
+			}
+			if _, rerr := tx.ExecContext(ctx, `ROLLBACK TO batch_item`); rerr != nil {
+				return errs, fmt.Errorf("rollback to savepoint: %w", rerr)
+			}
+			errs[i] = err
+		} else if origID != "" {
+			replays[i] = origID
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE batch_item`); err != nil {
+			return errs, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errs, fmt.Errorf("commit: %w", err)
+	}
+
+	// Replays are read back once committed, since the original may have
+	// been booked earlier in this batch.
+	for i, origID := range replays {
+		errs[i] = s.replayTransaction(ctx, txns[i], origID)
+	}
+	return errs, nil
+}
+
+// CreateAccounts creates accts in order in one SQL transaction, each under
+// its own savepoint, and errs[i] is the error of accts[i]. A failing
+// account is rolled back on its own and the rest are committed; err is
+// only set when the batch as a whole fails.
+func (s *Store) CreateAccounts(ctx context.Context, accts []*ledger.Account) (errs []error, err error) {
+	errs = make([]error, len(accts))
+	for i, acct := range accts {
+		errs[i] = s.checkAccount(ctx, acct)
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return errs, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, acct := range accts {
+		if errs[i] != nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_item`); err != nil {
+			return errs, fmt.Errorf("savepoint: %w", err)
+		}
+		if errs[i] = insertAccount(ctx, tx, acct); errs[i] != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO batch_item`); err != nil {
+				return errs, fmt.Errorf("rollback to savepoint: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE batch_item`); err != nil {
+			return errs, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errs, fmt.Errorf("commit: %w", err)
+	}
+	return errs, nil
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestCreateAccounts(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t)
+	if err := st.CreateAccount(ctx, &ledger.Account{ID: "acc_1", Name: "Alice", Code: 2020, Category: ledger.CategoryLiabilities, Currency: "USD"}); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	accts := []*ledger.Account{
+		{ID: "acc_2", Name: "Bob", Code: 2020, Category: ledger.CategoryLiabilities, Currency: "USD"},
+		{ID: "acc_3", Name: "Carol", Code: 2020, Category: ledger.CategoryLiabilities, Currency: "CHF"},
+		{ID: "acc_1", Name: "Alice again", Code: 2020, Category: ledger.CategoryLiabilities, Currency: "USD"},
+		{ID: "acc_4", Name: "Dan", Code: 2020, Category: ledger.CategoryLiabilities, Currency: "EUR"},
+	}
+	errs, err := st.CreateAccounts(ctx, accts)
+	if err != nil {
+		t.Fatalf("CreateAccounts: %v", err)
+	}
+	if errs[0] != nil || errs[3] != nil {
+		t.Errorf("valid accounts failed: %v, %v", errs[0], errs[3])
+	}
+	if !errors.Is(errs[1], ledger.ErrInvalidCurrency) {
+		t.Errorf("disabled currency: error = %v, want ErrInvalidCurrency", errs[1])
+	}
+	if errs[2] == nil {
+		t.Error("existing account ID was accepted")
+	}
+
+	for id, want := range map[string]string{"acc_1": "Alice", "acc_2": "Bob", "acc_4": "Dan"} {
+		acct, err := st.GetAccount(ctx, id)
+		if err != nil {
+			t.Errorf("GetAccount(%s): %v", id, err)
+		} else if acct.Name != want {
+			t.Errorf("%s is named %q, want %q", id, acct.Name, want)
+		}
+	}
+	if _, err := st.GetAccount(ctx, "acc_3"); !errors.Is(err, ledger.ErrAccountNotFound) {
+		t.Errorf("GetAccount(acc_3) error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func TestCreateTransactions(t *testing.T) {
+	ctx := context.Background()
+	keyed := func(key string, amount int64) *ledger.Transaction {
+		txn := testTransaction("keyed", amount)
+		txn.IdempotencyKey = key
+		return txn
+	}
+	unbalanced := func() *ledger.Transaction {
+		txn := testTransaction("unbalanced", 100)
+		txn.Entries[1].Amount = -99
+		return txn
+	}
+	unknownAccount := func() *ledger.Transaction {
+		txn := testTransaction("unknown account", 100)
+		txn.Entries[1].AccountID = "nobody"
+		return txn
+	}
+
+	tests := []struct {
+		name     string
+		atomic   bool
+		txns     func() []*ledger.Transaction
+		wantErr  bool    // the batch as a whole fails
+		wantErrs []error // per transaction
+		settled  int64
+		replayOf map[int]int // transaction i hands back transaction j
+	}{
+		{
+			name:   "atomic",
+			atomic: true,
+			txns: func() []*ledger.Transaction {
+				return []*ledger.Transaction{testTransaction("a", 100), testTransaction("b", 200)}
+			},
+			wantErrs: []error{nil, nil},
+			settled:  300,
+		},
+		{
+			name:     "atomic with an invalid transaction",
+			atomic:   true,
+			txns:     func() []*ledger.Transaction { return []*ledger.Transaction{testTransaction("a", 100), unbalanced()} },
+			wantErr:  true,
+			wantErrs: []error{nil, ledger.ErrUnbalancedTransaction},
+		},
+		{
+			// The first transaction is booked before the second fails, and
+			// must be rolled back with it.
+			name:   "atomic with a failing transaction",
+			atomic: true,
+			txns: func() []*ledger.Transaction {
+				return []*ledger.Transaction{testTransaction("a", 100), unknownAccount()}
+			},
+			wantErr:  true,
+			wantErrs: []error{nil, ledger.ErrAccountNotFound},
+		},
+		{
+			name:   "best effort",
+			atomic: false,
+			txns: func() []*ledger.Transaction {
+				return []*ledger.Transaction{testTransaction("a", 100), unbalanced(), unknownAccount(), testTransaction("b", 200)}
+			},
+			wantErrs: []error{nil, ledger.ErrUnbalancedTransaction, ledger.ErrAccountNotFound, nil},
+			settled:  300,
+		},
+		{
+			name:     "best effort replaying within the batch",
+			atomic:   false,
+			txns:     func() []*ledger.Transaction { return []*ledger.Transaction{keyed("k", 100), keyed("k", 100)} },
+			wantErrs: []error{nil, nil},
+			settled:  100,
+			replayOf: map[int]int{1: 0},
+		},
+		{
+			name:     "best effort reusing a key within the batch",
+			atomic:   false,
+			txns:     func() []*ledger.Transaction { return []*ledger.Transaction{keyed("k", 100), keyed("k", 200)} },
+			wantErrs: []error{nil, ledger.ErrIdempotencyConflict},
+			settled:  100,
+		},
+		{
+			name:     "atomic reusing a key within the batch",
+			atomic:   true,
+			txns:     func() []*ledger.Transaction { return []*ledger.Transaction{keyed("k", 100), keyed("k", 200)} },
+			wantErr:  true,
+			wantErrs: []error{nil, ledger.ErrIdempotencyConflict},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := openTestStore(t)
+			txns := tt.txns()
+			errs, err := st.CreateTransactions(ctx, txns, tt.atomic)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("batch error = %v, want failure %v", err, tt.wantErr)
+			}
+			for i, want := range tt.wantErrs {
+				if !errors.Is(errs[i], want) {
+					t.Errorf("transaction %d: error = %v, want %v", i, errs[i], want)
+				}
+			}
+			if got := settled(t, st); got != tt.settled {
+				t.Errorf("~settlement = %d, want %d", got, tt.settled)
+			}
+			for i, j := range tt.replayOf {
+				if txns[i].ID != txns[j].ID {
+					t.Errorf("transaction %d got ID %s, want %s", i, txns[i].ID, txns[j].ID)
+				}
+			}
+		})
+	}
+}
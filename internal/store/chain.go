@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// appendChain links the just-finalized transaction id to the end of the
+// hash chain. It must run in the same SQL transaction that finalized it.
+func appendChain(ctx context.Context, tx *sql.Tx, id string) error {
+	txn, err := chainTransaction(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	prev := ledger.GenesisHash
+	err = tx.QueryRowContext(ctx,
+		`SELECT hash FROM transaction_chain ORDER BY seq DESC LIMIT 1`,
+	).Scan(&prev)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("read chain head: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO transaction_chain (transaction_id, prev_hash, hash) VALUES (?, ?, ?)`,
+		id, prev, ledger.ChainHash(prev, txn))
+	if err != nil {
+		return fmt.Errorf("append to hash chain: %w", err)
+	}
+	return nil
+}
+
+// chainTransaction reads the hashed content of transaction id.
+func chainTransaction(ctx context.Context, tx *sql.Tx, id string) (*ledger.Transaction, error) {
+	txn := &ledger.Transaction{ID: id}
+	var postedAt string
+	err := tx.QueryRowContext(ctx,
+		`SELECT description, posted_at FROM transactions WHERE id = ?`, id,
+	).Scan(&txn.Description, &postedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrTransactionNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read transaction %s: %w", id, err)
+	}
+	txn.PostedAt, _ = time.Parse(time.RFC3339Nano, postedAt)
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, transaction_id, account_id, amount, currency, created_at FROM entries WHERE transaction_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("read entries of %s: %w", id, err)
+	}
+	txn.Entries, err = scanEntries(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
+
+// VerifyChain recomputes the hash chain from the stored transactions and
+// reports the first link whose content, previous hash or position no
+// longer matches, or the first finalized transaction missing from it.
+func (s *Store) VerifyChain(ctx context.Context) (*ledger.ChainVerification, error) {
+	tx, err := s.reader.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	v := &ledger.ChainVerification{Valid: true, Head: ledger.GenesisHash, VerifiedAt: time.Now().UTC()}
+
+	type link struct {
+		seq            int64
+		id, prev, hash string
+		finalized      sql.NullBool
+	}
+	var links []link
+	err = eachRow(ctx, tx,
+		`SELECT c.seq, c.transaction_id, c.prev_hash, c.hash, t.finalized
+		FROM transaction_chain c
+		LEFT JOIN transactions t ON t.id = c.transaction_id
+		ORDER BY c.seq`,
+		func(rows *sql.Rows) error {
+			var l link
+			if err := rows.Scan(&l.seq, &l.id, &l.prev, &l.hash, &l.finalized); err != nil {
+				return err
+			}
+			links = append(links, l)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("read hash chain: %w", err)
+	}
+
+	for _, l := range links {
+		fail := func(reason, expected, stored string) {
+			v.Valid = false
+			v.Break = &ledger.ChainBreak{Seq: l.seq, TransactionID: l.id, Reason: reason, Expected: expected, Stored: stored}
+		}
+		if !l.finalized.Valid {
+			fail("transaction no longer exists", "", "")
+			return v, nil
+		}
+		if !l.finalized.Bool {
+			fail("transaction is no longer finalized", "", "")
+			return v, nil
+		}
+		if l.prev != v.Head {
+			fail("previous hash does not match the preceding link", v.Head, l.prev)
+			return v, nil
+		}
+		txn, err := chainTransaction(ctx, tx, l.id)
+		if err != nil {
+			return nil, err
+		}
+		if want := ledger.ChainHash(l.prev, txn); want != l.hash {
+			fail("transaction content has changed since it was finalized", want, l.hash)
+			return v, nil
+		}
+		v.Head = l.hash
+		v.Length++
+	}
+
+	var missing string
+	err = tx.QueryRowContext(ctx,
+		`SELECT t.id FROM transactions t
+		LEFT JOIN transaction_chain c ON c.transaction_id = t.id
+		WHERE t.finalized = 1 AND c.seq IS NULL
+		ORDER BY t.rowid LIMIT 1`,
+	).Scan(&missing)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("find unchained transactions: %w", err)
+	}
+	if missing != "" {
+		v.Valid = false
+		v.Break = &ledger.ChainBreak{Seq: v.Length + 1, TransactionID: missing, Reason: "finalized transaction is missing from the chain"}
+	}
+	return v, nil
+}
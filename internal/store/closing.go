@@ -0,0 +1,214 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// CloseYear posts one closing transaction per currency that brings every
+// revenue and expense account to zero as at the end of year, booking the net
+// result to the 3010 Retained Earnings account in that currency. Years must be
+// closed in order, so an earlier year with revenue or expense balances left
+// must be closed first; a year can only be closed once.
+func (s *Store) CloseYear(ctx context.Context, year int) ([]ledger.YearClosing, error) {
+	yearEnd := ledger.YearEnd(year)
+	nextYear := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var latest sql.NullInt64
+	if err := tx.QueryRowContext(ctx, `SELECT MAX(year) FROM year_closings`).Scan(&latest); err != nil {
+		return nil, fmt.Errorf("check year closings: %w", err)
+	}
+	if latest.Valid && latest.Int64 >= int64(year) {
+		return nil, fmt.Errorf("%w: cannot close %d, year %d is already closed", ledger.ErrYearAlreadyClosed, year, latest.Int64)
+	}
+	open, err := earliestOpenYear(ctx, tx, year, latest)
+	if err != nil {
+		return nil, err
+	}
+	if open != 0 {
+		return nil, fmt.Errorf("%w: close %d before %d", ledger.ErrYearOutOfOrder, open, year)
+	}
+
+	// Cumulative P&L balances up to year end. Earlier closings have already
+	// zeroed prior years, so this is the year's result plus anything never closed.
+	rows, err := tx.QueryContext(ctx,
+		`SELECT e.account_id, e.currency, SUM(e.amount) AS balance
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+		JOIN accounts a ON a.id = e.account_id
+		WHERE a.category IN (?, ?)
+		  AND julianday(t.posted_at) < julianday(?)
+		GROUP BY e.account_id, e.currency
+		HAVING balance != 0
+		ORDER BY a.code, e.account_id`,
+		string(ledger.CategoryRevenue), string(ledger.CategoryExpenses), nextYear.Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("year-end balances: %w", err)
+	}
+
+	byCurrency := map[string]*ledger.Transaction{}
+	net := map[string]int64{}
+	for rows.Next() {
+		var accountID, currency string
+		var balance int64
+		if err := rows.Scan(&accountID, &currency, &balance); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan year-end balance: %w", err)
+		}
+		txn, ok := byCurrency[currency]
+		if !ok {
+			txn = &ledger.Transaction{
+				Description: fmt.Sprintf("Year-end close %d (%s)", year, currency),
+				PostedAt:    yearEnd,
+			}
+			byCurrency[currency] = txn
+		}
+		txn.Entries = append(txn.Entries, ledger.Entry{AccountID: accountID, Amount: -balance, Currency: currency})
+		net[currency] += balance
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	if len(byCurrency) == 0 {
+		return nil, fmt.Errorf("%w: %d", ledger.ErrNothingToClose, year)
+	}
+
+	currencies := make([]string, 0, len(byCurrency))
+	for ccy := range byCurrency {
+		currencies = append(currencies, ccy)
+	}
+	sort.Strings(currencies)
+
+	now := time.Now().UTC()
+	rc := newRuleCache()
+	var closings []ledger.YearClosing
+	for _, ccy := range currencies {
+		txn := byCurrency[ccy]
+		if net[ccy] != 0 {
+			reID, err := retainedEarningsAccount(ctx, tx, ccy)
+			if err != nil {
+				return nil, err
+			}
+			txn.Entries = append(txn.Entries, ledger.Entry{AccountID: reID, Amount: net[ccy], Currency: ccy})
+		}
+
+		if err := prepareTransaction(txn); err != nil {
+			return nil, err
+		}
+		if err := insertTransaction(ctx, tx, txn, rc); err != nil {
+			return nil, err
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO year_closings (year, currency, transaction_id, created_at) VALUES (?, ?, ?, ?)`,
+			year, ccy, txn.ID, now.Format(time.RFC3339Nano))
+		if err != nil {
+			return nil, fmt.Errorf("record year closing: %w", err)
+		}
+		closings = append(closings, ledger.YearClosing{
+			Year:          year,
+			Currency:      ccy,
+			TransactionID: txn.ID,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return closings, nil
+}
+
+// earliestOpenYear returns the earliest year before year, and after the
+// latest closed one, that left revenue or expense balances behind, or 0 if
+// there is none. Closing year would otherwise sweep them into its result.
+// Years in between with nothing to close need no closing of their own.
+func earliestOpenYear(ctx context.Context, tx *sql.Tx, year int, latest sql.NullInt64) (int, error) {
+	var after time.Time
+	if latest.Valid {
+		after = ledger.YearEnd(int(latest.Int64))
+	}
+	before := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var open sql.NullInt64
+	err := tx.QueryRowContext(ctx,
+		`WITH unclosed AS (
+			SELECT e.account_id, e.currency
+			FROM entries e
+			JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+			JOIN accounts a ON a.id = e.account_id
+			WHERE a.category IN (?, ?)
+			  AND julianday(t.posted_at) < julianday(?)
+			GROUP BY e.account_id, e.currency
+			HAVING SUM(e.amount) != 0
+		)
+		SELECT MIN(CAST(strftime('%Y', t.posted_at) AS INTEGER))
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+		JOIN unclosed u ON u.account_id = e.account_id AND u.currency = e.currency
+		WHERE julianday(t.posted_at) > julianday(?)
+		  AND julianday(t.posted_at) < julianday(?)`,
+		string(ledger.CategoryRevenue), string(ledger.CategoryExpenses), before.Format(time.RFC3339Nano),
+		after.Format(time.RFC3339Nano), before.Format(time.RFC3339Nano),
+	).Scan(&open)
+	if err != nil {
+		return 0, fmt.Errorf("check earlier years: %w", err)
+	}
+	return int(open.Int64), nil
+}
+
+// ListYearClosings returns every recorded year-end closing, oldest first.
+func (s *Store) ListYearClosings(ctx context.Context) ([]ledger.YearClosing, error) {
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT year, currency, transaction_id, created_at FROM year_closings ORDER BY year, currency`)
+	if err != nil {
+		return nil, fmt.Errorf("list year closings: %w", err)
+	}
+	defer rows.Close()
+
+	var closings []ledger.YearClosing
+	for rows.Next() {
+		var c ledger.YearClosing
+		var createdAt string
+		if err := rows.Scan(&c.Year, &c.Currency, &c.TransactionID, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan year closing: %w", err)
+		}
+		c.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		closings = append(closings, c)
+	}
+	return closings, rows.Err()
+}
+
+// retainedEarningsAccount picks the 3010 account that can hold currency,
+// preferring one denominated in it over a wildcard account.
+func retainedEarningsAccount(ctx context.Context, tx *sql.Tx, currency string) (string, error) {
+	var id string
+	err := tx.QueryRowContext(ctx,
+		`SELECT id FROM accounts
+		WHERE code = ? AND currency IN (?, '*')
+		ORDER BY currency = '*', id
+		LIMIT 1`,
+		ledger.RetainedEarningsCode, currency,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w %s: create an account with code %d in %s first",
+			ledger.ErrNoRetainedEarnings, currency, ledger.RetainedEarningsCode, currency)
+	}
+	if err != nil {
+		return "", fmt.Errorf("lookup retained earnings: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestCloseYearInOrder(t *testing.T) {
+	ctx := context.Background()
+	type closing struct {
+		year int
+		want error
+	}
+	tests := []struct {
+		name     string
+		sales    []int // years with a sale
+		closings []closing
+	}{
+		{
+			name:     "in order",
+			sales:    []int{2024, 2025},
+			closings: []closing{{2024, nil}, {2025, nil}, {2025, ledger.ErrYearAlreadyClosed}},
+		},
+		{
+			name:     "skipping an open year",
+			sales:    []int{2024, 2025, 2026},
+			closings: []closing{{2024, nil}, {2026, ledger.ErrYearOutOfOrder}, {2025, nil}, {2026, nil}},
+		},
+		{
+			name:     "first closing after an open year",
+			sales:    []int{2024, 2025},
+			closings: []closing{{2025, ledger.ErrYearOutOfOrder}, {2024, nil}, {2025, nil}},
+		},
+		{
+			name:     "skipping a year with nothing to close",
+			sales:    []int{2024, 2026},
+			closings: []closing{{2024, nil}, {2026, nil}, {2025, ledger.ErrYearAlreadyClosed}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := openTestStore(t)
+			for _, a := range []*ledger.Account{
+				{ID: "sales", Name: "Sales", Code: 4010, Category: ledger.CategoryRevenue, Currency: "USD"},
+				{ID: "re_usd", Name: "Retained Earnings", Code: ledger.RetainedEarningsCode, Category: ledger.CategoryEquity, Currency: "USD"},
+			} {
+				if err := st.CreateAccount(ctx, a); err != nil {
+					t.Fatalf("CreateAccount %s: %v", a.ID, err)
+				}
+			}
+			for _, year := range tt.sales {
+				mustCreate(t, st, &ledger.Transaction{
+					Description: "Sale",
+					PostedAt:    time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC),
+					Entries: []ledger.Entry{
+						{AccountID: "~settlement", Amount: 100, Currency: "USD"},
+						{AccountID: "sales", Amount: -100, Currency: "USD"},
+					},
+				})
+			}
+
+			for _, c := range tt.closings {
+				_, err := st.CloseYear(ctx, c.year)
+				if !errors.Is(err, c.want) {
+					t.Fatalf("CloseYear(%d) error = %v, want %v", c.year, err, c.want)
+				}
+			}
+			balance, _, err := st.AccountBalance(ctx, "sales", time.Time{})
+			if err != nil {
+				t.Fatalf("AccountBalance: %v", err)
+			}
+			if balance != 0 {
+				t.Errorf("sales balance = %d after closing every year, want 0", balance)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// ListCurrencies returns the ledger's currency registry sorted by code.
+// With enabledOnly set, disabled currencies are left out.
+func (s *Store) ListCurrencies(ctx context.Context, enabledOnly bool) ([]ledger.CurrencyDef, error) {
+	query := `SELECT code, name, exponent, enabled FROM currencies`
+	if enabledOnly {
+		query += ` WHERE enabled = 1`
+	}
+	query += ` ORDER BY code`
+
+	rows, err := s.reader.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list currencies: %w", err)
+	}
+	defer rows.Close()
+
+	var defs []ledger.CurrencyDef
+	for rows.Next() {
+		var c ledger.CurrencyDef
+		if err := rows.Scan(&c.Code, &c.Name, &c.Exponent, &c.Enabled); err != nil {
+			return nil, fmt.Errorf("scan currency: %w", err)
+		}
+		defs = append(defs, c)
+	}
+	return defs, rows.Err()
+}
+
+// SetCurrencyEnabled enables or disables a currency for new accounts and
+// rates. Existing accounts and balances are unaffected. The reporting
+// currency cannot be disabled.
+func (s *Store) SetCurrencyEnabled(ctx context.Context, code string, enabled bool) (*ledger.CurrencyDef, error) {
+	if !enabled && code == s.reporting {
+		return nil, fmt.Errorf("%w: %s is the reporting currency", ledger.ErrInvalidCurrency, code)
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before := ledger.CurrencyDef{Code: code}
+	err = tx.QueryRowContext(ctx,
+		`SELECT name, exponent, enabled FROM currencies WHERE code = ?`, code,
+	).Scan(&before.Name, &before.Exponent, &before.Enabled)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrInvalidCurrency, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get currency: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE currencies SET enabled = ? WHERE code = ?`, enabled, code); err != nil {
+		return nil, fmt.Errorf("update currency: %w", err)
+	}
+	after := before
+	after.Enabled = enabled
+
+	op := ledger.OpCurrencyDisable
+	if enabled {
+		op = ledger.OpCurrencyEnable
+	}
+	if err := logChange(ctx, tx, op, code, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return &after, nil
+}
+
+// requireEnabled checks code is enabled in this ledger's registry.
+func (s *Store) requireEnabled(ctx context.Context, code string) error {
+	var enabled bool
+	err := s.reader.QueryRowContext(ctx, `SELECT enabled FROM currencies WHERE code = ?`, code).Scan(&enabled)
+	if err == sql.ErrNoRows || err == nil && !enabled {
+		return fmt.Errorf("%w: %s is not enabled", ledger.ErrInvalidCurrency, code)
+	}
+	if err != nil {
+		return fmt.Errorf("check currency: %w", err)
+	}
+	return nil
+}
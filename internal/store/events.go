@@ -0,0 +1,293 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// publishPosted writes a transaction.posted event for the just-finalized
+// transaction id to the outbox and queues it for every webhook. It must
+// run in the same SQL transaction that finalized it, after appendChain.
+func publishPosted(ctx context.Context, tx *sql.Tx, id string) error {
+	txn, err := chainTransaction(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	txn.Finalized = true
+	if err := tx.QueryRowContext(ctx,
+		`SELECT hash FROM transaction_chain WHERE transaction_id = ?`, id).Scan(&txn.Hash); err != nil {
+		return fmt.Errorf("read chain hash of %s: %w", id, err)
+	}
+	data, err := json.Marshal(txn)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO outbox (event_type, transaction_id, payload) VALUES (?, ?, ?)`,
+		string(ledger.EventTransactionPosted), id, string(data))
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+	eventID, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("outbox event id: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event_id) SELECT id, ? FROM webhooks`, eventID)
+	if err != nil {
+		return fmt.Errorf("queue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ListEvents returns up to limit outbox events with IDs after after,
+// oldest first.
+func (s *Store) ListEvents(ctx context.Context, after int64, limit int) ([]ledger.Event, error) {
+	rows, err := s.reader.QueryContext(ctx,
+		eventSelect+` WHERE id > ? ORDER BY id LIMIT ?`, after, pageSize(limit))
+	if err != nil {
+		return nil, fmt.Errorf("list events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []ledger.Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *e)
+	}
+	return events, rows.Err()
+}
+
+// LastEventID returns the ID of the newest outbox event, or 0 if there is
+// none.
+func (s *Store) LastEventID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.reader.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM outbox`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("last event id: %w", err)
+	}
+	return id, nil
+}
+
+const eventSelect = `SELECT id, event_type, created_at, payload FROM outbox`
+
+func scanEvent(row rowScanner) (*ledger.Event, error) {
+	var e ledger.Event
+	var createdAt, payload string
+	if err := row.Scan(&e.ID, &e.Type, &createdAt, &payload); err != nil {
+		return nil, fmt.Errorf("scan event: %w", err)
+	}
+	e.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	e.Data = json.RawMessage(payload)
+	return &e, nil
+}
+
+// CreateWebhook subscribes rawURL to every event written from now on. An
+// empty secret generates one. The returned webhook carries its secret.
+func (s *Store) CreateWebhook(ctx context.Context, rawURL, secret string) (*ledger.Webhook, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL, got %q", ledger.ErrInvalidWebhook, rawURL)
+	}
+	if secret == "" {
+		if secret, err = ledger.NewWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	id := uuid.Must(uuid.NewV7()).String()
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO webhooks (id, url, secret) VALUES (?, ?, ?)`, id, rawURL, secret)
+	if err != nil {
+		return nil, fmt.Errorf("insert webhook: %w", err)
+	}
+	hook, err := getWebhook(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := logChange(ctx, tx, ledger.OpWebhookCreate, id, nil, auditedWebhook(hook)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	hook.Secret = secret
+	return hook, nil
+}
+
+// ListWebhooks returns every webhook with its delivery counts, oldest
+// first. Secrets are left out.
+func (s *Store) ListWebhooks(ctx context.Context) ([]ledger.Webhook, error) {
+	rows, err := s.reader.QueryContext(ctx, webhookSelect+` GROUP BY w.id ORDER BY w.created_at, w.id`)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []ledger.Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, *hook)
+	}
+	return hooks, rows.Err()
+}
+
+// DeleteWebhook unsubscribes webhook id and drops its undelivered events.
+func (s *Store) DeleteWebhook(ctx context.Context, id string) (*ledger.Webhook, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	hook, err := getWebhook(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = ?`, id); err != nil {
+		return nil, fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id); err != nil {
+		return nil, fmt.Errorf("delete webhook: %w", err)
+	}
+	if err := logChange(ctx, tx, ledger.OpWebhookDelete, id, auditedWebhook(hook), nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return hook, nil
+}
+
+// auditedWebhook is what the audit log records of a webhook: neither its
+// secret nor its delivery counts.
+func auditedWebhook(h *ledger.Webhook) any {
+	return struct {
+		ID        string    `json:"id"`
+		URL       string    `json:"url"`
+		CreatedAt time.Time `json:"created_at"`
+	}{h.ID, h.URL, h.CreatedAt}
+}
+
+func getWebhook(ctx context.Context, tx *sql.Tx, id string) (*ledger.Webhook, error) {
+	return scanWebhook(tx.QueryRowContext(ctx, webhookSelect+` WHERE w.id = ? GROUP BY w.id`, id))
+}
+
+const webhookSelect = `SELECT w.id, w.url, w.created_at,
+	COUNT(CASE WHEN d.status = 'pending' THEN 1 END),
+	COUNT(CASE WHEN d.status = 'delivered' THEN 1 END),
+	COUNT(CASE WHEN d.status = 'failed' THEN 1 END)
+	FROM webhooks w
+	LEFT JOIN webhook_deliveries d ON d.webhook_id = w.id`
+
+func scanWebhook(row rowScanner) (*ledger.Webhook, error) {
+	var hook ledger.Webhook
+	var createdAt string
+	if err := row.Scan(&hook.ID, &hook.URL, &createdAt, &hook.Pending, &hook.Delivered, &hook.Failed); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ledger.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("scan webhook: %w", err)
+	}
+	hook.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	return &hook, nil
+}
+
+// WebhookDelivery is an event due to be POSTed to a webhook.
+type WebhookDelivery struct {
+	WebhookID string
+	URL       string
+	Secret    string
+	Attempts  int // failed attempts so far
+	Event     ledger.Event
+}
+
+// DueDeliveries returns up to limit pending deliveries per webhook, oldest
+// event first, so a webhook with a backlog does not crowd out the others.
+// Deliveries to a webhook are only due once its oldest pending one is: while
+// that one backs off after a failure, the later events wait behind it, so
+// every webhook receives its events in order.
+func (s *Store) DueDeliveries(ctx context.Context, limit int) ([]WebhookDelivery, error) {
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT webhook_id, url, secret, attempts, event_id, event_type, created_at, payload FROM (
+			SELECT w.id AS webhook_id, w.url, w.secret, d.attempts, o.id AS event_id, o.event_type, o.created_at, o.payload,
+				ROW_NUMBER() OVER (PARTITION BY w.id ORDER BY o.id) AS n,
+				FIRST_VALUE(d.next_attempt_at) OVER (PARTITION BY w.id ORDER BY o.id) AS head_next_attempt_at
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			JOIN outbox o ON o.id = d.event_id
+			WHERE d.status = 'pending'
+		)
+		WHERE n <= ? AND julianday(head_next_attempt_at) <= julianday('now')
+		ORDER BY event_id, webhook_id`, pageSize(limit))
+	if err != nil {
+		return nil, fmt.Errorf("due deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var due []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		var createdAt, payload string
+		if err := rows.Scan(&d.WebhookID, &d.URL, &d.Secret, &d.Attempts,
+			&d.Event.ID, &d.Event.Type, &createdAt, &payload); err != nil {
+			return nil, fmt.Errorf("scan delivery: %w", err)
+		}
+		d.Event.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		d.Event.Data = json.RawMessage(payload)
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+// MarkDelivered records that d was accepted by its webhook.
+func (s *Store) MarkDelivered(ctx context.Context, d WebhookDelivery) error {
+	_, err := s.writer.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = 'delivered', attempts = attempts + 1, delivered_at = ?, last_error = NULL
+		WHERE webhook_id = ? AND event_id = ?`,
+		time.Now().UTC().Format(time.RFC3339Nano), d.WebhookID, d.Event.ID)
+	if err != nil {
+		return fmt.Errorf("mark delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt to deliver d. It is retried at
+// retryAt, or given up on when retryAt is zero.
+func (s *Store) MarkFailed(ctx context.Context, d WebhookDelivery, reason string, retryAt time.Time) error {
+	status := "pending"
+	next := retryAt.UTC().Format(time.RFC3339Nano)
+	if retryAt.IsZero() {
+		status = "failed"
+		next = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	_, err := s.writer.ExecContext(ctx,
+		`UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, next_attempt_at = ?, last_error = ?
+		WHERE webhook_id = ? AND event_id = ?`,
+		status, next, reason, d.WebhookID, d.Event.ID)
+	if err != nil {
+		return fmt.Errorf("mark failed: %w", err)
+	}
+	return nil
+}
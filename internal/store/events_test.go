@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCreateWebhookTwice(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t)
+
+	a, err := st.CreateWebhook(ctx, "https://example.com/hook", "s3cret")
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	b, err := st.CreateWebhook(ctx, "https://example.com/hook", "s3cret")
+	if err != nil {
+		t.Fatalf("CreateWebhook again: %v", err)
+	}
+	if a.ID == b.ID {
+		t.Errorf("both webhooks have ID %s", a.ID)
+	}
+}
@@ -0,0 +1,499 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// Snapshot is a consistent read-only view of a ledger for exporting it.
+// Postings committed while it is open are not seen.
+type Snapshot struct {
+	tx                *sql.Tx
+	SchemaVersion     int
+	ReportingCurrency string
+}
+
+// Snapshot calls fn with a read-only view of the ledger as of now, taken
+// on the reader connection so the server keeps posting meanwhile.
+func (s *Store) Snapshot(ctx context.Context, fn func(*Snapshot) error) error {
+	tx, err := s.reader.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("begin snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	snap := &Snapshot{tx: tx, ReportingCurrency: s.reporting}
+	if err := tx.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&snap.SchemaVersion); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	return fn(snap)
+}
+
+// Accounts calls fn with every account in the order they were created.
+func (sn *Snapshot) Accounts(ctx context.Context, fn func(*ledger.Account) error) error {
+	rows, err := sn.tx.QueryContext(ctx,
+		`SELECT id, name, code, category, currency, is_system, created_at FROM accounts ORDER BY rowid`)
+	if err != nil {
+		return fmt.Errorf("export accounts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		acct, err := scanAccountRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(acct); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Transactions calls fn with every posted transaction and its entries, in
+// hash chain order. Each carries its chain hash, the transaction it
+// reverses, its idempotency key and request hash and, for a captured hold,
+// status posted.
+// Open, voided and expired holds and transactions awaiting or refused
+// approval are left out.
+func (sn *Snapshot) Transactions(ctx context.Context, fn func(*ledger.Transaction) error) error {
+	rows, err := sn.tx.QueryContext(ctx,
+		`SELECT t.id, t.description, t.posted_at, c.hash, COALESCE(r.original_id, ''), COALESCE(ik.key, ''), COALESCE(ik.request_hash, ''), COALESCE(p.status, ''),
+			e.id, e.account_id, e.amount, e.currency, e.created_at
+		FROM transaction_chain c
+		JOIN transactions t ON t.id = c.transaction_id
+		JOIN entries e ON e.transaction_id = t.id
+		LEFT JOIN transaction_reversals r ON r.reversal_id = t.id
+		LEFT JOIN idempotency_keys ik ON ik.transaction_id = t.id
+		LEFT JOIN pending_transactions p ON p.transaction_id = t.id
+		ORDER BY c.seq, e.id`)
+	if err != nil {
+		return fmt.Errorf("export transactions: %w", err)
+	}
+	defer rows.Close()
+
+	// Rows come one per entry; a transaction is done when the ID changes.
+	var txn *ledger.Transaction
+	for rows.Next() {
+		var t ledger.Transaction
+		var e ledger.Entry
+		var postedAt, createdAt string
+		if err := rows.Scan(&t.ID, &t.Description, &postedAt, &t.Hash, &t.ReversalOf, &t.IdempotencyKey, &t.RequestHash, &t.Status,
+			&e.ID, &e.AccountID, &e.Amount, &e.Currency, &createdAt); err != nil {
+			return fmt.Errorf("scan transaction: %w", err)
+		}
+		if txn == nil || txn.ID != t.ID {
+			if txn != nil {
+				if err := fn(txn); err != nil {
+					return err
+				}
+			}
+			t.Finalized = true
+			t.PostedAt, _ = time.Parse(time.RFC3339Nano, postedAt)
+			txn = &t
+		}
+		e.TransactionID = t.ID
+		e.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		txn.Entries = append(txn.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if txn != nil {
+		return fn(txn)
+	}
+	return nil
+}
+
+// Settings calls fn with every CoA code setting.
+func (sn *Snapshot) Settings(ctx context.Context, fn func(ledger.CoASetting) error) error {
+	rows, err := sn.tx.QueryContext(ctx, `SELECT code, setting, value FROM coa_settings ORDER BY code, setting`)
+	if err != nil {
+		return fmt.Errorf("export settings: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cs ledger.CoASetting
+		if err := rows.Scan(&cs.Code, &cs.Setting, &cs.Value); err != nil {
+			return fmt.Errorf("scan setting: %w", err)
+		}
+		if err := fn(cs); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Periods calls fn with every accounting period, earliest first.
+func (sn *Snapshot) Periods(ctx context.Context, fn func(*ledger.Period) error) error {
+	rows, err := sn.tx.QueryContext(ctx,
+		`SELECT id, starts_at, ends_at, status, created_at, closed_at FROM periods ORDER BY julianday(starts_at)`)
+	if err != nil {
+		return fmt.Errorf("export periods: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p, err := scanPeriod(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// YearClosings calls fn with every year-end closing, oldest first.
+func (sn *Snapshot) YearClosings(ctx context.Context, fn func(ledger.YearClosing) error) error {
+	rows, err := sn.tx.QueryContext(ctx,
+		`SELECT year, currency, transaction_id, created_at FROM year_closings ORDER BY year, currency`)
+	if err != nil {
+		return fmt.Errorf("export year closings: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c ledger.YearClosing
+		var createdAt string
+		if err := rows.Scan(&c.Year, &c.Currency, &c.TransactionID, &createdAt); err != nil {
+			return fmt.Errorf("scan year closing: %w", err)
+		}
+		c.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// FXRates calls fn with the whole FX rate history, including the rates the
+// ledger was seeded with.
+func (sn *Snapshot) FXRates(ctx context.Context, fn func(ledger.FXRate) error) error {
+	rows, err := sn.tx.QueryContext(ctx,
+		`SELECT currency, rate, effective_at FROM fx_rates ORDER BY currency, julianday(effective_at)`)
+	if err != nil {
+		return fmt.Errorf("export fx rates: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r ledger.FXRate
+		var effectiveAt string
+		if err := rows.Scan(&r.Currency, &r.Rate, &effectiveAt); err != nil {
+			return fmt.Errorf("scan rate: %w", err)
+		}
+		r.EffectiveAt, _ = time.Parse(time.RFC3339Nano, effectiveAt)
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// FXRevaluations calls fn with every FX revaluation run and its lines,
+// oldest first.
+func (sn *Snapshot) FXRevaluations(ctx context.Context, fn func(*ledger.FXRevaluation) error) error {
+	rows, err := sn.tx.QueryContext(ctx,
+		`SELECT r.id, r.as_of, COALESCE(r.transaction_id, ''), r.gain, r.loss, r.created_at,
+			l.account_id, l.currency, l.balance, l.rate, l.rate_effective_at, l.carrying_amount, l.revalued_amount, l.adjustment
+		FROM fx_revaluations r
+		LEFT JOIN fx_revaluation_lines l ON l.revaluation_id = r.id
+		ORDER BY julianday(r.as_of), r.id, l.account_id, l.currency`)
+	if err != nil {
+		return fmt.Errorf("export fx revaluations: %w", err)
+	}
+	defer rows.Close()
+
+	// Rows come one per line; a run is done when the ID changes.
+	var rev *ledger.FXRevaluation
+	for rows.Next() {
+		r := ledger.FXRevaluation{ReportingCurrency: sn.ReportingCurrency}
+		var asOf, createdAt string
+		var accountID, currency, effectiveAt sql.NullString
+		var balance, carrying, revalued, adjustment sql.NullInt64
+		var rate sql.NullFloat64
+		if err := rows.Scan(&r.ID, &asOf, &r.TransactionID, &r.Gain, &r.Loss, &createdAt,
+			&accountID, &currency, &balance, &rate, &effectiveAt, &carrying, &revalued, &adjustment); err != nil {
+			return fmt.Errorf("scan fx revaluation: %w", err)
+		}
+		if rev == nil || rev.ID != r.ID {
+			if rev != nil {
+				if err := fn(rev); err != nil {
+					return err
+				}
+			}
+			r.AsOf, _ = time.Parse(time.RFC3339Nano, asOf)
+			r.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+			rev = &r
+		}
+		if accountID.Valid {
+			l := ledger.FXRevaluationLine{
+				AccountID:      accountID.String,
+				Currency:       currency.String,
+				Balance:        balance.Int64,
+				Rate:           rate.Float64,
+				CarryingAmount: carrying.Int64,
+				RevaluedAmount: revalued.Int64,
+				Adjustment:     adjustment.Int64,
+			}
+			l.RateEffectiveAt, _ = time.Parse(time.RFC3339Nano, effectiveAt.String)
+			rev.Lines = append(rev.Lines, l)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if rev != nil {
+		return fn(rev)
+	}
+	return nil
+}
+
+// Restorer replays an exported ledger into a new one. See Store.Restore.
+type Restorer struct {
+	tx       *sql.Tx
+	settings []ledger.CoASetting
+}
+
+// Restore replays the export described by m into s, which must be a new
+// ledger with only its system accounts and m's reporting currency. fn adds
+// the exported records with the Restorer: accounts first, then
+// transactions in chain order, which must end at m.ChainHead, then the FX
+// rate history, periods, year-end closings and FX revaluations, which
+// refer to the transactions. Periods come after the transactions so that
+// history posted before a period was closed can be restored into it. The
+// rates the new ledger was seeded with are replaced by the exported ones.
+// It all happens in one SQL transaction, so nothing is written unless
+// every record restores. Code settings are applied last, so that history
+// posted before a setting existed is not checked against it.
+func (s *Store) Restore(ctx context.Context, m *ledger.ArchiveManifest, fn func(*Restorer) error) error {
+	if m.SchemaVersion > schemaVersion {
+		return fmt.Errorf("%w: made by a newer miniledger (schema version %d, this one supports %d)",
+			ledger.ErrInvalidArchive, m.SchemaVersion, schemaVersion)
+	}
+	if m.ReportingCurrency != s.reporting {
+		return fmt.Errorf("%w: the archive reports in %s, this ledger in %s",
+			ledger.ErrInvalidArchive, m.ReportingCurrency, s.reporting)
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var accounts, txns int
+	err = tx.QueryRowContext(ctx,
+		`SELECT (SELECT COUNT(*) FROM accounts WHERE is_system = 0), (SELECT COUNT(*) FROM transactions)`,
+	).Scan(&accounts, &txns)
+	if err != nil {
+		return fmt.Errorf("check ledger is empty: %w", err)
+	}
+	if accounts > 0 || txns > 0 {
+		return fmt.Errorf("%w: %d accounts and %d transactions", ledger.ErrLedgerNotEmpty, accounts, txns)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM fx_rates`); err != nil {
+		return fmt.Errorf("clear seeded fx rates: %w", err)
+	}
+
+	r := &Restorer{tx: tx}
+	if err := fn(r); err != nil {
+		return err
+	}
+	head := ledger.GenesisHash
+	err = tx.QueryRowContext(ctx, `SELECT hash FROM transaction_chain ORDER BY seq DESC LIMIT 1`).Scan(&head)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("read chain head: %w", err)
+	}
+	if head != m.ChainHead {
+		return fmt.Errorf("%w: restored chain ends at %s, not %s", ledger.ErrInvalidArchive, head, m.ChainHead)
+	}
+	for _, cs := range r.settings {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO coa_settings (code, setting, value) VALUES (?, ?, ?)
+			ON CONFLICT(code, setting) DO UPDATE SET value = excluded.value`,
+			cs.Code, string(cs.Setting), cs.Value)
+		if err != nil {
+			return fmt.Errorf("restore setting %d/%s: %w", cs.Code, cs.Setting, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// Account restores acct with its creation time and enables its currency.
+// System accounts already exist and must match.
+func (r *Restorer) Account(ctx context.Context, acct *ledger.Account) error {
+	existing, err := scanAccount(r.tx.QueryRowContext(ctx,
+		`SELECT id, name, code, category, currency, is_system, created_at FROM accounts WHERE id = ?`, acct.ID))
+	if err == nil {
+		if existing.Code != acct.Code || existing.Currency != acct.Currency || existing.IsSystem != acct.IsSystem {
+			return fmt.Errorf("%w: account %s is %d %s here but %d %s in the archive",
+				ledger.ErrInvalidArchive, acct.ID, existing.Code, existing.Currency, acct.Code, acct.Currency)
+		}
+		return nil
+	}
+	if err != ledger.ErrAccountNotFound {
+		return err
+	}
+
+	_, err = r.tx.ExecContext(ctx,
+		`INSERT INTO accounts (id, name, code, category, currency, is_system, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		acct.ID, acct.Name, acct.Code, string(acct.Category), acct.Currency, boolToInt(acct.IsSystem),
+		acct.CreatedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("restore account %s: %w", acct.ID, err)
+	}
+	if acct.Currency != "*" {
+		if _, err := r.tx.ExecContext(ctx, `UPDATE currencies SET enabled = 1 WHERE code = ?`, acct.Currency); err != nil {
+			return fmt.Errorf("enable currency %s: %w", acct.Currency, err)
+		}
+	}
+	return nil
+}
+
+// Transaction restores the posted txn at its original posting time and
+// appends it to the hash chain, which must reproduce txn.Hash.
+func (r *Restorer) Transaction(ctx context.Context, txn *ledger.Transaction) error {
+	if err := txn.Validate(); err != nil {
+		return fmt.Errorf("%w: transaction %s: %w", ledger.ErrInvalidArchive, txn.ID, err)
+	}
+	_, err := r.tx.ExecContext(ctx,
+		`INSERT INTO transactions (id, description, posted_at) VALUES (?, ?, ?)`,
+		txn.ID, txn.Description, txn.PostedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("restore transaction %s: %w", txn.ID, err)
+	}
+	for i, e := range txn.Entries {
+		_, err := r.tx.ExecContext(ctx,
+			`INSERT INTO entries (transaction_id, account_id, amount, currency, created_at) VALUES (?, ?, ?, ?, ?)`,
+			txn.ID, e.AccountID, e.Amount, e.Currency, e.CreatedAt.UTC().Format(time.RFC3339Nano))
+		if err != nil {
+			return fmt.Errorf("restore entry %d of %s: %w", i, txn.ID, err)
+		}
+	}
+	if _, err := r.tx.ExecContext(ctx, `UPDATE transactions SET finalized = 1 WHERE id = ?`, txn.ID); err != nil {
+		return fmt.Errorf("finalize transaction %s: %w", txn.ID, err)
+	}
+	if err := appendChain(ctx, r.tx, txn.ID); err != nil {
+		return err
+	}
+	var hash string
+	if err := r.tx.QueryRowContext(ctx,
+		`SELECT hash FROM transaction_chain WHERE transaction_id = ?`, txn.ID).Scan(&hash); err != nil {
+		return fmt.Errorf("read chain hash of %s: %w", txn.ID, err)
+	}
+	if hash != txn.Hash {
+		return fmt.Errorf("%w: transaction %s hashes to %s, not %s as exported", ledger.ErrInvalidArchive, txn.ID, hash, txn.Hash)
+	}
+
+	if txn.ReversalOf != "" {
+		_, err := r.tx.ExecContext(ctx,
+			`INSERT INTO transaction_reversals (original_id, reversal_id) VALUES (?, ?)`, txn.ReversalOf, txn.ID)
+		if err != nil {
+			return fmt.Errorf("restore reversal %s: %w", txn.ID, err)
+		}
+	}
+	if txn.IdempotencyKey != "" {
+		hash := txn.RequestHash
+		if hash == "" {
+			// Older archives leave the hash out. Their ledgers hashed the
+			// request that created the transaction without its posting
+			// time, and it asked for a hold if it was a captured one.
+			req := *txn
+			req.PostedAt = time.Time{}
+			req.Status = ""
+			if txn.Status == ledger.StatusPosted {
+				req.Status = ledger.StatusPending
+			}
+			hash = requestHash(&req)
+		}
+		if err := insertIdempotencyKey(ctx, r.tx, txn.IdempotencyKey, txn.ID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FXRate restores one rate of the FX rate history.
+func (r *Restorer) FXRate(ctx context.Context, rate ledger.FXRate) error {
+	if err := rate.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ledger.ErrInvalidArchive, err)
+	}
+	_, err := r.tx.ExecContext(ctx,
+		`INSERT INTO fx_rates (currency, rate, effective_at) VALUES (?, ?, ?)`,
+		rate.Currency, rate.Rate, rate.EffectiveAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("restore fx rate %s: %w", rate.Currency, err)
+	}
+	return nil
+}
+
+// Period restores p with its status. It must be called after every
+// transaction is restored, as postings into a closed period are refused.
+func (r *Restorer) Period(ctx context.Context, p *ledger.Period) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ledger.ErrInvalidArchive, err)
+	}
+	var closedAt any
+	if p.ClosedAt != nil {
+		closedAt = p.ClosedAt.UTC().Format(time.RFC3339Nano)
+	}
+	_, err := r.tx.ExecContext(ctx,
+		`INSERT INTO periods (id, starts_at, ends_at, status, created_at, closed_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		p.ID, p.StartsAt.UTC().Format(time.RFC3339Nano), p.EndsAt.UTC().Format(time.RFC3339Nano), string(p.Status),
+		p.CreatedAt.UTC().Format(time.RFC3339Nano), closedAt)
+	if err != nil {
+		return fmt.Errorf("restore period %s: %w", p.ID, err)
+	}
+	return nil
+}
+
+// YearClosing records that the restored transaction c.TransactionID closed
+// c.Year for c.Currency.
+func (r *Restorer) YearClosing(ctx context.Context, c ledger.YearClosing) error {
+	_, err := r.tx.ExecContext(ctx,
+		`INSERT INTO year_closings (year, currency, transaction_id, created_at) VALUES (?, ?, ?, ?)`,
+		c.Year, c.Currency, c.TransactionID, c.CreatedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("restore year closing %d %s: %w", c.Year, c.Currency, err)
+	}
+	return nil
+}
+
+// FXRevaluation restores a revaluation run and its lines, which later runs
+// carry balances forward from.
+func (r *Restorer) FXRevaluation(ctx context.Context, rev *ledger.FXRevaluation) error {
+	var txnID sql.NullString
+	if rev.TransactionID != "" {
+		txnID = sql.NullString{String: rev.TransactionID, Valid: true}
+	}
+	_, err := r.tx.ExecContext(ctx,
+		`INSERT INTO fx_revaluations (id, as_of, transaction_id, gain, loss, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		rev.ID, rev.AsOf.UTC().Format(time.RFC3339Nano), txnID, rev.Gain, rev.Loss, rev.CreatedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("restore fx revaluation %s: %w", rev.ID, err)
+	}
+	for _, l := range rev.Lines {
+		_, err := r.tx.ExecContext(ctx,
+			`INSERT INTO fx_revaluation_lines
+			(revaluation_id, account_id, currency, balance, rate, rate_effective_at, carrying_amount, revalued_amount, adjustment)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			rev.ID, l.AccountID, l.Currency, l.Balance, l.Rate, l.RateEffectiveAt.UTC().Format(time.RFC3339Nano),
+			l.CarryingAmount, l.RevaluedAmount, l.Adjustment)
+		if err != nil {
+			return fmt.Errorf("restore fx revaluation line %s/%s: %w", l.AccountID, l.Currency, err)
+		}
+	}
+	return nil
+}
+
+// Setting queues cs, to be applied once every transaction is restored.
+func (r *Restorer) Setting(cs ledger.CoASetting) {
+	r.settings = append(r.settings, cs)
+}
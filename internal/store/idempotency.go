@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// requestHash fingerprints the client-controlled parts of a transaction
+// (description, posting time if given, and entries, in order) for
+// idempotency comparison. It must be taken before prepareTransaction fills
+// in the posting time.
+func requestHash(txn *ledger.Transaction) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q\n", txn.Description)
+	if txn.Status == ledger.StatusPending {
+		b.WriteString("pending\n")
+	}
+	if !txn.PostedAt.IsZero() {
+		fmt.Fprintf(&b, "posted_at %s\n", txn.PostedAt.UTC().Format(time.RFC3339Nano))
+	}
+	for _, e := range txn.Entries {
+		fmt.Fprintf(&b, "%q:%d:%q\n", e.AccountID, e.Amount, e.Currency)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookupIdempotencyKey returns the transaction previously created with key,
+// or "" if the key is unused. A key reused with a different payload fails
+// with ErrIdempotencyConflict.
+func lookupIdempotencyKey(ctx context.Context, tx *sql.Tx, key, hash string) (string, error) {
+	var txnID, storedHash string
+	err := tx.QueryRowContext(ctx,
+		`SELECT transaction_id, request_hash FROM idempotency_keys WHERE key = ?`, key,
+	).Scan(&txnID, &storedHash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("lookup idempotency key: %w", err)
+	}
+	if storedHash != hash {
+		return "", fmt.Errorf("%w: key %q belongs to transaction %s", ledger.ErrIdempotencyConflict, key, txnID)
+	}
+	return txnID, nil
+}
+
+func insertIdempotencyKey(ctx context.Context, tx *sql.Tx, key, txnID, hash string) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (key, transaction_id, request_hash) VALUES (?, ?, ?)`,
+		key, txnID, hash,
+	)
+	if err != nil {
+		return fmt.Errorf("insert idempotency key: %w", err)
+	}
+	return nil
+}
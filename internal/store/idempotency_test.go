@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestIdempotentReplay(t *testing.T) {
+	ctx := context.Background()
+	jan2 := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	postedAt := func(at time.Time) func() *ledger.Transaction {
+		return func() *ledger.Transaction {
+			txn := testTransaction("payment", 1000)
+			txn.PostedAt = at
+			return txn
+		}
+	}
+	tests := []struct {
+		name     string
+		postedAt time.Time // of the original, if given
+		replay   func() *ledger.Transaction
+		// want is the error, or nil if the replay should hand back the
+		// original (same) or book a new transaction.
+		want        error
+		same        bool
+		wantBalance int64
+	}{
+		{
+			name:        "same body",
+			replay:      func() *ledger.Transaction { return testTransaction("payment", 1000) },
+			same:        true,
+			wantBalance: 1000,
+		},
+		{
+			name:   "changed amount",
+			replay: func() *ledger.Transaction { return testTransaction("payment", 1001) },
+			want:   ledger.ErrIdempotencyConflict,
+		},
+		{
+			name:   "changed description",
+			replay: func() *ledger.Transaction { return testTransaction("payment (retry)", 1000) },
+			want:   ledger.ErrIdempotencyConflict,
+		},
+		{
+			name: "reordered entries",
+			replay: func() *ledger.Transaction {
+				txn := testTransaction("payment", 1000)
+				txn.Entries[0], txn.Entries[1] = txn.Entries[1], txn.Entries[0]
+				return txn
+			},
+			want: ledger.ErrIdempotencyConflict,
+		},
+		{
+			name:   "as a hold",
+			replay: func() *ledger.Transaction { return testHold("payment", 1000) },
+			want:   ledger.ErrIdempotencyConflict,
+		},
+		{
+			name:   "with a posting time",
+			replay: postedAt(jan2),
+			want:   ledger.ErrIdempotencyConflict,
+		},
+		{
+			name:     "without the posting time",
+			postedAt: jan2,
+			replay:   func() *ledger.Transaction { return testTransaction("payment", 1000) },
+			want:     ledger.ErrIdempotencyConflict,
+		},
+		{
+			name:     "changed posting time",
+			postedAt: jan2,
+			replay:   postedAt(jan2.AddDate(0, 0, 1)),
+			want:     ledger.ErrIdempotencyConflict,
+		},
+		{
+			name:        "same posting time",
+			postedAt:    jan2,
+			replay:      postedAt(jan2),
+			same:        true,
+			wantBalance: 1000,
+		},
+		{
+			name: "another key",
+			replay: func() *ledger.Transaction {
+				txn := testTransaction("payment", 1000)
+				txn.IdempotencyKey = "pay-2"
+				return txn
+			},
+			wantBalance: 2000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := openTestStore(t)
+			orig := testTransaction("payment", 1000)
+			orig.IdempotencyKey = "pay-1"
+			orig.PostedAt = tt.postedAt
+			mustCreate(t, st, orig)
+
+			txn := tt.replay()
+			if txn.IdempotencyKey == "" {
+				txn.IdempotencyKey = "pay-1"
+			}
+			err := st.CreateTransaction(ctx, txn)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("error = %v, want %v", err, tt.want)
+				}
+				if got := settled(t, st); got != 1000 {
+					t.Errorf("~settlement = %d after a refused replay, want 1000", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateTransaction: %v", err)
+			}
+			if same := txn.ID == orig.ID; same != tt.same {
+				t.Errorf("replay got ID %s, original %s", txn.ID, orig.ID)
+			}
+			if got := settled(t, st); got != tt.wantBalance {
+				t.Errorf("~settlement = %d, want %d", got, tt.wantBalance)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// ReportingCurrency returns the currency the ledger's reports are
+// converted to.
+func (s *Store) ReportingCurrency() string {
+	return s.reporting
+}
+
+// Info returns the ledger-wide settings fixed when it was created.
+func (s *Store) Info() ledger.LedgerInfo {
+	return ledger.LedgerInfo{ReportingCurrency: s.reporting}
+}
+
+// reportingCurrency resolves the ledger's reporting currency before
+// migrating from version. A new ledger takes the requested currency or the
+// default; an existing one keeps its own, and GEL if it predates the
+// setting. Requesting a different currency for an existing ledger fails.
+func reportingCurrency(ctx context.Context, tx *sql.Tx, version int, requested string) (string, error) {
+	requested = strings.ToUpper(strings.TrimSpace(requested))
+	if version == 0 {
+		if requested == "" {
+			return ledger.DefaultReportingCurrency, nil
+		}
+		if _, ok := ledger.LookupCurrency(requested); !ok {
+			return "", fmt.Errorf("%w: %s", ledger.ErrInvalidCurrency, requested)
+		}
+		return requested, nil
+	}
+
+	current := ledger.DefaultReportingCurrency
+	if version >= 12 {
+		err := tx.QueryRowContext(ctx,
+			`SELECT value FROM ledger_meta WHERE key = 'reporting_currency'`,
+		).Scan(&current)
+		if err != nil && err != sql.ErrNoRows {
+			return "", fmt.Errorf("read reporting currency: %w", err)
+		}
+	}
+	if requested != "" && requested != current {
+		return "", fmt.Errorf("ledger reports in %s; the reporting currency cannot be changed to %s", current, requested)
+	}
+	return current, nil
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/simonvc/miniledger/internal/ledger"
 )
 
-func (s *Store) migrate(ctx context.Context) error {
+// schemaVersion is the version migrate brings a ledger to.
+const schemaVersion = 17
+
+func (s *Store) migrate(ctx context.Context, opts Options) error {
 	tx, err := s.writer.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -30,8 +34,13 @@ func (s *Store) migrate(ctx context.Context) error {
 		return fmt.Errorf("read schema version: %w", err)
 	}
 
+	s.reporting, err = reportingCurrency(ctx, tx, version, opts.ReportingCurrency)
+	if err != nil {
+		return err
+	}
+
 	if version < 1 {
-		if err := migrateV1(ctx, tx); err != nil {
+		if err := migrateV1(ctx, tx, s.reporting); err != nil {
 			return fmt.Errorf("migration v1: %w", err)
 		}
 	}
@@ -42,10 +51,109 @@ func (s *Store) migrate(ctx context.Context) error {
 		}
 	}
 
+	if version < 3 {
+		if err := migrateV3(ctx, tx); err != nil {
+			return fmt.Errorf("migration v3: %w", err)
+		}
+	}
+
+	if version < 4 {
+		if err := migrateV4(ctx, tx); err != nil {
+			return fmt.Errorf("migration v4: %w", err)
+		}
+	}
+
+	if version < 5 {
+		if err := migrateV5(ctx, tx); err != nil {
+			return fmt.Errorf("migration v5: %w", err)
+		}
+	}
+
+	if version < 6 {
+		if err := migrateV6(ctx, tx); err != nil {
+			return fmt.Errorf("migration v6: %w", err)
+		}
+	}
+
+	if version < 7 {
+		if err := migrateV7(ctx, tx); err != nil {
+			return fmt.Errorf("migration v7: %w", err)
+		}
+	}
+
+	if version < 8 {
+		if err := migrateV8(ctx, tx); err != nil {
+			return fmt.Errorf("migration v8: %w", err)
+		}
+	}
+
+	if version < 9 {
+		if err := migrateV9(ctx, tx, s.reporting); err != nil {
+			return fmt.Errorf("migration v9: %w", err)
+		}
+	}
+
+	if version < 10 {
+		if err := migrateV10(ctx, tx, s.reporting); err != nil {
+			return fmt.Errorf("migration v10: %w", err)
+		}
+	}
+
+	if version < 11 {
+		if err := migrateV11(ctx, tx); err != nil {
+			return fmt.Errorf("migration v11: %w", err)
+		}
+	}
+
+	if version < 12 {
+		if err := migrateV12(ctx, tx, s.reporting); err != nil {
+			return fmt.Errorf("migration v12: %w", err)
+		}
+	}
+
+	if version < 13 {
+		if err := migrateV13(ctx, tx); err != nil {
+			return fmt.Errorf("migration v13: %w", err)
+		}
+	}
+
+	if version < 14 {
+		if err := migrateV14(ctx, tx); err != nil {
+			return fmt.Errorf("migration v14: %w", err)
+		}
+	}
+
+	if version < 15 {
+		if err := migrateV15(ctx, tx); err != nil {
+			return fmt.Errorf("migration v15: %w", err)
+		}
+	}
+
+	if version < 16 {
+		if err := migrateV16(ctx, tx); err != nil {
+			return fmt.Errorf("migration v16: %w", err)
+		}
+	}
+
+	if version < 17 {
+		if err := migrateV17(ctx, tx); err != nil {
+			return fmt.Errorf("migration v17: %w", err)
+		}
+	}
+
 	return tx.Commit()
 }
 
-func migrateV1(ctx context.Context, tx *sql.Tx) error {
+// stmtPrefix shortens a failing migration statement for its error message.
+func stmtPrefix(stmt string) string {
+	const n = 60
+	if len(stmt) > n {
+		return stmt[:n]
+	}
+	return stmt
+}
+
+func migrateV1(ctx context.Context, tx *sql.Tx, reporting string) error {
 	stmts := []string{
 		// Accounts table
 		`CREATE TABLE IF NOT EXISTS accounts (
@@ -137,19 +245,15 @@ func migrateV1(ctx context.Context, tx *sql.Tx) error {
 
 	for _, stmt := range stmts {
 		if _, err := tx.ExecContext(ctx, stmt); err != nil {
-			return fmt.Errorf("exec %q: %w", stmt[:60], err)
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
 		}
 	}
 
 	// Seed system accounts
 	for _, sa := range ledger.SystemAccounts {
-		currency := "USD"
-		if sa.ID == "~fx" {
-			currency = "*"
-		}
 		_, err := tx.ExecContext(ctx,
 			`INSERT OR IGNORE INTO accounts (id, name, code, category, currency, is_system) VALUES (?, ?, ?, ?, ?, 1)`,
-			sa.ID, sa.Name, sa.Code, string(sa.Category), currency,
+			sa.ID, sa.Name, sa.Code, string(sa.Category), ledger.SystemAccountCurrency(sa.ID, reporting),
 		)
 		if err != nil {
 			return fmt.Errorf("seed system account %s: %w", sa.ID, err)
@@ -259,7 +363,595 @@ func migrateV2(ctx context.Context, tx *sql.Tx) error {
 
 	for _, stmt := range stmts {
 		if _, err := tx.ExecContext(ctx, stmt); err != nil {
-			return fmt.Errorf("exec %q: %w", stmt[:60], err)
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV3(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Links a reversing transaction to the original it reverses.
+		// original_id is the primary key, so a transaction can only be reversed once.
+		`CREATE TABLE IF NOT EXISTS transaction_reversals (
+			original_id TEXT PRIMARY KEY REFERENCES transactions(id),
+			reversal_id TEXT NOT NULL UNIQUE REFERENCES transactions(id),
+			reason      TEXT NOT NULL DEFAULT '',
+			created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (3)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV4(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Client-supplied idempotency keys for transaction creation.
+		// request_hash fingerprints the payload so a reused key with a
+		// different body can be rejected instead of silently replayed.
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			key            TEXT PRIMARY KEY,
+			transaction_id TEXT NOT NULL REFERENCES transactions(id),
+			request_hash   TEXT NOT NULL,
+			created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (4)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV5(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Accounting periods, each covering [starts_at, ends_at).
+		`CREATE TABLE IF NOT EXISTS periods (
+			id         TEXT PRIMARY KEY,
+			starts_at  TEXT NOT NULL,
+			ends_at    TEXT NOT NULL,
+			status     TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open','closing','closed')),
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			closed_at  TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_periods_range ON periods(starts_at, ends_at)`,
+
+		// Trigger: prevent finalizing a transaction dated inside a closed period.
+		// julianday() normalises RFC 3339 timestamps with varying fractional digits.
+		`CREATE TRIGGER IF NOT EXISTS trg_period_closed
+		BEFORE UPDATE OF finalized ON transactions
+		WHEN NEW.finalized = 1
+		BEGIN
+			SELECT CASE
+				WHEN EXISTS (
+					SELECT 1 FROM periods p
+					WHERE p.status = 'closed'
+					  AND julianday(NEW.posted_at) >= julianday(p.starts_at)
+					  AND julianday(NEW.posted_at) < julianday(p.ends_at)
+				)
+				THEN RAISE(ABORT, 'posting date falls in a closed period')
+			END;
+		END`,
+
+		// Trigger: closed periods are locked and cannot be reopened or resized.
+		`CREATE TRIGGER IF NOT EXISTS trg_period_locked
+		BEFORE UPDATE ON periods
+		WHEN OLD.status = 'closed'
+		BEGIN
+			SELECT RAISE(ABORT, 'closed periods cannot be modified');
+		END`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (5)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV6(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Year-end closing transactions, one per fiscal year and currency.
+		`CREATE TABLE IF NOT EXISTS year_closings (
+			year           INTEGER NOT NULL,
+			currency       TEXT NOT NULL,
+			transaction_id TEXT NOT NULL UNIQUE REFERENCES transactions(id),
+			created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			PRIMARY KEY (year, currency)
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (6)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV7(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Keyset pagination walks transactions newest first by (posted_at, id).
+		`CREATE INDEX IF NOT EXISTS idx_transactions_posted_key ON transactions(julianday(posted_at), id)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (7)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV8(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Two-phase transactions: the entries stay unfinalized until capture.
+		`CREATE TABLE IF NOT EXISTS pending_transactions (
+			transaction_id TEXT PRIMARY KEY REFERENCES transactions(id),
+			status         TEXT NOT NULL DEFAULT 'pending'
+			               CHECK (status IN ('pending', 'posted', 'voided', 'expired')),
+			expires_at     TEXT NOT NULL,
+			created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			resolved_at    TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_pending_status ON pending_transactions(status, expires_at)`,
+
+		// Trigger: a hold can only be finalized once it has been captured
+		`CREATE TRIGGER IF NOT EXISTS trg_pending_finalize
+		BEFORE UPDATE OF finalized ON transactions
+		WHEN NEW.finalized = 1 AND EXISTS (
+			SELECT 1 FROM pending_transactions WHERE transaction_id = NEW.id AND status != 'posted'
+		)
+		BEGIN
+			SELECT RAISE(ABORT, 'pending transaction must be captured before it is finalized');
+		END`,
+
+		// Trigger: resolved holds are final
+		`CREATE TRIGGER IF NOT EXISTS trg_pending_resolved
+		BEFORE UPDATE OF status ON pending_transactions
+		WHEN OLD.status != 'pending'
+		BEGIN
+			SELECT RAISE(ABORT, 'pending transaction is already resolved');
+		END`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (8)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV9(ctx context.Context, tx *sql.Tx, reporting string) error {
+	stmts := []string{
+		// FX mid-rates to the reporting currency, effective from effective_at.
+		`CREATE TABLE IF NOT EXISTS fx_rates (
+			currency     TEXT NOT NULL,
+			rate         REAL NOT NULL CHECK (rate > 0),
+			effective_at TEXT NOT NULL,
+			created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			PRIMARY KEY (currency, effective_at)
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (9)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	// Seed the former hardcoded rates as effective since the epoch, so
+	// existing ledgers report the same GEL totals until new rates are set.
+	// New ledgers get them crossed into their reporting currency.
+	epoch := time.Unix(0, 0).UTC().Format(time.RFC3339Nano)
+	for ccy, rate := range ledger.IndicativeRates(reporting) {
+		if ccy == reporting {
+			continue
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO fx_rates (currency, rate, effective_at) VALUES (?, ?, ?)`,
+			ccy, rate, epoch,
+		)
+		if err != nil {
+			return fmt.Errorf("seed fx rate %s: %w", ccy, err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV10(ctx context.Context, tx *sql.Tx, reporting string) error {
+	stmts := []string{
+		// One row per FX revaluation run, with the transaction it posted.
+		`CREATE TABLE IF NOT EXISTS fx_revaluations (
+			id             TEXT PRIMARY KEY,
+			as_of          TEXT NOT NULL,
+			transaction_id TEXT REFERENCES transactions(id),
+			gain           INTEGER NOT NULL DEFAULT 0,
+			loss           INTEGER NOT NULL DEFAULT 0,
+			created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+		)`,
+
+		// Audit trail: the balance, rate and reporting-currency values used for each account.
+		`CREATE TABLE IF NOT EXISTS fx_revaluation_lines (
+			revaluation_id    TEXT NOT NULL REFERENCES fx_revaluations(id),
+			account_id        TEXT NOT NULL REFERENCES accounts(id),
+			currency          TEXT NOT NULL,
+			balance           INTEGER NOT NULL,
+			rate              REAL NOT NULL,
+			rate_effective_at TEXT NOT NULL,
+			carrying_amount   INTEGER NOT NULL,
+			revalued_amount   INTEGER NOT NULL,
+			adjustment        INTEGER NOT NULL,
+			PRIMARY KEY (revaluation_id, account_id, currency)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_fx_revaluation_lines_account ON fx_revaluation_lines(account_id, currency)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (10)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	// Existing ledgers were seeded before the revaluation accounts existed.
+	for _, sa := range ledger.SystemAccounts {
+		_, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO accounts (id, name, code, category, currency, is_system) VALUES (?, ?, ?, ?, ?, 1)`,
+			sa.ID, sa.Name, sa.Code, string(sa.Category), ledger.SystemAccountCurrency(sa.ID, reporting),
+		)
+		if err != nil {
+			return fmt.Errorf("seed system account %s: %w", sa.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV11(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Currency registry; only enabled currencies are accepted for new
+		// accounts and rates.
+		`CREATE TABLE IF NOT EXISTS currencies (
+			code     TEXT PRIMARY KEY CHECK (length(code) = 3 AND code = upper(code)),
+			name     TEXT NOT NULL,
+			exponent INTEGER NOT NULL CHECK (exponent BETWEEN 0 AND 4),
+			enabled  INTEGER NOT NULL DEFAULT 0
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (11)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	for _, c := range ledger.ISO4217 {
+		_, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO currencies (code, name, exponent) VALUES (?, ?, ?)`,
+			c.Code, c.Name, c.Exponent,
+		)
+		if err != nil {
+			return fmt.Errorf("seed currency %s: %w", c.Code, err)
+		}
+	}
+
+	// Enable the defaults plus anything an existing ledger already uses.
+	for _, code := range ledger.DefaultCurrencies {
+		if _, err := tx.ExecContext(ctx, `UPDATE currencies SET enabled = 1 WHERE code = ?`, code); err != nil {
+			return fmt.Errorf("enable currency %s: %w", code, err)
+		}
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE currencies SET enabled = 1
+		WHERE code IN (SELECT currency FROM accounts UNION SELECT currency FROM entries)`)
+	if err != nil {
+		return fmt.Errorf("enable used currencies: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV12(ctx context.Context, tx *sql.Tx, reporting string) error {
+	stmts := []string{
+		// Ledger-wide settings fixed at creation, such as the reporting currency.
+		`CREATE TABLE IF NOT EXISTS ledger_meta (
+			key   TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (12)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO ledger_meta (key, value) VALUES ('reporting_currency', ?)`, reporting)
+	if err != nil {
+		return fmt.Errorf("record reporting currency: %w", err)
+	}
+
+	return nil
+}
+
+func migrateV13(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Tamper-evident hash chain over finalized transactions, in the
+		// order they were finalized.
+		`CREATE TABLE IF NOT EXISTS transaction_chain (
+			seq            INTEGER PRIMARY KEY AUTOINCREMENT,
+			transaction_id TEXT NOT NULL UNIQUE REFERENCES transactions(id),
+			prev_hash      TEXT NOT NULL,
+			hash           TEXT NOT NULL,
+			created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+		)`,
+
+		// Trigger: chain links are append-only
+		`CREATE TRIGGER IF NOT EXISTS trg_chain_immutable_update
+		BEFORE UPDATE ON transaction_chain
+		BEGIN
+			SELECT RAISE(ABORT, 'hash chain links cannot be modified');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_chain_immutable_delete
+		BEFORE DELETE ON transaction_chain
+		BEGIN
+			SELECT RAISE(ABORT, 'hash chain links cannot be removed');
+		END`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (13)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	// Chain the existing history in the order it was written.
+	var ids []string
+	err := eachRow(ctx, tx,
+		`SELECT id FROM transactions WHERE finalized = 1 ORDER BY rowid`,
+		func(rows *sql.Rows) error {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("list finalized transactions: %w", err)
+	}
+	for _, id := range ids {
+		if err := appendChain(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrateV14(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Append-only log of changes to accounts, settings, currencies,
+		// periods and rates, with the target's JSON before and after.
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id        INTEGER PRIMARY KEY AUTOINCREMENT,
+			at        TEXT NOT NULL,
+			actor     TEXT NOT NULL,
+			operation TEXT NOT NULL,
+			target    TEXT NOT NULL,
+			before    TEXT,
+			after     TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_operation ON audit_log(operation, id)`,
+
+		// Trigger: audit log entries are append-only
+		`CREATE TRIGGER IF NOT EXISTS trg_audit_log_immutable_update
+		BEFORE UPDATE ON audit_log
+		BEGIN
+			SELECT RAISE(ABORT, 'audit log entries cannot be modified');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_audit_log_immutable_delete
+		BEFORE DELETE ON audit_log
+		BEGIN
+			SELECT RAISE(ABORT, 'audit log entries cannot be removed');
+		END`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (14)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV15(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// API keys; only the SHA-256 of each token is stored
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id         TEXT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			role       TEXT NOT NULL CHECK (role IN ('reader', 'poster', 'admin')),
+			token_hash TEXT NOT NULL UNIQUE,
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			revoked_at TEXT
+		)`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (15)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV16(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Maker-checker queue of transactions parked above a code's
+		// APPROVAL_THRESHOLD; hold is set when the maker asked for a hold,
+		// which is placed rather than posted once approved
+		`CREATE TABLE IF NOT EXISTS approvals (
+			transaction_id  TEXT PRIMARY KEY REFERENCES transactions(id),
+			status          TEXT NOT NULL DEFAULT 'pending'
+			                CHECK (status IN ('pending', 'approved', 'rejected')),
+			reason          TEXT NOT NULL,
+			requested_by    TEXT NOT NULL,
+			requested_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			hold            INTEGER NOT NULL DEFAULT 0,
+			hold_expires_at TEXT,
+			decided_by      TEXT,
+			decided_at      TEXT,
+			comment         TEXT,
+			CHECK (status != 'approved' OR decided_by != requested_by)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_approvals_status ON approvals(status, requested_at)`,
+
+		// Trigger: a parked transaction can only be finalized once approved
+		`CREATE TRIGGER IF NOT EXISTS trg_approval_finalize
+		BEFORE UPDATE OF finalized ON transactions
+		WHEN NEW.finalized = 1 AND EXISTS (
+			SELECT 1 FROM approvals WHERE transaction_id = NEW.id AND status != 'approved'
+		)
+		BEGIN
+			SELECT RAISE(ABORT, 'transaction must be approved before it is finalized');
+		END`,
+
+		// Trigger: approval decisions are final
+		`CREATE TRIGGER IF NOT EXISTS trg_approval_decided
+		BEFORE UPDATE ON approvals
+		WHEN OLD.status != 'pending'
+		BEGIN
+			SELECT RAISE(ABORT, 'approval has already been decided');
+		END`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (16)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
+		}
+	}
+
+	return nil
+}
+
+func migrateV17(ctx context.Context, tx *sql.Tx) error {
+	stmts := []string{
+		// Transactional outbox: one row per event, written in the same SQL
+		// transaction as the posting it describes
+		`CREATE TABLE IF NOT EXISTS outbox (
+			id             INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_type     TEXT NOT NULL,
+			transaction_id TEXT NOT NULL REFERENCES transactions(id),
+			payload        TEXT NOT NULL,
+			created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+		)`,
+
+		// Webhook subscribers; every event is queued for each of them
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id         TEXT PRIMARY KEY,
+			url        TEXT NOT NULL,
+			secret     TEXT NOT NULL,
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+		)`,
+
+		// One delivery per webhook and event, retried with backoff until it
+		// succeeds or runs out of attempts
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			webhook_id      TEXT NOT NULL REFERENCES webhooks(id),
+			event_id        INTEGER NOT NULL REFERENCES outbox(id),
+			status          TEXT NOT NULL DEFAULT 'pending'
+			                CHECK (status IN ('pending', 'delivered', 'failed')),
+			attempts        INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+			last_error      TEXT,
+			delivered_at    TEXT,
+			PRIMARY KEY (webhook_id, event_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at)`,
+
+		// Triggers: the outbox is append-only
+		`CREATE TRIGGER IF NOT EXISTS trg_outbox_immutable_update
+		BEFORE UPDATE ON outbox
+		BEGIN
+			SELECT RAISE(ABORT, 'outbox events are immutable');
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS trg_outbox_immutable_delete
+		BEFORE DELETE ON outbox
+		BEGIN
+			SELECT RAISE(ABORT, 'outbox events are immutable');
+		END`,
+
+		// Record schema version
+		`INSERT INTO schema_version (version) VALUES (17)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("exec %q: %w", stmtPrefix(stmt), err)
 		}
 	}
 
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// pageSize clamps a requested page size to [1, maxPageSize], defaulting
+// when unset.
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// Cursors are opaque to callers: base64 of the sort key of the last row on
+// the previous page. Transactions sort by (posted_at, id), entries by id.
+
+func encodeTxnCursor(postedAt, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(postedAt + "\x1f" + id))
+}
+
+func decodeTxnCursor(cursor string) (postedAt, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %q", ledger.ErrInvalidCursor, cursor)
+	}
+	postedAt, id, ok := strings.Cut(string(raw), "\x1f")
+	if !ok || postedAt == "" || id == "" {
+		return "", "", fmt.Errorf("%w: %q", ledger.ErrInvalidCursor, cursor)
+	}
+	return postedAt, id, nil
+}
+
+func encodeEntryCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeEntryCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ledger.ErrInvalidCursor, cursor)
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: %q", ledger.ErrInvalidCursor, cursor)
+	}
+	return id, nil
+}
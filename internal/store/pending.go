@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// holdColumns selects the pending_transactions fields scanned by holdRow.
+// The query must LEFT JOIN pending_transactions as p.
+const holdColumns = `COALESCE(p.status, ''), COALESCE(p.expires_at, ''),
+	COALESCE(p.status = 'pending' AND julianday(p.expires_at) <= julianday('now'), 0)`
+
+// holdRow is the pending state of a transaction as read from the database.
+type holdRow struct {
+	status    string
+	expiresAt string
+	expired   bool // still pending but past expiry, not yet swept
+}
+
+func (h holdRow) apply(txn *ledger.Transaction) {
+	if h.status == "" {
+		return
+	}
+	txn.Status = ledger.PendingStatus(h.status)
+	if h.expired {
+		txn.Status = ledger.StatusExpired
+	}
+	if t, err := time.Parse(time.RFC3339Nano, h.expiresAt); err == nil {
+		txn.ExpiresAt = &t
+	}
+}
+
+// insertHold records txn as a pending transaction. Its entries are already
+// written but stay unfinalized until FinalizeTransaction.
+func insertHold(ctx context.Context, tx *sql.Tx, txn *ledger.Transaction) error {
+	if txn.ExpiresAt == nil {
+		exp := time.Now().UTC().Add(ledger.DefaultHoldTTL)
+		txn.ExpiresAt = &exp
+	}
+	if !txn.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: %s", ledger.ErrInvalidExpiry, txn.ExpiresAt.Format(time.RFC3339))
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO pending_transactions (transaction_id, expires_at) VALUES (?, ?)`,
+		txn.ID, txn.ExpiresAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("insert pending transaction: %w", err)
+	}
+	return nil
+}
+
+// pendingAmounts sums the open holds on accountID, split into debits and
+// credits (negative). Expired holds are ignored even before the sweeper
+// marks them. excludeTxnID leaves one transaction out, for re-checking a
+// hold that is being captured.
+func pendingAmounts(ctx context.Context, q queryRower, accountID, excludeTxnID string) (debits, credits int64, err error) {
+	err = q.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(CASE WHEN e.amount > 0 THEN e.amount END), 0),
+			COALESCE(SUM(CASE WHEN e.amount < 0 THEN e.amount END), 0)
+		FROM entries e
+		JOIN pending_transactions p ON p.transaction_id = e.transaction_id
+		WHERE e.account_id = ? AND e.transaction_id != ?
+			AND p.status = 'pending' AND julianday(p.expires_at) > julianday('now')`,
+		accountID, excludeTxnID,
+	).Scan(&debits, &credits)
+	if err != nil {
+		return 0, 0, fmt.Errorf("pending amounts for %s: %w", accountID, err)
+	}
+	return debits, credits, nil
+}
+
+// PendingAmounts returns the open hold debits and credits on accountID.
+func (s *Store) PendingAmounts(ctx context.Context, accountID string) (debits, credits int64, err error) {
+	return pendingAmounts(ctx, s.reader, accountID, "")
+}
+
+// FinalizeTransaction captures a pending transaction: it is re-dated to
+// now, re-checked against the code settings and finalized.
+func (s *Store) FinalizeTransaction(ctx context.Context, id string) (*ledger.Transaction, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := resolveHold(ctx, tx, id, ledger.StatusPosted); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := checkPeriodOpen(ctx, tx, now); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE transactions SET posted_at = ? WHERE id = ?`, now.Format(time.RFC3339Nano), id)
+	if err != nil {
+		return nil, fmt.Errorf("date transaction: %w", err)
+	}
+
+	if err := finalizeExisting(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetTransaction(ctx, id)
+}
+
+// recheckTransaction reloads the entries of the unfinalized transaction id
+// and checks them against the code settings again, since balances may have
+// moved since it was written.
+func recheckTransaction(ctx context.Context, tx *sql.Tx, id string) (*ledger.Transaction, error) {
+	txn := &ledger.Transaction{ID: id}
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, transaction_id, account_id, amount, currency, created_at FROM entries WHERE transaction_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get entries: %w", err)
+	}
+	txn.Entries, err = scanEntries(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := loadAccountRules(ctx, tx, txn.Entries)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInvertedBalances(ctx, tx, txn, rules); err != nil {
+		return nil, err
+	}
+	return txn, nil
+}
+
+// finalizeExisting re-checks and finalizes a transaction that was written
+// unfinalized, appends it to the hash chain and publishes it.
+func finalizeExisting(ctx context.Context, tx *sql.Tx, id string) error {
+	if _, err := recheckTransaction(ctx, tx, id); err != nil {
+		return err
+	}
+
+	// Finalize - triggers fire to validate balance and capture state
+	_, err := tx.ExecContext(ctx,
+		`UPDATE transactions SET finalized = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("finalize transaction: %w", err)
+	}
+	if err := appendChain(ctx, tx, id); err != nil {
+		return err
+	}
+	return publishPosted(ctx, tx, id)
+}
+
+// VoidTransaction releases a pending transaction without posting it.
+func (s *Store) VoidTransaction(ctx context.Context, id string) (*ledger.Transaction, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := resolveHold(ctx, tx, id, ledger.StatusVoided); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.GetTransaction(ctx, id)
+}
+
+// resolveHold moves the hold on id out of pending. A hold past its expiry
+// can no longer be captured or voided.
+func resolveHold(ctx context.Context, tx *sql.Tx, id string, to ledger.PendingStatus) error {
+	var status ledger.PendingStatus
+	var expired bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT status, julianday(expires_at) <= julianday('now') FROM pending_transactions WHERE transaction_id = ?`, id,
+	).Scan(&status, &expired)
+	if err == sql.ErrNoRows {
+		var exists int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM transactions WHERE id = ?`, id).Scan(&exists); err != nil {
+			return fmt.Errorf("lookup transaction: %w", err)
+		}
+		if exists == 0 {
+			return ledger.ErrTransactionNotFound
+		}
+		return fmt.Errorf("%w: %s was posted directly", ledger.ErrNotPending, id)
+	}
+	if err != nil {
+		return fmt.Errorf("lookup pending transaction: %w", err)
+	}
+	if status == ledger.StatusPending && expired {
+		status = ledger.StatusExpired
+	}
+	if status != ledger.StatusPending {
+		return fmt.Errorf("%w: %s is %s", ledger.ErrNotPending, id, status)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE pending_transactions SET status = ?, resolved_at = ? WHERE transaction_id = ?`,
+		to, time.Now().UTC().Format(time.RFC3339Nano), id)
+	if err != nil {
+		return fmt.Errorf("resolve pending transaction: %w", err)
+	}
+	return nil
+}
+
+// ExpirePending marks every hold whose expiry has passed as expired and
+// returns how many were released.
+func (s *Store) ExpirePending(ctx context.Context) (int, error) {
+	res, err := s.writer.ExecContext(ctx,
+		`UPDATE pending_transactions SET status = 'expired', resolved_at = ?
+		WHERE status = 'pending' AND julianday(expires_at) <= julianday('now')`,
+		time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, fmt.Errorf("expire pending transactions: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
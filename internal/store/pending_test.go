@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// holdStep acts on the hold id.
+type holdStep func(st *Store, id string) error
+
+func TestHoldLifecycle(t *testing.T) {
+	ctx := context.Background()
+	var capture holdStep = func(st *Store, id string) error { _, err := st.FinalizeTransaction(ctx, id); return err }
+	var void holdStep = func(st *Store, id string) error { _, err := st.VoidTransaction(ctx, id); return err }
+	// lapse moves the hold's expiry into the past without sweeping it.
+	var lapse holdStep = func(st *Store, id string) error {
+		_, err := st.writer.Exec(`UPDATE pending_transactions SET expires_at = ? WHERE transaction_id = ?`,
+			time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano), id)
+		return err
+	}
+	var sweep holdStep = func(st *Store, id string) error {
+		n, err := st.ExpirePending(ctx)
+		if err == nil && n != 1 {
+			return fmt.Errorf("ExpirePending released %d holds, want 1", n)
+		}
+		return err
+	}
+
+	tests := []struct {
+		name    string
+		steps   []holdStep
+		want    error // of the last step
+		status  ledger.PendingStatus
+		settled int64
+		pending int64 // open hold debits on ~settlement
+	}{
+		{"open", nil, nil, ledger.StatusPending, 0, 1000},
+		{"captured", []holdStep{capture}, nil, ledger.StatusPosted, 1000, 0},
+		{"voided", []holdStep{void}, nil, ledger.StatusVoided, 0, 0},
+		{"captured twice", []holdStep{capture, capture}, ledger.ErrNotPending, ledger.StatusPosted, 1000, 0},
+		{"voided after capture", []holdStep{capture, void}, ledger.ErrNotPending, ledger.StatusPosted, 1000, 0},
+		{"captured after void", []holdStep{void, capture}, ledger.ErrNotPending, ledger.StatusVoided, 0, 0},
+		{"lapsed", []holdStep{lapse}, nil, ledger.StatusExpired, 0, 0},
+		{"captured after lapsing", []holdStep{lapse, capture}, ledger.ErrNotPending, ledger.StatusExpired, 0, 0},
+		{"voided after lapsing", []holdStep{lapse, void}, ledger.ErrNotPending, ledger.StatusExpired, 0, 0},
+		{"swept", []holdStep{lapse, sweep}, nil, ledger.StatusExpired, 0, 0},
+		{"captured after sweep", []holdStep{lapse, sweep, capture}, ledger.ErrNotPending, ledger.StatusExpired, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := openTestStore(t)
+			id := mustCreate(t, st, testHold("auth", 1000)).ID
+
+			var err error
+			for _, step := range tt.steps {
+				err = step(st, id)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+
+			txn, err := st.GetTransaction(ctx, id)
+			if err != nil {
+				t.Fatalf("GetTransaction: %v", err)
+			}
+			if txn.Status != tt.status || txn.Finalized != (tt.status == ledger.StatusPosted) {
+				t.Errorf("status %s, finalized %v; want %s", txn.Status, txn.Finalized, tt.status)
+			}
+			if got := settled(t, st); got != tt.settled {
+				t.Errorf("~settlement = %d, want %d", got, tt.settled)
+			}
+			debits, _, err := st.PendingAmounts(ctx, "~settlement")
+			if err != nil {
+				t.Fatalf("PendingAmounts: %v", err)
+			}
+			if debits != tt.pending {
+				t.Errorf("pending debits = %d, want %d", debits, tt.pending)
+			}
+		})
+	}
+}
+
+func TestHoldRefusals(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t)
+
+	past := time.Now().Add(-time.Minute)
+	hold := testHold("auth", 1000)
+	hold.ExpiresAt = &past
+	if err := st.CreateTransaction(ctx, hold); !errors.Is(err, ledger.ErrInvalidExpiry) {
+		t.Errorf("hold expiring in the past: error = %v, want ErrInvalidExpiry", err)
+	}
+
+	posted := mustCreate(t, st, testTransaction("sale", 1000))
+	if _, err := st.FinalizeTransaction(ctx, posted.ID); !errors.Is(err, ledger.ErrNotPending) {
+		t.Errorf("capturing a posted transaction: error = %v, want ErrNotPending", err)
+	}
+	if _, err := st.VoidTransaction(ctx, "missing"); !errors.Is(err, ledger.ErrTransactionNotFound) {
+		t.Errorf("voiding a missing transaction: error = %v, want ErrTransactionNotFound", err)
+	}
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// CreatePeriod opens a new accounting period. Periods may not overlap.
+func (s *Store) CreatePeriod(ctx context.Context, p *ledger.Period) error {
+	p.StartsAt = p.StartsAt.UTC()
+	p.EndsAt = p.EndsAt.UTC()
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var overlapping string
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM periods
+		WHERE julianday(starts_at) < julianday(?) AND julianday(ends_at) > julianday(?)
+		LIMIT 1`,
+		p.EndsAt.Format(time.RFC3339Nano), p.StartsAt.Format(time.RFC3339Nano),
+	).Scan(&overlapping)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("check period overlap: %w", err)
+	}
+	if overlapping != "" {
+		return fmt.Errorf("%w: %s overlaps %s", ledger.ErrPeriodOverlap, p.ID, overlapping)
+	}
+
+	p.Status = ledger.PeriodOpen
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO periods (id, starts_at, ends_at, status) VALUES (?, ?, ?, ?)`,
+		p.ID, p.StartsAt.Format(time.RFC3339Nano), p.EndsAt.Format(time.RFC3339Nano), string(p.Status),
+	)
+	if err != nil {
+		return fmt.Errorf("insert period: %w", err)
+	}
+	created, err := getPeriod(ctx, tx, p.ID)
+	if err != nil {
+		return err
+	}
+	if err := logChange(ctx, tx, ledger.OpPeriodCreate, p.ID, nil, created); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetPeriod(ctx context.Context, id string) (*ledger.Period, error) {
+	row := s.reader.QueryRowContext(ctx,
+		`SELECT id, starts_at, ends_at, status, created_at, closed_at FROM periods WHERE id = ?`, id)
+	p, err := scanPeriod(row)
+	if err == sql.ErrNoRows {
+		return nil, ledger.ErrPeriodNotFound
+	}
+	return p, err
+}
+
+func (s *Store) ListPeriods(ctx context.Context) ([]ledger.Period, error) {
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT id, starts_at, ends_at, status, created_at, closed_at FROM periods ORDER BY julianday(starts_at)`)
+	if err != nil {
+		return nil, fmt.Errorf("list periods: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []ledger.Period
+	for rows.Next() {
+		p, err := scanPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, *p)
+	}
+	return periods, rows.Err()
+}
+
+// SetPeriodStatus moves a period through open → closing → closed.
+// Closing a period locks its books: later postings dated inside it are rejected.
+func (s *Store) SetPeriodStatus(ctx context.Context, id string, status ledger.PeriodStatus) (*ledger.Period, error) {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	p, err := getPeriod(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ledger.CanTransition(p.Status, status) {
+		return nil, fmt.Errorf("%w: %s is %s, cannot move to %s", ledger.ErrInvalidPeriodTransition, id, p.Status, status)
+	}
+
+	var closedAt any
+	if status == ledger.PeriodClosed {
+		closedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE periods SET status = ?, closed_at = ? WHERE id = ?`, string(status), closedAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("update period: %w", err)
+	}
+	after, err := getPeriod(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := logChange(ctx, tx, ledger.OpPeriodStatus, id, p, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return after, nil
+}
+
+func getPeriod(ctx context.Context, tx *sql.Tx, id string) (*ledger.Period, error) {
+	row := tx.QueryRowContext(ctx,
+		`SELECT id, starts_at, ends_at, status, created_at, closed_at FROM periods WHERE id = ?`, id)
+	p, err := scanPeriod(row)
+	if err == sql.ErrNoRows {
+		return nil, ledger.ErrPeriodNotFound
+	}
+	return p, err
+}
+
+// checkPeriodOpen rejects postings dated inside a closed period. The
+// trg_period_closed trigger enforces the same rule at finalization.
+func checkPeriodOpen(ctx context.Context, tx *sql.Tx, postedAt time.Time) error {
+	var id string
+	err := tx.QueryRowContext(ctx,
+		`SELECT id FROM periods
+		WHERE status = 'closed'
+		  AND julianday(?) >= julianday(starts_at) AND julianday(?) < julianday(ends_at)
+		LIMIT 1`,
+		postedAt.Format(time.RFC3339Nano), postedAt.Format(time.RFC3339Nano),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check period: %w", err)
+	}
+	return fmt.Errorf("%w: %s is in period %s", ledger.ErrPeriodClosed, postedAt.Format(time.RFC3339), id)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPeriod(row rowScanner) (*ledger.Period, error) {
+	var p ledger.Period
+	var startsAt, endsAt, createdAt string
+	var closedAt sql.NullString
+	if err := row.Scan(&p.ID, &startsAt, &endsAt, &p.Status, &createdAt, &closedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan period: %w", err)
+	}
+	p.StartsAt, _ = time.Parse(time.RFC3339Nano, startsAt)
+	p.EndsAt, _ = time.Parse(time.RFC3339Nano, endsAt)
+	p.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	if closedAt.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, closedAt.String)
+		p.ClosedAt = &t
+	}
+	return &p, nil
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// SetRate records the mid-rate in the reporting currency for a currency from effectiveAt onwards.
+// Setting a rate at an existing timestamp replaces it. A zero effectiveAt
+// means now.
+func (s *Store) SetRate(ctx context.Context, currency string, rate float64, effectiveAt time.Time) (*ledger.FXRate, error) {
+	if effectiveAt.IsZero() {
+		effectiveAt = time.Now()
+	}
+	r := &ledger.FXRate{Currency: currency, Rate: rate, EffectiveAt: effectiveAt.UTC()}
+	if err := s.validateRate(ctx, r); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := upsertRate(ctx, tx, r); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return r, nil
+}
+
+// ImportRates records a batch of historical rates atomically: either every
+// rate is stored or none is.
+func (s *Store) ImportRates(ctx context.Context, rates []ledger.FXRate) error {
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range rates {
+		r := &rates[i]
+		r.EffectiveAt = r.EffectiveAt.UTC()
+		if err := s.validateRate(ctx, r); err != nil {
+			return fmt.Errorf("rate %d: %w", i+1, err)
+		}
+		if err := upsertRate(ctx, tx, r); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// validateRate checks r and that its currency is enabled in this ledger
+// and is not the reporting currency.
+func (s *Store) validateRate(ctx context.Context, r *ledger.FXRate) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if r.Currency == s.reporting {
+		return fmt.Errorf("%w: %s is the reporting currency", ledger.ErrInvalidRate, r.Currency)
+	}
+	return s.requireEnabled(ctx, r.Currency)
+}
+
+// upsertRate stores r, logging the rate it replaces, if any.
+func upsertRate(ctx context.Context, tx *sql.Tx, r *ledger.FXRate) error {
+	effectiveAt := r.EffectiveAt.Format(time.RFC3339Nano)
+
+	var before *ledger.FXRate
+	var rate float64
+	err := tx.QueryRowContext(ctx,
+		`SELECT rate FROM fx_rates WHERE currency = ? AND effective_at = ?`, r.Currency, effectiveAt,
+	).Scan(&rate)
+	switch {
+	case err == nil:
+		before = &ledger.FXRate{Currency: r.Currency, Rate: rate, EffectiveAt: r.EffectiveAt}
+	case err != sql.ErrNoRows:
+		return fmt.Errorf("get fx rate %s: %w", r.Currency, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO fx_rates (currency, rate, effective_at) VALUES (?, ?, ?)
+		ON CONFLICT (currency, effective_at) DO UPDATE SET rate = excluded.rate`,
+		r.Currency, r.Rate, effectiveAt,
+	)
+	if err != nil {
+		return fmt.Errorf("set fx rate %s: %w", r.Currency, err)
+	}
+	return logChange(ctx, tx, ledger.OpFXRateSet, r.Currency+"@"+effectiveAt, before, r)
+}
+
+// RateAt returns the rate for currency effective at t (zero means now).
+// The reporting currency is always 1.
+func (s *Store) RateAt(ctx context.Context, currency string, t time.Time) (*ledger.FXRate, error) {
+	if currency == s.reporting {
+		return &ledger.FXRate{Currency: currency, Rate: 1, EffectiveAt: time.Unix(0, 0).UTC()}, nil
+	}
+	return rateAt(ctx, s.reader, currency, t)
+}
+
+// rateAt looks up the stored rate for a currency other than the reporting
+// currency.
+func rateAt(ctx context.Context, db interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}, currency string, t time.Time) (*ledger.FXRate, error) {
+	r := ledger.FXRate{Currency: currency}
+	var effectiveAt string
+	err := db.QueryRowContext(ctx,
+		`SELECT rate, effective_at FROM fx_rates
+		WHERE currency = ? AND julianday(effective_at) <= julianday(?)
+		ORDER BY julianday(effective_at) DESC LIMIT 1`,
+		currency, rateTime(t),
+	).Scan(&r.Rate, &effectiveAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s at %s", ledger.ErrRateNotFound, currency, rateTime(t))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rate at: %w", err)
+	}
+	r.EffectiveAt, _ = time.Parse(time.RFC3339Nano, effectiveAt)
+	return &r, nil
+}
+
+// RatesAt returns the rate of every currency effective at t (zero means
+// now), including the reporting currency at 1.
+func (s *Store) RatesAt(ctx context.Context, t time.Time) (ledger.RateTable, error) {
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT r.currency, r.rate FROM fx_rates r
+		WHERE julianday(r.effective_at) = (
+			SELECT MAX(julianday(effective_at)) FROM fx_rates
+			WHERE currency = r.currency AND julianday(effective_at) <= julianday(?)
+		)`, rateTime(t))
+	if err != nil {
+		return nil, fmt.Errorf("rates at: %w", err)
+	}
+	defer rows.Close()
+
+	table := ledger.RateTable{s.reporting: 1}
+	for rows.Next() {
+		var ccy string
+		var rate float64
+		if err := rows.Scan(&ccy, &rate); err != nil {
+			return nil, fmt.Errorf("scan rate: %w", err)
+		}
+		table[ccy] = rate
+	}
+	return table, rows.Err()
+}
+
+// ListRates returns the rate history, newest first, optionally for a
+// single currency.
+func (s *Store) ListRates(ctx context.Context, currency string) ([]ledger.FXRate, error) {
+	query := `SELECT currency, rate, effective_at FROM fx_rates`
+	var args []any
+	if currency != "" {
+		query += ` WHERE currency = ?`
+		args = append(args, currency)
+	}
+	query += ` ORDER BY currency, julianday(effective_at) DESC`
+
+	rows, err := s.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []ledger.FXRate
+	for rows.Next() {
+		var r ledger.FXRate
+		var effectiveAt string
+		if err := rows.Scan(&r.Currency, &r.Rate, &effectiveAt); err != nil {
+			return nil, fmt.Errorf("scan rate: %w", err)
+		}
+		r.EffectiveAt, _ = time.Parse(time.RFC3339Nano, effectiveAt)
+		rates = append(rates, r)
+	}
+	return rates, rows.Err()
+}
+
+// rateTime formats the lookup time for a rate query; zero means now.
+func rateTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// Registry holds the named entities served alongside the default ledger.
+// Each entity is a database file <dir>/<name>.db, opened on first use and
+// kept open until the registry is closed.
+type Registry struct {
+	dir    string
+	mu     sync.Mutex
+	stores map[string]*Store
+}
+
+func NewRegistry(dir string) *Registry {
+	return &Registry{dir: dir, stores: map[string]*Store{}}
+}
+
+func (r *Registry) path(name string) string {
+	return filepath.Join(r.dir, name+".db")
+}
+
+// Create creates a new entity with its own ledger database.
+func (r *Registry) Create(name string, opts Options) (*Store, error) {
+	if err := ledger.ValidateEntityName(name); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := os.Stat(r.path(name)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrEntityExists, name)
+	}
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create entities directory: %w", err)
+	}
+	st, err := Open(r.path(name), opts)
+	if err != nil {
+		os.Remove(r.path(name))
+		return nil, err
+	}
+	r.stores[name] = st
+	return st, nil
+}
+
+// Get returns the store of an existing entity, opening it if needed.
+func (r *Registry) Get(name string) (*Store, error) {
+	if err := ledger.ValidateEntityName(name); err != nil {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrEntityNotFound, name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if st, ok := r.stores[name]; ok {
+		return st, nil
+	}
+	if _, err := os.Stat(r.path(name)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ledger.ErrEntityNotFound, name)
+		}
+		return nil, fmt.Errorf("open entity %s: %w", name, err)
+	}
+	st, err := Open(r.path(name), Options{})
+	if err != nil {
+		return nil, fmt.Errorf("open entity %s: %w", name, err)
+	}
+	r.stores[name] = st
+	return st, nil
+}
+
+// List returns every entity, sorted by name.
+func (r *Registry) List() ([]ledger.Entity, error) {
+	files, err := filepath.Glob(filepath.Join(r.dir, "*.db"))
+	if err != nil {
+		return nil, fmt.Errorf("list entities: %w", err)
+	}
+	entities := []ledger.Entity{}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".db")
+		if ledger.ValidateEntityName(name) != nil {
+			continue
+		}
+		st, err := r.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, ledger.Entity{Name: name, ReportingCurrency: st.ReportingCurrency()})
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].Name < entities[j].Name })
+	return entities, nil
+}
+
+// Loaded returns the entities opened so far.
+func (r *Registry) Loaded() map[string]*Store {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	open := make(map[string]*Store, len(r.stores))
+	for name, st := range r.stores {
+		open[name] = st
+	}
+	return open
+}
+
+// ConsolidatedBalanceSheet consolidates the balance sheets of the named
+// entities as of asOf (zero for now) into currency, eliminating their
+// nostro and vostro balances with each other. An empty currency means the
+// entities' shared reporting currency.
+func (r *Registry) ConsolidatedBalanceSheet(ctx context.Context, names []string, currency string, asOf time.Time) (*ledger.ConsolidatedBalanceSheet, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no entities to consolidate", ledger.ErrInvalidEntity)
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if _, ok := ledger.LookupCurrency(currency); currency != "" && !ok {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrInvalidCurrency, currency)
+	}
+	stores := make(map[string]*Store, len(names))
+	for _, name := range names {
+		st, err := r.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		stores[name] = st
+	}
+	if currency == "" {
+		for _, st := range stores {
+			if currency != "" && st.ReportingCurrency() != currency {
+				return nil, fmt.Errorf("%w: the entities report in different currencies; choose one to consolidate into", ledger.ErrInvalidCurrency)
+			}
+			currency = st.ReportingCurrency()
+		}
+	}
+
+	sheets := make(map[string]*ledger.BalanceSheet, len(stores))
+	for name, st := range stores {
+		bs, err := st.BalanceSheet(ctx, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("entity %s: %w", name, err)
+		}
+		sheets[name] = bs
+	}
+	cbs, err := ledger.Consolidate(sheets, currency)
+	if err != nil {
+		return nil, err
+	}
+	cbs.AsOf = optionalTime(asOf)
+	return cbs, nil
+}
+
+// Close closes every open entity.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var first error
+	for name, st := range r.stores {
+		if err := st.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(r.stores, name)
+	}
+	return first
+}
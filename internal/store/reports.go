@@ -2,55 +2,105 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/simonvc/miniledger/internal/ledger"
 )
 
-func (s *Store) AccountBalance(ctx context.Context, accountID string) (int64, string, error) {
+// postedEntries returns a subquery over finalized entries, limited to
+// transactions posted at or before asOf when it is non-zero, and its args.
+func postedEntries(asOf time.Time) (string, []any) {
+	q := `SELECT e.account_id, e.amount, e.currency
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id
+		WHERE t.finalized = 1`
+	if asOf.IsZero() {
+		return q, nil
+	}
+	return q + ` AND julianday(t.posted_at) <= julianday(?)`, []any{asOf.UTC().Format(time.RFC3339Nano)}
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// optionalTime returns nil for the zero time so reports omit unset bounds.
+func optionalTime(asOf time.Time) *time.Time {
+	if asOf.IsZero() {
+		return nil
+	}
+	t := asOf.UTC()
+	return &t
+}
+
+// AccountBalance returns the account's balance and currency. A zero asOf
+// means the current balance.
+func (s *Store) AccountBalance(ctx context.Context, accountID string, asOf time.Time) (int64, string, error) {
 	// Verify account exists
 	acct, err := s.GetAccount(ctx, accountID)
 	if err != nil {
 		return 0, "", err
 	}
 
+	balance, err := accountBalance(ctx, s.reader, accountID, asOf)
+	if err != nil {
+		return 0, "", err
+	}
+	return balance, acct.Currency, nil
+}
+
+// accountBalance sums the posted entries of accountID as of asOf.
+func accountBalance(ctx context.Context, db queryRower, accountID string, asOf time.Time) (int64, error) {
+	sub, args := postedEntries(asOf)
 	var balance int64
-	err = s.reader.QueryRowContext(ctx,
+	err := db.QueryRowContext(ctx,
 		`SELECT COALESCE(SUM(e.amount), 0)
-		FROM entries e
-		JOIN transactions t ON t.id = e.transaction_id
-		WHERE e.account_id = ? AND t.finalized = 1`, accountID,
+		FROM (`+sub+`) e
+		WHERE e.account_id = ?`, append(args, accountID)...,
 	).Scan(&balance)
 	if err != nil {
-		return 0, "", fmt.Errorf("account balance: %w", err)
+		return 0, fmt.Errorf("account balance: %w", err)
 	}
-
-	return balance, acct.Currency, nil
+	return balance, nil
 }
 
-func (s *Store) BalanceSheet(ctx context.Context) (*ledger.BalanceSheet, error) {
+// BalanceSheet reports balance-sheet positions, totalled in the reporting
+// currency at the rates effective at asOf. A zero asOf means the current
+// state.
+func (s *Store) BalanceSheet(ctx context.Context, asOf time.Time) (*ledger.BalanceSheet, error) {
+	rates, err := s.RatesAt(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, args := postedEntries(asOf)
 	rows, err := s.reader.QueryContext(ctx,
-		`SELECT a.id, a.name, a.category, a.currency, COALESCE(SUM(e.amount), 0) as balance
+		`SELECT a.id, a.name, a.code, a.category, a.currency, COALESCE(SUM(e.amount), 0) as balance
 		FROM accounts a
-		LEFT JOIN entries e ON e.account_id = a.id
-		LEFT JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+		LEFT JOIN (`+sub+`) e ON e.account_id = a.id
 		GROUP BY a.id
 		HAVING balance != 0
-		ORDER BY a.code`)
+		ORDER BY a.code`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("balance sheet query: %w", err)
 	}
 	defer rows.Close()
 
 	bs := &ledger.BalanceSheet{
-		GeneratedAt: time.Now().UTC(),
+		GeneratedAt:       time.Now().UTC(),
+		AsOf:              optionalTime(asOf),
+		ReportingCurrency: s.reporting,
+		Rates:             rates,
 	}
 
 	for rows.Next() {
 		var line ledger.BalanceSheetLine
 		var category string
-		if err := rows.Scan(&line.AccountID, &line.AccountName, &category, &line.Currency, &line.Balance); err != nil {
+		if err := rows.Scan(&line.AccountID, &line.AccountName, &line.Code, &category, &line.Currency, &line.Balance); err != nil {
 			return nil, fmt.Errorf("scan balance sheet: %w", err)
 		}
 
@@ -60,17 +110,20 @@ func (s *Store) BalanceSheet(ctx context.Context) (*ledger.BalanceSheet, error)
 			continue
 		}
 
-		gelAmt := ledger.ToGEL(line.Balance, line.Currency)
+		amt, err := rates.Convert(line.Balance, line.Currency, s.reporting)
+		if err != nil {
+			return nil, fmt.Errorf("balance sheet: %s: %w", line.AccountID, err)
+		}
 		switch ledger.Category(category) {
 		case ledger.CategoryAssets:
 			bs.Assets = append(bs.Assets, line)
-			bs.TotalAssets += gelAmt
+			bs.TotalAssets += amt
 		case ledger.CategoryLiabilities:
 			bs.Liabilities = append(bs.Liabilities, line)
-			bs.TotalLiabilities += gelAmt
+			bs.TotalLiabilities += amt
 		case ledger.CategoryEquity:
 			bs.Equity = append(bs.Equity, line)
-			bs.TotalEquity += gelAmt
+			bs.TotalEquity += amt
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -79,7 +132,7 @@ func (s *Store) BalanceSheet(ctx context.Context) (*ledger.BalanceSheet, error)
 
 	// Balanced check: for every currency, the sum of all entries must be zero.
 	// This is the fundamental double-entry invariant (enforced per-txn by trigger).
-	bs.Balanced, err = s.isBalancedPerCurrency(ctx)
+	bs.Balanced, err = s.isBalancedPerCurrency(ctx, asOf)
 	if err != nil {
 		return nil, err
 	}
@@ -89,43 +142,58 @@ func (s *Store) BalanceSheet(ctx context.Context) (*ledger.BalanceSheet, error)
 
 // isBalancedPerCurrency returns true when, for every currency, the global sum
 // of all finalized entries is zero. This is the double-entry invariant.
-func (s *Store) isBalancedPerCurrency(ctx context.Context) (bool, error) {
+func (s *Store) isBalancedPerCurrency(ctx context.Context, asOf time.Time) (bool, error) {
+	sub, args := postedEntries(asOf)
 	var count int
 	err := s.reader.QueryRowContext(ctx,
 		`SELECT COUNT(*) FROM (
 			SELECT e.currency, SUM(e.amount) as bal
-			FROM entries e
-			JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+			FROM (`+sub+`) e
 			GROUP BY e.currency
 			HAVING bal != 0
-		)`).Scan(&count)
+		)`, args...).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("balance check: %w", err)
 	}
 	return count == 0, nil
 }
 
-func (s *Store) RegulatoryRatios(ctx context.Context) (*ledger.RegulatoryRatios, error) {
+// RegulatoryRatios computes prudential ratios from balances converted to
+// the reporting currency at the rates effective at asOf. A zero asOf means
+// the current state.
+func (s *Store) RegulatoryRatios(ctx context.Context, asOf time.Time) (*ledger.RegulatoryRatios, error) {
+	rates, err := s.RatesAt(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, args := postedEntries(asOf)
 	rows, err := s.reader.QueryContext(ctx,
-		`SELECT a.category, a.code, COALESCE(SUM(e.amount), 0) as balance
+		`SELECT a.category, a.code, e.currency, COALESCE(SUM(e.amount), 0) as balance
 		FROM accounts a
-		LEFT JOIN entries e ON e.account_id = a.id
-		LEFT JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
-		GROUP BY a.category, a.code
-		HAVING balance != 0`)
+		JOIN (`+sub+`) e ON e.account_id = a.id
+		GROUP BY a.category, a.code, e.currency
+		HAVING balance != 0`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("regulatory ratios query: %w", err)
 	}
 	defer rows.Close()
 
-	r := &ledger.RegulatoryRatios{}
+	r := &ledger.RegulatoryRatios{
+		AsOf:              optionalTime(asOf),
+		ReportingCurrency: s.reporting,
+		Rates:             rates,
+	}
 	for rows.Next() {
-		var category string
+		var category, currency string
 		var code int
 		var balance int64
-		if err := rows.Scan(&category, &code, &balance); err != nil {
+		if err := rows.Scan(&category, &code, &currency, &balance); err != nil {
 			return nil, fmt.Errorf("scan regulatory ratios: %w", err)
 		}
+		if balance, err = rates.Convert(balance, currency, s.reporting); err != nil {
+			return nil, fmt.Errorf("regulatory ratios: %w", err)
+		}
 
 		switch ledger.Category(category) {
 		case ledger.CategoryAssets:
@@ -156,45 +224,311 @@ func (s *Store) RegulatoryRatios(ctx context.Context) (*ledger.RegulatoryRatios,
 	return r, nil
 }
 
-func (s *Store) TrialBalance(ctx context.Context) (*ledger.TrialBalance, error) {
-	rows, err := s.reader.QueryContext(ctx,
+// TrialBalance lists every non-zero account balance, totalled in the
+// reporting currency at the rates effective at asOf. A zero asOf means the
+// current state.
+func (s *Store) TrialBalance(ctx context.Context, asOf time.Time) (*ledger.TrialBalance, error) {
+	rates, err := s.RatesAt(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := trialBalanceLines(ctx, s.reader, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	tb := &ledger.TrialBalance{
+		GeneratedAt:       time.Now().UTC(),
+		AsOf:              optionalTime(asOf),
+		ReportingCurrency: s.reporting,
+		Lines:             lines,
+		Rates:             rates,
+	}
+	for _, line := range lines {
+		total, amount := &tb.TotalCredit, line.Credit
+		if line.Debit > 0 {
+			total, amount = &tb.TotalDebit, line.Debit
+		}
+		amt, err := rates.Convert(amount, line.Currency, s.reporting)
+		if err != nil {
+			return nil, fmt.Errorf("trial balance: %s: %w", line.AccountID, err)
+		}
+		*total += amt
+	}
+
+	// Converted totals can differ by rounding, so balance is checked per currency.
+	tb.Balanced, err = s.isBalancedPerCurrency(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return tb, nil
+}
+
+// trialBalanceLines returns every account's non-zero posted balance as of
+// asOf, as a debit or a credit, in chart of accounts order.
+func trialBalanceLines(ctx context.Context, db querier, asOf time.Time) ([]ledger.TrialBalanceLine, error) {
+	sub, args := postedEntries(asOf)
+	rows, err := db.QueryContext(ctx,
 		`SELECT a.id, a.name, a.currency, COALESCE(SUM(e.amount), 0) as balance
 		FROM accounts a
-		LEFT JOIN entries e ON e.account_id = a.id
-		LEFT JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+		LEFT JOIN (`+sub+`) e ON e.account_id = a.id
 		GROUP BY a.id
 		HAVING balance != 0
-		ORDER BY a.code`)
+		ORDER BY a.code`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("trial balance query: %w", err)
 	}
 	defer rows.Close()
 
-	tb := &ledger.TrialBalance{
-		GeneratedAt: time.Now().UTC(),
-	}
-
+	var lines []ledger.TrialBalanceLine
 	for rows.Next() {
 		var line ledger.TrialBalanceLine
 		var balance int64
 		if err := rows.Scan(&line.AccountID, &line.AccountName, &line.Currency, &balance); err != nil {
 			return nil, fmt.Errorf("scan trial balance: %w", err)
 		}
-
 		if balance > 0 {
 			line.Debit = balance
-			tb.TotalDebit += balance
 		} else {
 			line.Credit = -balance
-			tb.TotalCredit += -balance
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// IncomeStatement reports revenue and expense activity posted between from
+// and to inclusive; a zero bound is open-ended. Year-end closing transactions
+// are excluded so a closed year still shows its result. Totals are in the
+// reporting currency at the rates effective at to.
+func (s *Store) IncomeStatement(ctx context.Context, from, to time.Time) (*ledger.IncomeStatement, error) {
+	rates, err := s.RatesAt(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT a.id, a.name, a.code, a.category, e.currency, SUM(e.amount) AS amount
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id
+		JOIN accounts a ON a.id = e.account_id
+		WHERE t.finalized = 1
+		  AND a.category IN (?, ?)
+		  AND t.id NOT IN (SELECT transaction_id FROM year_closings)`
+	args := []any{string(ledger.CategoryRevenue), string(ledger.CategoryExpenses)}
+	if !from.IsZero() {
+		query += ` AND julianday(t.posted_at) >= julianday(?)`
+		args = append(args, from.UTC().Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		query += ` AND julianday(t.posted_at) <= julianday(?)`
+		args = append(args, to.UTC().Format(time.RFC3339Nano))
+	}
+	query += ` GROUP BY a.id, e.currency
+		HAVING amount != 0
+		ORDER BY a.code, a.id, e.currency`
+
+	rows, err := s.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("income statement query: %w", err)
+	}
+	defer rows.Close()
+
+	is := &ledger.IncomeStatement{
+		From:              optionalTime(from),
+		To:                optionalTime(to),
+		GeneratedAt:       time.Now().UTC(),
+		ReportingCurrency: s.reporting,
+		Rates:             rates,
+	}
+
+	for rows.Next() {
+		var line ledger.IncomeStatementLine
+		var code int
+		var category string
+		if err := rows.Scan(&line.AccountID, &line.AccountName, &code, &category, &line.Currency, &line.Amount); err != nil {
+			return nil, fmt.Errorf("scan income statement: %w", err)
+		}
+
+		groups := &is.Expenses
+		if ledger.Category(category) == ledger.CategoryRevenue {
+			groups = &is.Revenue
+			line.Amount = -line.Amount // credit-normal → positive
 		}
 
-		tb.Lines = append(tb.Lines, line)
+		// Rows arrive ordered by code, so a new code always starts a new group.
+		if n := len(*groups); n == 0 || (*groups)[n-1].Code != code {
+			name := fmt.Sprintf("Code %d", code)
+			if ce := ledger.LookupChartEntry(code); ce != nil {
+				name = ce.Name
+			}
+			*groups = append(*groups, ledger.IncomeStatementGroup{
+				Code:      code,
+				Name:      name,
+				Subtotals: map[string]int64{},
+			})
+		}
+		g := &(*groups)[len(*groups)-1]
+		g.Lines = append(g.Lines, line)
+		g.Subtotals[line.Currency] += line.Amount
+		amt, err := rates.Convert(line.Amount, line.Currency, s.reporting)
+		if err != nil {
+			return nil, fmt.Errorf("income statement: %s: %w", line.AccountID, err)
+		}
+		g.Total += amt
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	tb.Balanced = tb.TotalDebit == tb.TotalCredit
-	return tb, nil
+	for _, g := range is.Revenue {
+		is.TotalRevenue += g.Total
+	}
+	for _, g := range is.Expenses {
+		is.TotalExpenses += g.Total
+	}
+	is.NetIncome = is.TotalRevenue - is.TotalExpenses
+	return is, nil
+}
+
+// CashFlowStatement reports cash moving through the 1010 nostro and 1060
+// reserve accounts between from and to inclusive; a zero bound is
+// open-ended. Each movement is classified by the CoA code of the
+// counter-entries in the same transaction and currency, so transfers between
+// cash accounts net to zero. Totals are in the reporting currency at the
+// rates effective at to.
+func (s *Store) CashFlowStatement(ctx context.Context, from, to time.Time) (*ledger.CashFlowStatement, error) {
+	rates, err := s.RatesAt(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cashCodes, cashArgs := cashCodeList()
+
+	window := ""
+	var windowArgs []any
+	if !from.IsZero() {
+		window += ` AND julianday(t.posted_at) >= julianday(?)`
+		windowArgs = append(windowArgs, from.UTC().Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		window += ` AND julianday(t.posted_at) <= julianday(?)`
+		windowArgs = append(windowArgs, to.UTC().Format(time.RFC3339Nano))
+	}
+
+	// Counter-entries of transactions that move cash in the same currency.
+	// Negating them gives the cash flow attributable to each code.
+	args := append([]any{}, cashArgs...)
+	args = append(args, cashArgs...)
+	args = append(args, windowArgs...)
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT a.code, e.currency, -SUM(e.amount) AS flow
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id
+		JOIN accounts a ON a.id = e.account_id
+		WHERE t.finalized = 1
+		  AND a.code NOT IN (`+cashCodes+`)
+		  AND EXISTS (
+			SELECT 1 FROM entries ce
+			JOIN accounts ca ON ca.id = ce.account_id
+			WHERE ce.transaction_id = e.transaction_id
+			  AND ce.currency = e.currency
+			  AND ca.code IN (`+cashCodes+`)
+		  )`+window+`
+		GROUP BY a.code, e.currency
+		HAVING flow != 0
+		ORDER BY a.code, e.currency`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cash flow query: %w", err)
+	}
+	defer rows.Close()
+
+	cf := &ledger.CashFlowStatement{
+		From:              optionalTime(from),
+		To:                optionalTime(to),
+		Operating:         ledger.CashFlowSection{Activity: ledger.ActivityOperating, Totals: map[string]int64{}},
+		Investing:         ledger.CashFlowSection{Activity: ledger.ActivityInvesting, Totals: map[string]int64{}},
+		Financing:         ledger.CashFlowSection{Activity: ledger.ActivityFinancing, Totals: map[string]int64{}},
+		NetChange:         map[string]int64{},
+		GeneratedAt:       time.Now().UTC(),
+		ReportingCurrency: s.reporting,
+		Rates:             rates,
+	}
+
+	for rows.Next() {
+		var line ledger.CashFlowLine
+		if err := rows.Scan(&line.Code, &line.Currency, &line.Amount); err != nil {
+			return nil, fmt.Errorf("scan cash flow: %w", err)
+		}
+		line.Name = fmt.Sprintf("Code %d", line.Code)
+		if ce := ledger.LookupChartEntry(line.Code); ce != nil {
+			line.Name = ce.Name
+		}
+
+		sec := cf.Section(ledger.ActivityForCode(line.Code))
+		sec.Lines = append(sec.Lines, line)
+		sec.Totals[line.Currency] += line.Amount
+		amt, err := rates.Convert(line.Amount, line.Currency, s.reporting)
+		if err != nil {
+			return nil, fmt.Errorf("cash flow: %w", err)
+		}
+		sec.Total += amt
+		cf.NetChange[line.Currency] += line.Amount
+		cf.NetChangeTotal += amt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Opening balance is everything strictly before from; closing is up to to.
+	if from.IsZero() {
+		cf.Opening = map[string]int64{}
+	} else {
+		cf.Opening, err = s.cashBalances(ctx, from.Add(-time.Millisecond))
+		if err != nil {
+			return nil, err
+		}
+	}
+	cf.Closing, err = s.cashBalances(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	return cf, nil
+}
+
+// cashBalances sums the cash accounts per currency as of asOf.
+func (s *Store) cashBalances(ctx context.Context, asOf time.Time) (map[string]int64, error) {
+	sub, args := postedEntries(asOf)
+	cashCodes, cashArgs := cashCodeList()
+	args = append(args, cashArgs...)
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT e.currency, SUM(e.amount)
+		FROM (`+sub+`) e
+		JOIN accounts a ON a.id = e.account_id
+		WHERE a.code IN (`+cashCodes+`)
+		GROUP BY e.currency`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cash balances: %w", err)
+	}
+	defer rows.Close()
+
+	balances := map[string]int64{}
+	for rows.Next() {
+		var ccy string
+		var bal int64
+		if err := rows.Scan(&ccy, &bal); err != nil {
+			return nil, fmt.Errorf("scan cash balance: %w", err)
+		}
+		balances[ccy] = bal
+	}
+	return balances, rows.Err()
+}
+
+// cashCodeList returns "?, ?" placeholders and args for ledger.CashAccountCodes.
+func cashCodeList() (string, []any) {
+	args := make([]any, len(ledger.CashAccountCodes))
+	for i, c := range ledger.CashAccountCodes {
+		args[i] = c
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", "), args
 }
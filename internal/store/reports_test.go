@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestReportsRefuseUnratedCurrencies(t *testing.T) {
+	ctx := context.Background()
+	st := openTestStore(t)
+	mustCreate(t, st, testTransaction("settle", 10000))
+	exec(t, st, `DELETE FROM fx_rates WHERE currency = 'USD'`)
+
+	reports := []struct {
+		name string
+		run  func() error
+	}{
+		{"balance sheet", func() error { _, err := st.BalanceSheet(ctx, time.Time{}); return err }},
+		{"trial balance", func() error { _, err := st.TrialBalance(ctx, time.Time{}); return err }},
+		{"regulatory ratios", func() error { _, err := st.RegulatoryRatios(ctx, time.Time{}); return err }},
+	}
+	for _, r := range reports {
+		t.Run(r.name, func(t *testing.T) {
+			err := r.run()
+			if !errors.Is(err, ledger.ErrRateNotFound) || !strings.Contains(err.Error(), "USD") {
+				t.Errorf("error = %v, want ErrRateNotFound naming USD", err)
+			}
+		})
+	}
+}
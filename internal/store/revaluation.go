@@ -0,0 +1,279 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// RevalueFX revalues every foreign-currency monetary balance at the rate
+// effective at asOf. Each balance is carried in the reporting currency at
+// the rates effective when its entries were posted, plus the adjustments of
+// earlier runs; the difference to the revalued amount is posted in one
+// reporting-currency transaction dated asOf, against ~fxreval with the net
+// result split between ~fxgain and ~fxloss.
+// Runs must be in date order. Every run is recorded with the rates it used.
+func (s *Store) RevalueFX(ctx context.Context, asOf time.Time) (*ledger.FXRevaluation, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	asOf = asOf.UTC()
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var latest sql.NullString
+	if err := tx.QueryRowContext(ctx,
+		`SELECT as_of FROM fx_revaluations ORDER BY julianday(as_of) DESC LIMIT 1`,
+	).Scan(&latest); err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("check fx revaluations: %w", err)
+	}
+	if latest.Valid {
+		last, _ := time.Parse(time.RFC3339Nano, latest.String)
+		if !asOf.After(last) {
+			return nil, fmt.Errorf("%w: last revaluation was as of %s",
+				ledger.ErrRevaluationOutOfOrder, last.Format(time.RFC3339))
+		}
+	}
+
+	lines, err := s.revaluationLines(ctx, tx, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, ledger.ErrNothingToRevalue
+	}
+
+	rev := &ledger.FXRevaluation{
+		ID:                uuid.Must(uuid.NewV7()).String(),
+		AsOf:              asOf,
+		ReportingCurrency: s.reporting,
+		Lines:             lines,
+		CreatedAt:         time.Now().UTC(),
+	}
+	for _, l := range lines {
+		if l.Adjustment > 0 {
+			rev.Gain += l.Adjustment
+		} else {
+			rev.Loss -= l.Adjustment
+		}
+	}
+
+	if rev.Gain != 0 || rev.Loss != 0 {
+		txn := revaluationTransaction(rev)
+		if err := prepareTransaction(txn); err != nil {
+			return nil, err
+		}
+		if err := insertTransaction(ctx, tx, txn, newRuleCache()); err != nil {
+			return nil, err
+		}
+		rev.TransactionID = txn.ID
+	}
+
+	var txnID sql.NullString
+	if rev.TransactionID != "" {
+		txnID = sql.NullString{String: rev.TransactionID, Valid: true}
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO fx_revaluations (id, as_of, transaction_id, gain, loss, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		rev.ID, rev.AsOf.Format(time.RFC3339Nano), txnID, rev.Gain, rev.Loss, rev.CreatedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("record fx revaluation: %w", err)
+	}
+	for _, l := range lines {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO fx_revaluation_lines
+			(revaluation_id, account_id, currency, balance, rate, rate_effective_at, carrying_amount, revalued_amount, adjustment)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			rev.ID, l.AccountID, l.Currency, l.Balance, l.Rate, l.RateEffectiveAt.Format(time.RFC3339Nano),
+			l.CarryingAmount, l.RevaluedAmount, l.Adjustment)
+		if err != nil {
+			return nil, fmt.Errorf("record fx revaluation line: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return rev, nil
+}
+
+// revaluationLines computes the carrying and revalued reporting-currency
+// amount of every foreign-currency monetary balance at asOf.
+func (s *Store) revaluationLines(ctx context.Context, tx *sql.Tx, asOf time.Time) ([]ledger.FXRevaluationLine, error) {
+	// Each entry is converted at the rate effective when it was posted.
+	rows, err := tx.QueryContext(ctx,
+		`WITH rated AS (
+			SELECT e.account_id, e.currency, e.amount, a.code, a.category,
+				(SELECT r.rate FROM fx_rates r
+				 WHERE r.currency = e.currency AND julianday(r.effective_at) <= julianday(t.posted_at)
+				 ORDER BY julianday(r.effective_at) DESC LIMIT 1) AS rate
+			FROM entries e
+			JOIN transactions t ON t.id = e.transaction_id AND t.finalized = 1
+			JOIN accounts a ON a.id = e.account_id
+			WHERE e.currency != ?
+			  AND a.category IN (?, ?)
+			  AND julianday(t.posted_at) <= julianday(?)
+		)
+		SELECT account_id, currency, code, category, SUM(amount), SUM(amount * rate), COUNT(*) - COUNT(rate)
+		FROM rated
+		GROUP BY account_id, currency
+		ORDER BY code, account_id, currency`,
+		s.reporting, string(ledger.CategoryAssets), string(ledger.CategoryLiabilities),
+		asOf.Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("revaluation balances: %w", err)
+	}
+
+	var lines []ledger.FXRevaluationLine
+	for rows.Next() {
+		var l ledger.FXRevaluationLine
+		var code, unrated int
+		var category string
+		var historical sql.NullFloat64
+		if err := rows.Scan(&l.AccountID, &l.Currency, &code, &category, &l.Balance, &historical, &unrated); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan revaluation balance: %w", err)
+		}
+		if !ledger.IsMonetary(code, ledger.Category(category)) {
+			continue
+		}
+		if unrated > 0 {
+			rows.Close()
+			return nil, fmt.Errorf("%w: %s for %d entries on %s", ledger.ErrRateNotFound, l.Currency, unrated, l.AccountID)
+		}
+		l.CarryingAmount = int64(math.Round(historical.Float64 * ledger.MinorUnitScale(l.Currency, s.reporting)))
+		lines = append(lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	rates := map[string]*ledger.FXRate{}
+	kept := lines[:0]
+	for _, l := range lines {
+		var prior int64
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COALESCE(SUM(adjustment), 0) FROM fx_revaluation_lines WHERE account_id = ? AND currency = ?`,
+			l.AccountID, l.Currency,
+		).Scan(&prior); err != nil {
+			return nil, fmt.Errorf("prior revaluations: %w", err)
+		}
+		l.CarryingAmount += prior
+
+		rate, ok := rates[l.Currency]
+		if !ok {
+			if rate, err = rateAt(ctx, tx, l.Currency, asOf); err != nil {
+				return nil, err
+			}
+			rates[l.Currency] = rate
+		}
+		l.Rate = rate.Rate
+		l.RateEffectiveAt = rate.EffectiveAt
+		l.RevaluedAmount = int64(math.Round(float64(l.Balance) * rate.Rate * ledger.MinorUnitScale(l.Currency, s.reporting)))
+		l.Adjustment = l.RevaluedAmount - l.CarryingAmount
+
+		if l.Balance == 0 && l.CarryingAmount == 0 {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept, nil
+}
+
+// revaluationTransaction books the run's gain and loss against the
+// ~fxreval carrying adjustment.
+func revaluationTransaction(rev *ledger.FXRevaluation) *ledger.Transaction {
+	txn := &ledger.Transaction{
+		Description: "FX revaluation as of " + rev.AsOf.Format("2006-01-02"),
+		PostedAt:    rev.AsOf,
+	}
+	ccy := rev.ReportingCurrency
+	if net := rev.Net(); net != 0 {
+		txn.Entries = append(txn.Entries, ledger.Entry{AccountID: ledger.FXRevaluationAccount, Amount: net, Currency: ccy})
+	}
+	if rev.Gain != 0 {
+		txn.Entries = append(txn.Entries, ledger.Entry{AccountID: ledger.FXGainAccount, Amount: -rev.Gain, Currency: ccy})
+	}
+	if rev.Loss != 0 {
+		txn.Entries = append(txn.Entries, ledger.Entry{AccountID: ledger.FXLossAccount, Amount: rev.Loss, Currency: ccy})
+	}
+	return txn
+}
+
+// ListFXRevaluations returns every revaluation run, oldest first, without
+// its lines.
+func (s *Store) ListFXRevaluations(ctx context.Context) ([]ledger.FXRevaluation, error) {
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT id, as_of, COALESCE(transaction_id, ''), gain, loss, created_at
+		FROM fx_revaluations ORDER BY julianday(as_of)`)
+	if err != nil {
+		return nil, fmt.Errorf("list fx revaluations: %w", err)
+	}
+	defer rows.Close()
+
+	var revs []ledger.FXRevaluation
+	for rows.Next() {
+		r := ledger.FXRevaluation{ReportingCurrency: s.reporting}
+		var asOf, createdAt string
+		if err := rows.Scan(&r.ID, &asOf, &r.TransactionID, &r.Gain, &r.Loss, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan fx revaluation: %w", err)
+		}
+		r.AsOf, _ = time.Parse(time.RFC3339Nano, asOf)
+		r.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		revs = append(revs, r)
+	}
+	return revs, rows.Err()
+}
+
+// GetFXRevaluation returns a revaluation run with the balances and rates
+// it used.
+func (s *Store) GetFXRevaluation(ctx context.Context, id string) (*ledger.FXRevaluation, error) {
+	r := ledger.FXRevaluation{ReportingCurrency: s.reporting}
+	var asOf, createdAt string
+	err := s.reader.QueryRowContext(ctx,
+		`SELECT id, as_of, COALESCE(transaction_id, ''), gain, loss, created_at
+		FROM fx_revaluations WHERE id = ?`, id,
+	).Scan(&r.ID, &asOf, &r.TransactionID, &r.Gain, &r.Loss, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ledger.ErrRevaluationNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get fx revaluation: %w", err)
+	}
+	r.AsOf, _ = time.Parse(time.RFC3339Nano, asOf)
+	r.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT l.account_id, l.currency, l.balance, l.rate, l.rate_effective_at, l.carrying_amount, l.revalued_amount, l.adjustment
+		FROM fx_revaluation_lines l
+		JOIN accounts a ON a.id = l.account_id
+		WHERE l.revaluation_id = ?
+		ORDER BY a.code, l.account_id, l.currency`, id)
+	if err != nil {
+		return nil, fmt.Errorf("fx revaluation lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l ledger.FXRevaluationLine
+		var effectiveAt string
+		if err := rows.Scan(&l.AccountID, &l.Currency, &l.Balance, &l.Rate, &effectiveAt,
+			&l.CarryingAmount, &l.RevaluedAmount, &l.Adjustment); err != nil {
+			return nil, fmt.Errorf("scan fx revaluation line: %w", err)
+		}
+		l.RateEffectiveAt, _ = time.Parse(time.RFC3339Nano, effectiveAt)
+		r.Lines = append(r.Lines, l)
+	}
+	return &r, rows.Err()
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// ReverseTransaction posts a new transaction that mirrors id with every entry
+// amount negated, and links the two. A transaction can only be reversed once.
+// Like any other posting, a reversal with an entry above its code's approval
+// threshold is parked for a second user to approve instead of posted.
+func (s *Store) ReverseTransaction(ctx context.Context, id, reason string) (*ledger.Transaction, error) {
+	orig, err := s.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !orig.Finalized {
+		return nil, fmt.Errorf("%w: void %s instead of reversing it", ledger.ErrTransactionPending, id)
+	}
+	if orig.ReversedBy != "" {
+		return nil, fmt.Errorf("%w: %s was reversed by %s", ledger.ErrAlreadyReversed, id, orig.ReversedBy)
+	}
+
+	rev := orig.Reversal(reason)
+	if err := prepareTransaction(rev); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Re-check under the writer lock in case a concurrent reversal won the race.
+	var existing string
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(reversal_id), '') FROM transaction_reversals WHERE original_id = ?`, id,
+	).Scan(&existing)
+	if err != nil {
+		return nil, fmt.Errorf("check reversal: %w", err)
+	}
+	if existing != "" {
+		return nil, fmt.Errorf("%w: %s was reversed by %s", ledger.ErrAlreadyReversed, id, existing)
+	}
+
+	rc := newRuleCache()
+	approval, err := approvalReason(ctx, tx, rev, rc)
+	if err != nil {
+		return nil, err
+	}
+	if approval != "" {
+		rev.ApprovalStatus = ledger.ApprovalPending
+	}
+	if err := insertTransaction(ctx, tx, rev, rc); err != nil {
+		return nil, err
+	}
+	if approval != "" {
+		if err := insertApproval(ctx, tx, rev, approval); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO transaction_reversals (original_id, reversal_id, reason) VALUES (?, ?, ?)`,
+		id, rev.ID, reason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("link reversal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	rev.Finalized = rev.ApprovalStatus != ledger.ApprovalPending
+	return rev, nil
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func TestReverseTransaction(t *testing.T) {
+	ctx := context.Background()
+	reverse := func(t *testing.T, st *Store, id string) *ledger.Transaction {
+		t.Helper()
+		rev, err := st.ReverseTransaction(ctx, id, "test")
+		if err != nil {
+			t.Fatalf("ReverseTransaction(%s): %v", id, err)
+		}
+		return rev
+	}
+
+	tests := []struct {
+		name string
+		// setup returns the transaction to reverse.
+		setup func(t *testing.T, st *Store) string
+		// want is the error, or nil if ~settlement should then hold wantBalance.
+		want        error
+		wantBalance int64
+	}{
+		{
+			name: "posted",
+			setup: func(t *testing.T, st *Store) string {
+				return mustCreate(t, st, testTransaction("sale", 1000)).ID
+			},
+			wantBalance: 0,
+		},
+		{
+			name: "already reversed",
+			setup: func(t *testing.T, st *Store) string {
+				id := mustCreate(t, st, testTransaction("sale", 1000)).ID
+				reverse(t, st, id)
+				return id
+			},
+			want: ledger.ErrAlreadyReversed,
+		},
+		{
+			// Reversing a reversal reinstates the original.
+			name: "reversal",
+			setup: func(t *testing.T, st *Store) string {
+				id := mustCreate(t, st, testTransaction("sale", 1000)).ID
+				return reverse(t, st, id).ID
+			},
+			wantBalance: 1000,
+		},
+		{
+			name: "reversed reversal",
+			setup: func(t *testing.T, st *Store) string {
+				id := mustCreate(t, st, testTransaction("sale", 1000)).ID
+				rev := reverse(t, st, id)
+				reverse(t, st, rev.ID)
+				return rev.ID
+			},
+			want: ledger.ErrAlreadyReversed,
+		},
+		{
+			name: "pending hold",
+			setup: func(t *testing.T, st *Store) string {
+				return mustCreate(t, st, testHold("auth", 1000)).ID
+			},
+			want: ledger.ErrTransactionPending,
+		},
+		{
+			name: "voided hold",
+			setup: func(t *testing.T, st *Store) string {
+				id := mustCreate(t, st, testHold("auth", 1000)).ID
+				if _, err := st.VoidTransaction(ctx, id); err != nil {
+					t.Fatalf("VoidTransaction: %v", err)
+				}
+				return id
+			},
+			want: ledger.ErrTransactionPending,
+		},
+		{
+			name: "awaiting approval",
+			setup: func(t *testing.T, st *Store) string {
+				requireApproval(t, st, 500)
+				return mustCreate(t, st, testTransaction("large", 1000)).ID
+			},
+			want: ledger.ErrTransactionPending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := openTestStore(t)
+			id := tt.setup(t, st)
+			before := settled(t, st)
+
+			rev, err := st.ReverseTransaction(ctx, id, "test")
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("error = %v, want %v", err, tt.want)
+				}
+				if after := settled(t, st); after != before {
+					t.Errorf("refused reversal moved ~settlement from %d to %d", before, after)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReverseTransaction: %v", err)
+			}
+			if rev.ReversalOf != id || !rev.Finalized {
+				t.Errorf("reversal = %+v, want a finalized reversal of %s", rev, id)
+			}
+			if got := settled(t, st); got != tt.wantBalance {
+				t.Errorf("~settlement = %d, want %d", got, tt.wantBalance)
+			}
+			orig, err := st.GetTransaction(ctx, id)
+			if err != nil {
+				t.Fatalf("GetTransaction: %v", err)
+			}
+			if orig.ReversedBy != rev.ID {
+				t.Errorf("original reversed by %q, want %s", orig.ReversedBy, rev.ID)
+			}
+		})
+	}
+}
+
+// Reversing a transaction above the approval threshold needs a second user,
+// like posting it did.
+func TestReverseAboveThreshold(t *testing.T) {
+	alice := WithActor(context.Background(), "alice")
+	for _, approve := range []bool{true, false} {
+		st := openTestStore(t)
+		id := parkTransaction(t, st, false)
+		if err := (decision{"bob", true}).apply(st, id); err != nil {
+			t.Fatalf("approving the original: %v", err)
+		}
+
+		rev, err := st.ReverseTransaction(alice, id, "mistake")
+		if err != nil {
+			t.Fatalf("ReverseTransaction: %v", err)
+		}
+		if rev.ApprovalStatus != ledger.ApprovalPending || rev.Finalized {
+			t.Fatalf("reversal = %+v, want it parked for approval", rev)
+		}
+		if got := settled(t, st); got != 1000 {
+			t.Errorf("~settlement = %d while the reversal awaits approval, want 1000", got)
+		}
+		if _, err := st.ReverseTransaction(alice, id, "again"); !errors.Is(err, ledger.ErrAlreadyReversed) {
+			t.Errorf("reversing again while awaiting approval: error = %v, want ErrAlreadyReversed", err)
+		}
+		if err := (decision{"alice", true}).apply(st, rev.ID); !errors.Is(err, ledger.ErrSelfApproval) {
+			t.Errorf("approving own reversal: error = %v, want ErrSelfApproval", err)
+		}
+
+		if err := (decision{"bob", approve}).apply(st, rev.ID); err != nil {
+			t.Fatalf("deciding the reversal: %v", err)
+		}
+		orig, err := st.GetTransaction(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetTransaction: %v", err)
+		}
+		if approve {
+			if got := settled(t, st); got != 0 {
+				t.Errorf("~settlement = %d after approving the reversal, want 0", got)
+			}
+			if orig.ReversedBy != rev.ID {
+				t.Errorf("original reversed by %q, want %s", orig.ReversedBy, rev.ID)
+			}
+			continue
+		}
+		// A rejected reversal leaves the original to be reversed again.
+		if got := settled(t, st); got != 1000 {
+			t.Errorf("~settlement = %d after rejecting the reversal, want 1000", got)
+		}
+		if orig.ReversedBy != "" {
+			t.Errorf("original reversed by %q after rejecting the reversal", orig.ReversedBy)
+		}
+		if _, err := st.ReverseTransaction(alice, id, "again"); err != nil {
+			t.Errorf("reversing after a rejected reversal: %v", err)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strconv"
 
 	"github.com/simonvc/miniledger/internal/ledger"
 )
@@ -46,13 +48,25 @@ func (s *Store) GetCodeSettings(ctx context.Context, code int) (ledger.CodeSetti
 			cs.BlockInverted = value == "1"
 		case ledger.SettingEntryDirection:
 			cs.EntryDirection = ledger.EntryDirection(value)
+		case ledger.SettingApprovalThreshold:
+			cs.ApprovalThreshold, _ = strconv.ParseInt(value, 10, 64)
 		}
 	}
 	return cs, rows.Err()
 }
 
 func (s *Store) UpsertSetting(ctx context.Context, setting ledger.CoASetting) error {
-	_, err := s.writer.ExecContext(ctx,
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getSetting(ctx, tx, setting.Code, setting.Setting)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
 		`INSERT INTO coa_settings (code, setting, value) VALUES (?, ?, ?)
 		 ON CONFLICT(code, setting) DO UPDATE SET value = excluded.value`,
 		setting.Code, setting.Setting, setting.Value,
@@ -60,14 +74,48 @@ func (s *Store) UpsertSetting(ctx context.Context, setting ledger.CoASetting) er
 	if err != nil {
 		return fmt.Errorf("upsert setting: %w", err)
 	}
-	return nil
+	target := ledger.SettingTarget(setting.Code, setting.Setting)
+	if err := logChange(ctx, tx, ledger.OpSettingUpsert, target, before, setting); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (s *Store) DeleteSetting(ctx context.Context, code int, name ledger.SettingName) error {
-	_, err := s.writer.ExecContext(ctx,
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := getSetting(ctx, tx, code, name)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
 		`DELETE FROM coa_settings WHERE code = ? AND setting = ?`, code, name)
 	if err != nil {
 		return fmt.Errorf("delete setting: %w", err)
 	}
-	return nil
+	if before != nil {
+		if err := logChange(ctx, tx, ledger.OpSettingDelete, ledger.SettingTarget(code, name), before, nil); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// getSetting returns the stored value of one setting, or nil if unset.
+func getSetting(ctx context.Context, tx *sql.Tx, code int, name ledger.SettingName) (*ledger.CoASetting, error) {
+	cs := ledger.CoASetting{Code: code, Setting: name}
+	err := tx.QueryRowContext(ctx,
+		`SELECT value FROM coa_settings WHERE code = ? AND setting = ?`, code, name,
+	).Scan(&cs.Value)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get setting: %w", err)
+	}
+	return &cs, nil
 }
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// counterpartySep separates account IDs in the group_concat below; account
+// IDs are free text, so a control character is the only safe delimiter.
+const counterpartySep = "\x1f"
+
+// AccountStatement builds the statement for accountID over from..to
+// inclusive; zero bounds are open-ended. Entries are ordered by posting time.
+func (s *Store) AccountStatement(ctx context.Context, accountID string, from, to time.Time) (*ledger.AccountStatement, error) {
+	acct, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &ledger.AccountStatement{
+		AccountID:   acct.ID,
+		AccountName: acct.Name,
+		Currency:    acct.Currency,
+		From:        optionalTime(from),
+		To:          optionalTime(to),
+		Opening:     map[string]int64{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	if !from.IsZero() {
+		sub, args := postedEntries(from.Add(-time.Millisecond))
+		rows, err := s.reader.QueryContext(ctx,
+			`SELECT e.currency, SUM(e.amount) FROM (`+sub+`) e
+			WHERE e.account_id = ?
+			GROUP BY e.currency`, append(args, accountID)...)
+		if err != nil {
+			return nil, fmt.Errorf("opening balance: %w", err)
+		}
+		for rows.Next() {
+			var ccy string
+			var bal int64
+			if err := rows.Scan(&ccy, &bal); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan opening balance: %w", err)
+			}
+			st.Opening[ccy] = bal
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+
+	query := `SELECT e.id, e.transaction_id, t.posted_at, t.description, e.amount, e.currency,
+			COALESCE((SELECT group_concat(account_id, ?) FROM (
+				SELECT DISTINCT ce.account_id FROM entries ce
+				WHERE ce.transaction_id = e.transaction_id AND ce.account_id != e.account_id
+				ORDER BY ce.account_id
+			)), '')
+		FROM entries e
+		JOIN transactions t ON t.id = e.transaction_id
+		WHERE e.account_id = ? AND t.finalized = 1`
+	args := []any{counterpartySep, accountID}
+	if !from.IsZero() {
+		query += ` AND julianday(t.posted_at) >= julianday(?)`
+		args = append(args, from.UTC().Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		query += ` AND julianday(t.posted_at) <= julianday(?)`
+		args = append(args, to.UTC().Format(time.RFC3339Nano))
+	}
+	query += ` ORDER BY julianday(t.posted_at), e.id`
+
+	rows, err := s.reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("account statement: %w", err)
+	}
+	defer rows.Close()
+
+	st.Closing = make(map[string]int64, len(st.Opening))
+	for ccy, bal := range st.Opening {
+		st.Closing[ccy] = bal
+	}
+	for rows.Next() {
+		var l ledger.StatementLine
+		var postedAt, counterparties string
+		if err := rows.Scan(&l.EntryID, &l.TransactionID, &postedAt, &l.Description, &l.Amount, &l.Currency, &counterparties); err != nil {
+			return nil, fmt.Errorf("scan statement line: %w", err)
+		}
+		l.PostedAt, _ = time.Parse(time.RFC3339Nano, postedAt)
+		l.Counterparties = []string{}
+		if counterparties != "" {
+			l.Counterparties = strings.Split(counterparties, counterpartySep)
+		}
+		st.Closing[l.Currency] += l.Amount
+		l.Balance = st.Closing[l.Currency]
+		st.Lines = append(st.Lines, l)
+	}
+	return st, rows.Err()
+}
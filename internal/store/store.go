@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
 	"runtime"
+	"time"
 
 	"github.com/simonvc/miniledger/internal/ledger"
 	_ "modernc.org/sqlite"
@@ -17,24 +19,52 @@ type AccountFilter struct {
 	Offset   int
 }
 
+// TxnFilter narrows a transaction listing. Zero values are ignored. The
+// entry-level fields (AccountID, Currency, Code, MinAmount, MaxAmount) must
+// all match the same entry; amounts compare against the entry's absolute
+// value in minor units.
 type TxnFilter struct {
-	AccountID string
-	Limit     int
-	Offset    int
+	AccountID   string
+	From        time.Time
+	To          time.Time
+	Description string // case-insensitive substring
+	Currency    string
+	Code        int
+	MinAmount   int64
+	MaxAmount   int64
+	Pending     bool // list open holds instead of finalized transactions
+
+	Limit  int
+	Cursor string // opaque, from a previous page's NextCursor
 }
 
 type EntryFilter struct {
 	Limit  int
-	Offset int
+	Cursor string
 }
 
 type Store struct {
-	writer *sql.DB
-	reader *sql.DB
+	writer    *sql.DB
+	reader    *sql.DB
+	reporting string // reporting currency, fixed when the ledger is created
 }
 
-func Open(dbPath string) (*Store, error) {
-	dsn := fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=foreign_keys(ON)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)", dbPath)
+// Options configure a ledger created by Open. An existing ledger keeps the
+// settings it was created with.
+type Options struct {
+	// ReportingCurrency is the currency reports are converted to. Empty
+	// means ledger.DefaultReportingCurrency.
+	ReportingCurrency string
+}
+
+// fileDSN returns the SQLite URI of the database at path with the given
+// query. The path is escaped, so ?, # and % in it are taken literally.
+func fileDSN(path, query string) string {
+	return (&url.URL{Scheme: "file", Path: path, RawQuery: query}).String()
+}
+
+func Open(dbPath string, opts Options) (*Store, error) {
+	dsn := fileDSN(dbPath, "_pragma=journal_mode(WAL)&_pragma=foreign_keys(ON)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)")
 
 	writer, err := sql.Open("sqlite", dsn)
 	if err != nil {
@@ -51,11 +81,10 @@ func Open(dbPath string) (*Store, error) {
 
 	s := &Store{writer: writer, reader: reader}
 
-	if err := s.migrate(context.Background()); err != nil {
+	if err := s.migrate(context.Background(), opts); err != nil {
 		s.Close()
 		return nil, fmt.Errorf("migrate: %w", err)
 	}
-
 	return s, nil
 }
 
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// openTestStore opens a new ledger in a temporary directory.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "ledger.db"), Options{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+// testTransaction moves amount USD cents from ~tax to ~settlement.
+func testTransaction(description string, amount int64) *ledger.Transaction {
+	return &ledger.Transaction{
+		Description: description,
+		Entries: []ledger.Entry{
+			{AccountID: "~settlement", Amount: amount, Currency: "USD"},
+			{AccountID: "~tax", Amount: -amount, Currency: "USD"},
+		},
+	}
+}
+
+// testHold is testTransaction as a hold.
+func testHold(description string, amount int64) *ledger.Transaction {
+	txn := testTransaction(description, amount)
+	txn.Status = ledger.StatusPending
+	return txn
+}
+
+// requireApproval parks transactions moving more than threshold USD cents
+// through ~settlement (code 1098) for approval.
+func requireApproval(t *testing.T, st *Store, threshold int64) {
+	t.Helper()
+	setting := ledger.CoASetting{Code: 1098, Setting: ledger.SettingApprovalThreshold, Value: strconv.FormatInt(threshold, 10)}
+	if err := st.UpsertSetting(context.Background(), setting); err != nil {
+		t.Fatalf("UpsertSetting: %v", err)
+	}
+}
+
+// settled returns the posted balance of ~settlement.
+func settled(t *testing.T, st *Store) int64 {
+	t.Helper()
+	balance, _, err := st.AccountBalance(context.Background(), "~settlement", time.Time{})
+	if err != nil {
+		t.Fatalf("AccountBalance: %v", err)
+	}
+	return balance
+}
+
+// mustCreate posts txn, failing the test if it is refused.
+func mustCreate(t *testing.T, st *Store, txn *ledger.Transaction) *ledger.Transaction {
+	t.Helper()
+	if err := st.CreateTransaction(context.Background(), txn); err != nil {
+		t.Fatalf("CreateTransaction %q: %v", txn.Description, err)
+	}
+	return txn
+}
+
+// exec runs raw SQL on the writer, bypassing the store's checks.
+func exec(t *testing.T, st *Store, query string, args ...any) {
+	t.Helper()
+	if _, err := st.writer.Exec(query, args...); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+}
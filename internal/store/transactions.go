@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,25 +13,116 @@ import (
 )
 
 func (s *Store) CreateTransaction(ctx context.Context, txn *ledger.Transaction) error {
+	hash := requestHash(txn)
+	if err := prepareTransaction(txn); err != nil {
+		return err
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	origID, err := bookTransaction(ctx, tx, txn, hash, newRuleCache())
+	if err != nil {
+		return err
+	}
+	if origID != "" {
+		// Replay: hand back the original transaction instead of booking again.
+		tx.Rollback()
+		return s.replayTransaction(ctx, txn, origID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// bookTransaction writes the prepared txn inside tx the way
+// CreateTransaction does: it parks it for approval when an entry exceeds its
+// code's threshold and records its idempotency key. If the key was already
+// used for the same request, going by hash, nothing is written and the
+// original transaction's ID is returned instead. The caller owns
+// commit/rollback.
+func bookTransaction(ctx context.Context, tx *sql.Tx, txn *ledger.Transaction, hash string, rc *ruleCache) (string, error) {
+	if txn.IdempotencyKey != "" {
+		origID, err := lookupIdempotencyKey(ctx, tx, txn.IdempotencyKey, hash)
+		if err != nil {
+			return "", err
+		}
+		if origID != "" {
+			return origID, nil
+		}
+	}
+
+	// Entries above their code's approval threshold park the transaction
+	// for a second user to approve instead of posting it.
+	reason, err := approvalReason(ctx, tx, txn, rc)
+	if err != nil {
+		return "", err
+	}
+	if reason != "" {
+		txn.ApprovalStatus = ledger.ApprovalPending
+	}
+
+	if err := insertTransaction(ctx, tx, txn, rc); err != nil {
+		return "", err
+	}
+	if reason != "" {
+		if err := insertApproval(ctx, tx, txn, reason); err != nil {
+			return "", err
+		}
+	}
+
+	if txn.IdempotencyKey != "" {
+		if err := insertIdempotencyKey(ctx, tx, txn.IdempotencyKey, txn.ID, hash); err != nil {
+			return "", err
+		}
+	}
+
+	txn.Finalized = txn.Status != ledger.StatusPending && txn.ApprovalStatus != ledger.ApprovalPending
+	return "", nil
+}
+
+// replayTransaction replaces txn with the committed transaction origID.
+func (s *Store) replayTransaction(ctx context.Context, txn *ledger.Transaction, origID string) error {
+	orig, err := s.GetTransaction(ctx, origID)
+	if err != nil {
+		return err
+	}
+	*txn = *orig
+	return nil
+}
+
+// prepareTransaction assigns a UUID v7 and posting time when missing and
+// validates the transaction invariants.
+func prepareTransaction(txn *ledger.Transaction) error {
 	if txn.ID == "" {
 		txn.ID = uuid.Must(uuid.NewV7()).String()
 	}
 	if txn.PostedAt.IsZero() {
 		txn.PostedAt = time.Now().UTC()
 	}
+	if txn.Status != "" && txn.Status != ledger.StatusPending {
+		return fmt.Errorf("%w: cannot create a %s transaction", ledger.ErrNotPending, txn.Status)
+	}
+	return txn.Validate()
+}
 
-	if err := txn.Validate(); err != nil {
+// insertTransaction writes txn and its entries inside tx, enforces the
+// per-code settings and finalizes it. A txn with Status pending is left
+// unfinalized as a hold instead, and one with ApprovalStatus pending is left
+// unfinalized for the approval queue. Account rules come from rc. The
+// caller owns commit/rollback.
+func insertTransaction(ctx context.Context, tx *sql.Tx, txn *ledger.Transaction, rc *ruleCache) error {
+	if err := checkPeriodOpen(ctx, tx, txn.PostedAt); err != nil {
 		return err
 	}
 
-	tx, err := s.writer.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
-	}
-	defer tx.Rollback()
-
 	// Insert transaction (finalized=0)
-	_, err = tx.ExecContext(ctx,
+	_, err := tx.ExecContext(ctx,
 		`INSERT INTO transactions (id, description, posted_at) VALUES (?, ?, ?)`,
 		txn.ID, txn.Description, txn.PostedAt.Format(time.RFC3339Nano),
 	)
@@ -37,82 +130,145 @@ func (s *Store) CreateTransaction(ctx context.Context, txn *ledger.Transaction)
 		return fmt.Errorf("insert transaction: %w", err)
 	}
 
-	// Collect account codes for settings enforcement
-	accountCodes := map[string]int{}    // account_id -> code
-	accountCats := map[string]string{}  // account_id -> category
-	for _, e := range txn.Entries {
-		if _, ok := accountCodes[e.AccountID]; !ok {
-			var code int
-			var cat string
-			err := tx.QueryRowContext(ctx,
-				`SELECT code, category FROM accounts WHERE id = ?`, e.AccountID).Scan(&code, &cat)
-			if err != nil {
-				return fmt.Errorf("lookup account %s: %w", e.AccountID, err)
-			}
-			accountCodes[e.AccountID] = code
-			accountCats[e.AccountID] = cat
+	rules, err := rc.rules(ctx, tx, txn.Entries)
+	if err != nil {
+		return err
+	}
+
+	// Insert all entries (with entry direction check)
+	for i := range txn.Entries {
+		txn.Entries[i].TransactionID = txn.ID
+		rule := rules[txn.Entries[i].AccountID]
+
+		// Entry direction enforcement
+		if rule.settings.EntryDirection == ledger.DirectionDebitOnly && txn.Entries[i].Amount < 0 {
+			return fmt.Errorf("%w: account %s (code %d) only allows debit entries",
+				ledger.ErrEntryDirectionViolation, txn.Entries[i].AccountID, rule.code)
 		}
+		if rule.settings.EntryDirection == ledger.DirectionCreditOnly && txn.Entries[i].Amount > 0 {
+			return fmt.Errorf("%w: account %s (code %d) only allows credit entries",
+				ledger.ErrEntryDirectionViolation, txn.Entries[i].AccountID, rule.code)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO entries (transaction_id, account_id, amount, currency) VALUES (?, ?, ?, ?)`,
+			txn.ID, txn.Entries[i].AccountID, txn.Entries[i].Amount, txn.Entries[i].Currency,
+		)
+		if err != nil {
+			return fmt.Errorf("insert entry %d: %w", i, err)
+		}
+	}
+
+	// Block inverted balance check (before finalization)
+	if err := checkInvertedBalances(ctx, tx, txn, rules); err != nil {
+		return err
+	}
+
+	if txn.ApprovalStatus == ledger.ApprovalPending {
+		return nil
 	}
+	if txn.Status == ledger.StatusPending {
+		return insertHold(ctx, tx, txn)
+	}
+
+	// Finalize - trigger fires to validate balance
+	_, err = tx.ExecContext(ctx,
+		`UPDATE transactions SET finalized = 1 WHERE id = ?`, txn.ID)
+	if err != nil {
+		return fmt.Errorf("finalize transaction: %w", err)
+	}
+	if err := appendChain(ctx, tx, txn.ID); err != nil {
+		return err
+	}
+	return publishPosted(ctx, tx, txn.ID)
+}
+
+// accountRule is what settings enforcement needs to know about an account.
+type accountRule struct {
+	code     int
+	category string
+	settings ledger.CodeSettings
+}
+
+// loadAccountRules looks up the code, category and code settings of every
+// account touched by entries.
+func loadAccountRules(ctx context.Context, tx *sql.Tx, entries []ledger.Entry) (map[string]accountRule, error) {
+	return newRuleCache().rules(ctx, tx, entries)
+}
+
+// ruleCache remembers account rules and code settings between transactions
+// written in the same SQL transaction, so a batch looks each up once.
+type ruleCache struct {
+	accounts map[string]accountRule
+	codes    map[int]ledger.CodeSettings
+}
+
+func newRuleCache() *ruleCache {
+	return &ruleCache{accounts: map[string]accountRule{}, codes: map[int]ledger.CodeSettings{}}
+}
+
+// rules returns the rule of every account touched by entries, looking up
+// only the accounts and codes it has not seen before.
+func (rc *ruleCache) rules(ctx context.Context, tx *sql.Tx, entries []ledger.Entry) (map[string]accountRule, error) {
+	rules := map[string]accountRule{}
+	for _, e := range entries {
+		if _, ok := rules[e.AccountID]; ok {
+			continue
+		}
+		if rule, ok := rc.accounts[e.AccountID]; ok {
+			rules[e.AccountID] = rule
+			continue
+		}
+		var rule accountRule
+		err := tx.QueryRowContext(ctx,
+			`SELECT code, category FROM accounts WHERE id = ?`, e.AccountID).Scan(&rule.code, &rule.category)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ledger.ErrAccountNotFound, e.AccountID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("lookup account %s: %w", e.AccountID, err)
+		}
 
-	// Load settings for all involved codes
-	codeSettingsCache := map[int]ledger.CodeSettings{}
-	for _, code := range accountCodes {
-		if _, ok := codeSettingsCache[code]; !ok {
-			var cs ledger.CodeSettings
-			cs = ledger.DefaultCodeSettings(code)
+		cs, ok := rc.codes[rule.code]
+		if !ok {
+			cs = ledger.DefaultCodeSettings(rule.code)
 			rows, qerr := tx.QueryContext(ctx,
-				`SELECT setting, value FROM coa_settings WHERE code = ?`, code)
+				`SELECT setting, value FROM coa_settings WHERE code = ?`, rule.code)
 			if qerr != nil {
-				return fmt.Errorf("load code settings %d: %w", code, qerr)
+				return nil, fmt.Errorf("load code settings %d: %w", rule.code, qerr)
 			}
 			for rows.Next() {
 				var name ledger.SettingName
 				var value string
 				if err := rows.Scan(&name, &value); err != nil {
 					rows.Close()
-					return fmt.Errorf("scan code setting: %w", err)
+					return nil, fmt.Errorf("scan code setting: %w", err)
 				}
 				switch name {
 				case ledger.SettingBlockInverted:
 					cs.BlockInverted = value == "1"
 				case ledger.SettingEntryDirection:
 					cs.EntryDirection = ledger.EntryDirection(value)
+				case ledger.SettingApprovalThreshold:
+					cs.ApprovalThreshold, _ = strconv.ParseInt(value, 10, 64)
 				}
 			}
 			rows.Close()
-			codeSettingsCache[code] = cs
-		}
-	}
-
-	// Insert all entries (with entry direction check)
-	for i := range txn.Entries {
-		txn.Entries[i].TransactionID = txn.ID
-		code := accountCodes[txn.Entries[i].AccountID]
-		cs := codeSettingsCache[code]
-
-		// Entry direction enforcement
-		if cs.EntryDirection == ledger.DirectionDebitOnly && txn.Entries[i].Amount < 0 {
-			return fmt.Errorf("%w: account %s (code %d) only allows debit entries",
-				ledger.ErrEntryDirectionViolation, txn.Entries[i].AccountID, code)
-		}
-		if cs.EntryDirection == ledger.DirectionCreditOnly && txn.Entries[i].Amount > 0 {
-			return fmt.Errorf("%w: account %s (code %d) only allows credit entries",
-				ledger.ErrEntryDirectionViolation, txn.Entries[i].AccountID, code)
-		}
-
-		_, err = tx.ExecContext(ctx,
-			`INSERT INTO entries (transaction_id, account_id, amount, currency) VALUES (?, ?, ?, ?)`,
-			txn.ID, txn.Entries[i].AccountID, txn.Entries[i].Amount, txn.Entries[i].Currency,
-		)
-		if err != nil {
-			return fmt.Errorf("insert entry %d: %w", i, err)
+			rc.codes[rule.code] = cs
 		}
+		rule.settings = cs
+		rc.accounts[e.AccountID] = rule
+		rules[e.AccountID] = rule
 	}
+	return rules, nil
+}
 
-	// Block inverted balance check (before finalization)
-	for acctID, code := range accountCodes {
-		cs := codeSettingsCache[code]
-		if !cs.BlockInverted {
+// checkInvertedBalances rejects txn if it would push a block_inverted
+// account past zero. Open holds from other pending transactions count
+// against the account, so a hold reserves the funds it covers.
+func checkInvertedBalances(ctx context.Context, tx *sql.Tx, txn *ledger.Transaction, rules map[string]accountRule) error {
+	for acctID, rule := range rules {
+		if !rule.settings.BlockInverted {
 			continue
 		}
 
@@ -133,32 +289,23 @@ func (s *Store) CreateTransaction(ctx context.Context, txn *ledger.Transaction)
 			}
 		}
 
+		heldDebits, heldCredits, err := pendingAmounts(ctx, tx, acctID, txn.ID)
+		if err != nil {
+			return err
+		}
+
 		projected := existingBalance + txnAmount
-		cat := accountCats[acctID]
-		isDebitNormal := cat == "assets" || cat == "expenses"
+		isDebitNormal := rule.category == "assets" || rule.category == "expenses"
 
-		if isDebitNormal && projected < 0 {
+		if isDebitNormal && projected+heldCredits < 0 {
 			return fmt.Errorf("%w: account %s (code %d) would have negative balance %d",
-				ledger.ErrInvertedBalance, acctID, code, projected)
+				ledger.ErrInvertedBalance, acctID, rule.code, projected+heldCredits)
 		}
-		if !isDebitNormal && projected > 0 {
+		if !isDebitNormal && projected+heldDebits > 0 {
 			return fmt.Errorf("%w: account %s (code %d) would have positive balance %d",
-				ledger.ErrInvertedBalance, acctID, code, projected)
+				ledger.ErrInvertedBalance, acctID, rule.code, projected+heldDebits)
 		}
 	}
-
-	// Finalize - trigger fires to validate balance
-	_, err = tx.ExecContext(ctx,
-		`UPDATE transactions SET finalized = 1 WHERE id = ?`, txn.ID)
-	if err != nil {
-		return fmt.Errorf("finalize transaction: %w", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit: %w", err)
-	}
-
-	txn.Finalized = true
 	return nil
 }
 
@@ -166,10 +313,22 @@ func (s *Store) GetTransaction(ctx context.Context, id string) (*ledger.Transact
 	var txn ledger.Transaction
 	var postedAt string
 	var finalized int
+	var hold holdRow
 
 	err := s.reader.QueryRowContext(ctx,
-		`SELECT id, description, finalized, posted_at FROM transactions WHERE id = ?`, id,
-	).Scan(&txn.ID, &txn.Description, &finalized, &postedAt)
+		`SELECT t.id, t.description, t.finalized, t.posted_at,
+			COALESCE(rof.original_id, ''), COALESCE(rby.reversal_id, ''), COALESCE(ik.key, ''), COALESCE(c.hash, ''),
+			`+holdColumns+`, COALESCE(a.status, '')
+		FROM transactions t
+		LEFT JOIN transaction_reversals rof ON rof.reversal_id = t.id
+		LEFT JOIN transaction_reversals rby ON rby.original_id = t.id
+		LEFT JOIN idempotency_keys ik ON ik.transaction_id = t.id
+		LEFT JOIN transaction_chain c ON c.transaction_id = t.id
+		LEFT JOIN pending_transactions p ON p.transaction_id = t.id
+		LEFT JOIN approvals a ON a.transaction_id = t.id
+		WHERE t.id = ?`, id,
+	).Scan(&txn.ID, &txn.Description, &finalized, &postedAt, &txn.ReversalOf, &txn.ReversedBy, &txn.IdempotencyKey, &txn.Hash,
+		&hold.status, &hold.expiresAt, &hold.expired, &txn.ApprovalStatus)
 	if err == sql.ErrNoRows {
 		return nil, ledger.ErrTransactionNotFound
 	}
@@ -179,6 +338,7 @@ func (s *Store) GetTransaction(ctx context.Context, id string) (*ledger.Transact
 
 	txn.Finalized = finalized == 1
 	txn.PostedAt, _ = time.Parse(time.RFC3339Nano, postedAt)
+	hold.apply(&txn)
 
 	entries, err := s.getEntriesForTransaction(ctx, id)
 	if err != nil {
@@ -189,78 +349,201 @@ func (s *Store) GetTransaction(ctx context.Context, id string) (*ledger.Transact
 	return &txn, nil
 }
 
-func (s *Store) ListTransactions(ctx context.Context, filter TxnFilter) ([]ledger.Transaction, error) {
-	query := `SELECT DISTINCT t.id, t.description, t.finalized, t.posted_at FROM transactions t`
+// ListTransactions returns one page of finalized transactions (or open
+// holds, with filter.Pending), newest first, keyed on (posted_at, id) so
+// pages stay stable while new transactions are posted.
+func (s *Store) ListTransactions(ctx context.Context, filter TxnFilter) (*ledger.TransactionPage, error) {
+	query := `SELECT t.id, t.description, t.finalized, t.posted_at, ` + holdColumns + `
+		FROM transactions t
+		LEFT JOIN pending_transactions p ON p.transaction_id = t.id`
 	args := []any{}
 
-	if filter.AccountID != "" {
-		query += ` JOIN entries e ON e.transaction_id = t.id WHERE e.account_id = ?`
-		args = append(args, filter.AccountID)
+	if filter.Pending {
+		query += ` WHERE t.finalized = 0 AND p.status = 'pending' AND julianday(p.expires_at) > julianday('now')`
 	} else {
-		query += ` WHERE 1=1`
+		query += ` WHERE t.finalized = 1`
 	}
 
-	query += ` AND t.finalized = 1 ORDER BY t.posted_at DESC`
+	if !filter.From.IsZero() {
+		query += ` AND julianday(t.posted_at) >= julianday(?)`
+		args = append(args, filter.From.UTC().Format(time.RFC3339Nano))
+	}
+	if !filter.To.IsZero() {
+		query += ` AND julianday(t.posted_at) <= julianday(?)`
+		args = append(args, filter.To.UTC().Format(time.RFC3339Nano))
+	}
+	if filter.Description != "" {
+		query += ` AND t.description LIKE ? ESCAPE '\'`
+		args = append(args, "%"+likeEscaper.Replace(filter.Description)+"%")
+	}
 
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(` LIMIT %d`, filter.Limit)
-		if filter.Offset > 0 {
-			query += fmt.Sprintf(` OFFSET %d`, filter.Offset)
+	// Entry-level filters must all hold for a single entry.
+	var conds []string
+	if filter.AccountID != "" {
+		conds = append(conds, `e.account_id = ?`)
+		args = append(args, filter.AccountID)
+	}
+	if filter.Currency != "" {
+		conds = append(conds, `e.currency = ?`)
+		args = append(args, filter.Currency)
+	}
+	if filter.Code != 0 {
+		conds = append(conds, `e.account_id IN (SELECT id FROM accounts WHERE code = ?)`)
+		args = append(args, filter.Code)
+	}
+	if filter.MinAmount > 0 {
+		conds = append(conds, `abs(e.amount) >= ?`)
+		args = append(args, filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		conds = append(conds, `abs(e.amount) <= ?`)
+		args = append(args, filter.MaxAmount)
+	}
+	if len(conds) > 0 {
+		query += ` AND EXISTS (SELECT 1 FROM entries e WHERE e.transaction_id = t.id AND ` +
+			strings.Join(conds, ` AND `) + `)`
+	}
+
+	if filter.Cursor != "" {
+		postedAt, id, err := decodeTxnCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
 		}
+		query += ` AND (julianday(t.posted_at) < julianday(?)
+			OR (julianday(t.posted_at) = julianday(?) AND t.id < ?))`
+		args = append(args, postedAt, postedAt, id)
 	}
 
+	// Fetch one extra row to learn whether another page follows.
+	limit := pageSize(filter.Limit)
+	query += ` ORDER BY julianday(t.posted_at) DESC, t.id DESC LIMIT ?`
+	args = append(args, limit+1)
+
 	rows, err := s.reader.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list transactions: %w", err)
 	}
-	defer rows.Close()
 
-	var txns []ledger.Transaction
+	page := &ledger.TransactionPage{Transactions: []ledger.Transaction{}}
+	var lastPostedAt string
 	for rows.Next() {
 		var txn ledger.Transaction
 		var postedAt string
 		var finalized int
-		if err := rows.Scan(&txn.ID, &txn.Description, &finalized, &postedAt); err != nil {
+		var hold holdRow
+		if err := rows.Scan(&txn.ID, &txn.Description, &finalized, &postedAt,
+			&hold.status, &hold.expiresAt, &hold.expired); err != nil {
+			rows.Close()
 			return nil, fmt.Errorf("scan transaction: %w", err)
 		}
+		if len(page.Transactions) == limit {
+			page.NextCursor = encodeTxnCursor(lastPostedAt, page.Transactions[limit-1].ID)
+			break
+		}
 		txn.Finalized = finalized == 1
 		txn.PostedAt, _ = time.Parse(time.RFC3339Nano, postedAt)
+		hold.apply(&txn)
+		page.Transactions = append(page.Transactions, txn)
+		lastPostedAt = postedAt
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+	// Release the reader connection before loading the page's entries.
+	rows.Close()
 
-		entries, err := s.getEntriesForTransaction(ctx, txn.ID)
-		if err != nil {
-			return nil, err
-		}
-		txn.Entries = entries
-
-		txns = append(txns, txn)
+	ids := make([]string, len(page.Transactions))
+	for i, t := range page.Transactions {
+		ids[i] = t.ID
 	}
-	return txns, rows.Err()
+	entries, err := s.entriesForTransactions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Transactions {
+		page.Transactions[i].Entries = entries[page.Transactions[i].ID]
+	}
+	return page, nil
 }
 
-func (s *Store) ListEntriesByAccount(ctx context.Context, accountID string, filter EntryFilter) ([]ledger.Entry, error) {
+// ListEntriesByAccount returns one page of an account's finalized entries,
+// newest first.
+func (s *Store) ListEntriesByAccount(ctx context.Context, accountID string, filter EntryFilter) (*ledger.EntryPage, error) {
 	query := `SELECT e.id, e.transaction_id, e.account_id, e.amount, e.currency, e.created_at
 		FROM entries e
 		JOIN transactions t ON t.id = e.transaction_id
-		WHERE e.account_id = ? AND t.finalized = 1
-		ORDER BY e.created_at DESC`
-
+		WHERE e.account_id = ? AND t.finalized = 1`
 	args := []any{accountID}
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(` LIMIT %d`, filter.Limit)
-		if filter.Offset > 0 {
-			query += fmt.Sprintf(` OFFSET %d`, filter.Offset)
+
+	if filter.Cursor != "" {
+		id, err := decodeEntryCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
 		}
+		query += ` AND e.id < ?`
+		args = append(args, id)
 	}
 
+	limit := pageSize(filter.Limit)
+	query += ` ORDER BY e.id DESC LIMIT ?`
+	args = append(args, limit+1)
+
 	rows, err := s.reader.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list entries: %w", err)
 	}
 	defer rows.Close()
 
-	return scanEntries(rows)
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	page := &ledger.EntryPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = encodeEntryCursor(entries[limit-1].ID)
+	}
+	if page.Entries == nil {
+		page.Entries = []ledger.Entry{}
+	}
+	return page, nil
 }
 
+// entriesForTransactions loads the entries of all ids in one query, keyed
+// by transaction ID.
+func (s *Store) entriesForTransactions(ctx context.Context, ids []string) (map[string][]ledger.Entry, error) {
+	byTxn := make(map[string][]ledger.Entry, len(ids))
+	if len(ids) == 0 {
+		return byTxn, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := s.reader.QueryContext(ctx,
+		`SELECT id, transaction_id, account_id, amount, currency, created_at FROM entries
+		WHERE transaction_id IN (`+placeholders+`) ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		byTxn[e.TransactionID] = append(byTxn[e.TransactionID], e)
+	}
+	return byTxn, nil
+}
+
+// likeEscaper escapes LIKE wildcards so description filters match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func (s *Store) getEntriesForTransaction(ctx context.Context, txnID string) ([]ledger.Entry, error) {
 	rows, err := s.reader.QueryContext(ctx,
 		`SELECT id, transaction_id, account_id, amount, currency, created_at FROM entries WHERE transaction_id = ? ORDER BY id`,
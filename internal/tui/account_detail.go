@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/simonvc/miniledger/internal/client"
@@ -12,19 +13,23 @@ import (
 )
 
 type accountDetailLoadedMsg struct {
-	account *ledger.Account
-	balance *client.BalanceResponse
-	entries []ledger.Entry
-	err     error
+	account   *ledger.Account
+	balance   *client.BalanceResponse
+	statement *ledger.AccountStatement
+	reporting string
+	rates     ledger.RateTable
+	err       error
 }
 
 type accountDetailModel struct {
-	account *ledger.Account
-	balance *client.BalanceResponse
-	entries []ledger.Entry
-	loading bool
-	err     error
-	width   int
+	account   *ledger.Account
+	balance   *client.BalanceResponse
+	statement *ledger.AccountStatement
+	reporting string           // the ledger's reporting currency
+	rates     ledger.RateTable // effective rates into reporting
+	loading   bool
+	err       error
+	width     int
 }
 
 func (m *accountDetailModel) init(c *client.Client, id string) tea.Cmd {
@@ -34,12 +39,20 @@ func (m *accountDetailModel) init(c *client.Client, id string) tea.Cmd {
 		if err != nil {
 			return accountDetailLoadedMsg{err: err}
 		}
-		bal, err := c.GetAccountBalance(context.Background(), id)
+		bal, err := c.GetAccountBalance(context.Background(), id, time.Time{})
 		if err != nil {
 			return accountDetailLoadedMsg{account: acct, err: err}
 		}
-		entries, err := c.ListAccountEntries(context.Background(), id)
-		return accountDetailLoadedMsg{account: acct, balance: bal, entries: entries, err: err}
+		st, err := c.AccountStatement(context.Background(), id, time.Time{}, time.Time{})
+		if err != nil {
+			return accountDetailLoadedMsg{account: acct, balance: bal, err: err}
+		}
+		info, err := c.LedgerInfo(context.Background())
+		if err != nil {
+			return accountDetailLoadedMsg{account: acct, balance: bal, statement: st, err: err}
+		}
+		rates, err := c.EffectiveFXRates(context.Background(), time.Time{})
+		return accountDetailLoadedMsg{account: acct, balance: bal, statement: st, reporting: info.ReportingCurrency, rates: rates, err: err}
 	}
 }
 
@@ -49,7 +62,9 @@ func (m accountDetailModel) update(msg tea.Msg) (accountDetailModel, tea.Cmd) {
 		m.loading = false
 		m.account = msg.account
 		m.balance = msg.balance
-		m.entries = msg.entries
+		m.statement = msg.statement
+		m.reporting = msg.reporting
+		m.rates = msg.rates
 		m.err = msg.err
 	}
 	return m, nil
@@ -80,31 +95,39 @@ func (m *accountDetailModel) view() string {
 			b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Balance:"), "See FX Position below"))
 		} else {
 			b.WriteString(fmt.Sprintf("%s %s %s\n", labelStyle.Render("Balance:"), m.balance.Formatted, m.balance.Currency))
+			if m.balance.Pending != 0 {
+				b.WriteString(fmt.Sprintf("%s %s %s\n", labelStyle.Render("Available:"), m.balance.AvailableFormatted, m.balance.Currency))
+			}
 		}
 	}
 	b.WriteString(fmt.Sprintf("%s %v\n", labelStyle.Render("System:"), m.account.IsSystem))
 	b.WriteString("\n")
 
-	if len(m.entries) == 0 {
+	if m.statement == nil || len(m.statement.Lines) == 0 {
 		b.WriteString(dimStyle.Render("  No entries."))
 	} else {
-		header := fmt.Sprintf("  %-4s %-36s %15s %s", "TYPE", "TRANSACTION", "AMOUNT", "CCY")
+		header := fmt.Sprintf("  %-10s %-28s %-18s %-2s %14s %16s %s", "DATE", "DESCRIPTION", "COUNTERPARTY", "", "AMOUNT", "BALANCE", "CCY")
 		b.WriteString(headerStyle.Render(header))
 		b.WriteString("\n")
 
-		for _, e := range m.entries {
+		for _, l := range m.statement.Lines {
 			direction := "DR"
-			amt := e.Amount
+			amt := l.Amount
 			if amt < 0 {
 				direction = "CR"
 				amt = -amt
 			}
-			formatted := ledger.FormatAmount(amt, e.Currency)
-			txnShort := e.TransactionID
-			if len(txnShort) > 34 {
-				txnShort = txnShort[:34] + ".."
+			desc := l.Description
+			if len(desc) > 28 {
+				desc = desc[:26] + ".."
+			}
+			counter := strings.Join(l.Counterparties, ",")
+			if len(counter) > 18 {
+				counter = counter[:16] + ".."
 			}
-			line := fmt.Sprintf("  %-4s %-36s %15s %s", direction, txnShort, formatted, e.Currency)
+			line := fmt.Sprintf("  %-10s %-28s %-18s %-2s %14s %16s %s",
+				l.PostedAt.Format("2006-01-02"), desc, counter, direction,
+				ledger.FormatAmount(amt, l.Currency), formatBalanceSheetAmt(l.Balance, l.Currency), l.Currency)
 			if direction == "DR" {
 				b.WriteString(debitStyle.Render(line))
 			} else {
@@ -115,11 +138,8 @@ func (m *accountDetailModel) view() string {
 	}
 
 	// FX Position & PnL for wildcard-currency accounts (e.g. ~fx)
-	if m.account.Currency == "*" && len(m.entries) > 0 {
-		byCurrency := make(map[string]int64)
-		for _, e := range m.entries {
-			byCurrency[e.Currency] += e.Amount
-		}
+	if m.account.Currency == "*" && m.statement != nil && len(m.statement.Lines) > 0 {
+		byCurrency := m.statement.Closing
 
 		currencies := make([]string, 0, len(byCurrency))
 		for ccy := range byCurrency {
@@ -130,28 +150,32 @@ func (m *accountDetailModel) view() string {
 		b.WriteString("\n")
 		b.WriteString(headerStyle.Render("  FX Position & PnL"))
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render(fmt.Sprintf("  %-6s %18s %18s", "CCY", "NET POSITION", "GEL EQUIV")))
+		rc := m.reporting
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %-6s %18s %18s", "CCY", "NET POSITION", rc+" EQUIV")))
 		b.WriteString("\n")
 
-		var totalGEL int64
+		var total int64
 		for _, ccy := range currencies {
 			bal := byCurrency[ccy]
-			gelEquiv := ledger.ToGEL(bal, ccy)
-			totalGEL += gelEquiv
-			b.WriteString(fmt.Sprintf("  %-6s %14s %-3s %14s GEL\n",
+			equiv := fmt.Sprintf("%18s", noRate)
+			if amt, err := m.rates.Convert(bal, ccy, rc); err == nil {
+				total += amt
+				equiv = fmt.Sprintf("%14s %s", ledger.FormatAmount(amt, rc), rc)
+			}
+			b.WriteString(fmt.Sprintf("  %-6s %14s %-3s %s\n",
 				ccy,
 				ledger.FormatAmount(bal, ccy), ccy,
-				ledger.FormatAmount(gelEquiv, ledger.ReportingCurrency)))
+				equiv))
 		}
 		b.WriteString(fmt.Sprintf("  %s\n", strings.Repeat("─", 44)))
 		label := "Net FX PnL"
-		gelStr := ledger.FormatAmount(totalGEL, ledger.ReportingCurrency) + " GEL"
-		if totalGEL > 0 {
-			b.WriteString(successStyle.Render(fmt.Sprintf("  %-24s %14s", label, gelStr)))
-		} else if totalGEL < 0 {
-			b.WriteString(errorStyle.Render(fmt.Sprintf("  %-24s %14s", label, gelStr)))
+		totalStr := ledger.FormatAmount(total, rc) + " " + rc
+		if total > 0 {
+			b.WriteString(successStyle.Render(fmt.Sprintf("  %-24s %14s", label, totalStr)))
+		} else if total < 0 {
+			b.WriteString(errorStyle.Render(fmt.Sprintf("  %-24s %14s", label, totalStr)))
 		} else {
-			b.WriteString(fmt.Sprintf("  %-24s %14s", label, gelStr))
+			b.WriteString(fmt.Sprintf("  %-24s %14s", label, totalStr))
 		}
 		b.WriteString("\n")
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -65,7 +66,7 @@ func (m *accountListModel) init(c *client.Client) tea.Cmd {
 		}
 		balances := make(map[string]*client.BalanceResponse, len(accounts))
 		for _, a := range accounts {
-			bal, err := c.GetAccountBalance(context.Background(), a.ID)
+			bal, err := c.GetAccountBalance(context.Background(), a.ID, time.Time{})
 			if err == nil {
 				balances[a.ID] = bal
 			}
@@ -2,11 +2,13 @@ package tui
 
 import (
 	"context"
+	"slices"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/simonvc/miniledger/internal/client"
+	"github.com/simonvc/miniledger/internal/ledger"
 )
 
 type mode int
@@ -18,15 +20,18 @@ const (
 	modeTransactionList
 	modeTransactionDetail
 	modeBalanceSheet
+	modeIncomeStatement
+	modeCashFlow
 	modeWizard
 	modeJournalEntry
 	modeLearn
 	modeRatios
 	modeOTCFX
 	modeConfig
+	modeApprovals
 )
 
-var tabModes = []mode{modeAbout, modeAccountList, modeTransactionList, modeBalanceSheet, modeRatios, modeOTCFX, modeConfig, modeLearn}
+var tabModes = []mode{modeAbout, modeAccountList, modeTransactionList, modeBalanceSheet, modeIncomeStatement, modeCashFlow, modeRatios, modeOTCFX, modeApprovals, modeConfig, modeLearn}
 
 func tabLabel(m mode) string {
 	switch m {
@@ -38,10 +43,16 @@ func tabLabel(m mode) string {
 		return "Transactions"
 	case modeBalanceSheet:
 		return "Balance Sheet"
+	case modeIncomeStatement:
+		return "P&L"
+	case modeCashFlow:
+		return "Cash Flow"
 	case modeRatios:
 		return "Ratios"
 	case modeOTCFX:
 		return "OTC FX"
+	case modeApprovals:
+		return "Approvals"
 	case modeConfig:
 		return "Config"
 	case modeLearn:
@@ -65,12 +76,19 @@ type App struct {
 	txnList       txnListModel
 	txnDetail     txnDetailModel
 	balanceSheet  balanceSheetModel
+	incomeStmt    incomeStatementModel
+	cashFlow      cashFlowModel
 	wizard        wizardModel
 	journalEntry  journalEntryModel
 	ratios        ratiosModel
 	otcFX         otcFXModel
+	approvals     approvalsModel
 	config        configModel
 	learn         learnModel
+
+	// currencies are the codes enabled in the ledger, offered when picking
+	// a currency; the defaults until the server's list is loaded.
+	currencies []string
 }
 
 func NewApp(c *client.Client) *App {
@@ -80,13 +98,44 @@ func NewApp(c *client.Client) *App {
 		tabIndex: 0,
 		about:    newAboutModel(),
 		otcFX:    newOTCFX(),
+
+		currencies: slices.Sorted(slices.Values(ledger.DefaultCurrencies)),
 	}
-	app.learn.init()
+	app.learn.init(app.currencies)
 	return app
 }
 
+// EmbeddedServer tells the App that it talks to a server it started without
+// API keys. Such a server cannot tell a checker from the maker, so the
+// Approvals tab only lists the queue.
+func (a *App) EmbeddedServer() {
+	a.approvals.readOnly = true
+}
+
+// currenciesLoadedMsg carries the codes of the ledger's enabled currencies.
+type currenciesLoadedMsg struct {
+	codes []string
+	err   error
+}
+
+func loadCurrencies(c *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		defs, err := c.ListCurrencies(context.Background(), true)
+		if err != nil {
+			return currenciesLoadedMsg{err: err}
+		}
+		codes := make([]string, len(defs))
+		for i, d := range defs {
+			codes[i] = d.Code
+		}
+		slices.Sort(codes)
+		return currenciesLoadedMsg{codes: codes}
+	}
+}
+
 func (a *App) Init() tea.Cmd {
 	return tea.Batch(
+		loadCurrencies(a.client),
 		a.accountList.init(a.client),
 		a.txnList.init(a.client),
 		a.balanceSheet.init(a.client),
@@ -108,6 +157,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.txnList.height = msg.Height - 6
 		a.balanceSheet.width = msg.Width
 		a.balanceSheet.height = msg.Height - 6
+		a.incomeStmt.width = msg.Width
+		a.incomeStmt.height = msg.Height - 6
+		a.cashFlow.width = msg.Width
+		a.cashFlow.height = msg.Height - 6
 		a.accountDetail.width = msg.Width
 		a.txnDetail.width = msg.Width
 		a.wizard.width = msg.Width
@@ -116,6 +169,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.ratios.height = msg.Height - 6
 		a.otcFX.width = msg.Width
 		a.otcFX.height = msg.Height - 6
+		a.approvals.width = msg.Width
+		a.approvals.height = msg.Height - 6
 		a.config.width = msg.Width
 		a.config.height = msg.Height - 6
 		a.learn.width = msg.Width
@@ -128,6 +183,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// This is needed because Init() fires all three loads concurrently but the bottom
 	// delegation only routes to the active mode's model.
 	switch typedMsg := msg.(type) {
+	case currenciesLoadedMsg:
+		// Without the server's list the default currencies stay.
+		if typedMsg.err == nil && len(typedMsg.codes) > 0 {
+			a.currencies = typedMsg.codes
+			a.learn.init(a.currencies)
+		}
+		return a, nil
 	case accountsLoadedMsg:
 		var cmd tea.Cmd
 		a.accountList, cmd = a.accountList.update(msg)
@@ -136,10 +198,26 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		a.txnList, cmd = a.txnList.update(msg)
 		return a, cmd
+	case txnsMoreMsg:
+		return a, a.txnList.loadMore(a.client)
+	case balanceSheetReloadMsg:
+		return a, a.balanceSheet.init(a.client)
 	case balanceSheetLoadedMsg:
 		var cmd tea.Cmd
 		a.balanceSheet, cmd = a.balanceSheet.update(msg)
 		return a, cmd
+	case incomeStatementReloadMsg:
+		return a, a.incomeStmt.init(a.client)
+	case incomeStatementLoadedMsg:
+		var cmd tea.Cmd
+		a.incomeStmt, cmd = a.incomeStmt.update(msg)
+		return a, cmd
+	case cashFlowReloadMsg:
+		return a, a.cashFlow.init(a.client)
+	case cashFlowLoadedMsg:
+		var cmd tea.Cmd
+		a.cashFlow, cmd = a.cashFlow.update(msg)
+		return a, cmd
 	case accountDetailLoadedMsg:
 		var cmd tea.Cmd
 		a.accountDetail, cmd = a.accountDetail.update(msg)
@@ -181,6 +259,78 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.accountList.init(a.client),
 			a.balanceSheet.init(a.client),
 		)
+	case txnReverseRequestMsg:
+		id := typedMsg.id
+		reason := typedMsg.reason
+		return a, func() tea.Msg {
+			rev, err := a.client.ReverseTransaction(context.Background(), id, reason)
+			return txnReversedMsg{id: id, rev: rev, err: err}
+		}
+	case txnReversedMsg:
+		a.txnDetail, _ = a.txnDetail.update(msg)
+		if typedMsg.err != nil {
+			return a, nil
+		}
+		a.statusMsg = "Transaction " + typedMsg.id[:8] + " reversed by " + typedMsg.rev.ID[:8]
+		if typedMsg.rev.ApprovalStatus == ledger.ApprovalPending {
+			a.statusMsg += ", awaiting approval"
+		}
+		return a, tea.Batch(
+			a.txnDetail.init(a.client, typedMsg.id),
+			a.txnList.init(a.client),
+			a.balanceSheet.init(a.client),
+		)
+	case txnHoldRequestMsg:
+		id := typedMsg.id
+		void := typedMsg.void
+		return a, func() tea.Msg {
+			var txn *ledger.Transaction
+			var err error
+			if void {
+				txn, err = a.client.VoidTransaction(context.Background(), id)
+			} else {
+				txn, err = a.client.FinalizeTransaction(context.Background(), id)
+			}
+			return txnHoldResolvedMsg{txn: txn, err: err}
+		}
+	case txnHoldResolvedMsg:
+		a.txnDetail, _ = a.txnDetail.update(msg)
+		if typedMsg.err != nil {
+			return a, nil
+		}
+		a.statusMsg = "Transaction " + typedMsg.txn.ID[:8] + " " + string(typedMsg.txn.Status)
+		return a, tea.Batch(
+			a.txnDetail.init(a.client, typedMsg.txn.ID),
+			a.txnList.init(a.client),
+			a.balanceSheet.init(a.client),
+		)
+	case approvalsLoadedMsg:
+		var cmd tea.Cmd
+		a.approvals, cmd = a.approvals.update(msg)
+		return a, cmd
+	case approvalDecisionMsg:
+		req := typedMsg
+		return a, func() tea.Msg {
+			var approval *ledger.Approval
+			var err error
+			if req.reject {
+				approval, err = a.client.RejectTransaction(context.Background(), req.id, req.comment)
+			} else {
+				approval, err = a.client.ApproveTransaction(context.Background(), req.id, req.comment)
+			}
+			return approvalDecidedMsg{approval: approval, err: err}
+		}
+	case approvalDecidedMsg:
+		a.approvals, _ = a.approvals.update(msg)
+		if typedMsg.err != nil {
+			return a, nil
+		}
+		a.statusMsg = "Transaction " + typedMsg.approval.TransactionID[:8] + " " + string(typedMsg.approval.Status)
+		return a, tea.Batch(
+			a.approvals.init(a.client),
+			a.txnList.init(a.client),
+			a.balanceSheet.init(a.client),
+		)
 	case otcFXDashLoadedMsg:
 		var cmd tea.Cmd
 		a.otcFX, cmd = a.otcFX.update(msg, a.client)
@@ -226,6 +376,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		a.config, cmd = a.config.update(msg, a.client)
 		return a, cmd
+	case settingHistoryMsg:
+		var cmd tea.Cmd
+		a.config, cmd = a.config.update(msg, a.client)
+		return a, cmd
 	}
 
 	// Modal modes: delegate ALL message types (not just keys)
@@ -281,6 +435,27 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, cmd
 	}
 
+	// When the reversal reason prompt is open, delegate all keys directly
+	if a.mode == modeTransactionDetail && a.txnDetail.reversing {
+		var cmd tea.Cmd
+		a.txnDetail, cmd = a.txnDetail.update(msg)
+		return a, cmd
+	}
+
+	// When the rejection comment prompt is open, delegate all keys directly
+	if a.mode == modeApprovals && a.approvals.rejecting {
+		var cmd tea.Cmd
+		a.approvals, cmd = a.approvals.update(msg)
+		return a, cmd
+	}
+
+	// When the balance sheet date picker is open, delegate all keys directly
+	if a.mode == modeBalanceSheet && a.balanceSheet.picking {
+		var cmd tea.Cmd
+		a.balanceSheet, cmd = a.balanceSheet.update(msg)
+		return a, cmd
+	}
+
 	// When account list has inline input (rename/delete confirm), delegate all keys directly
 	if a.mode == modeAccountList && (a.accountList.renaming || a.accountList.confirmDelete) {
 		var cmd tea.Cmd
@@ -318,14 +493,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, keys.New):
 			if a.mode == modeAccountList {
 				a.mode = modeWizard
-				a.wizard = newWizard()
+				a.wizard = newWizard(a.currencies)
 				return a, nil
 			}
 
 		case key.Matches(msg, keys.NewTxn):
 			if a.mode == modeTransactionList {
 				a.mode = modeJournalEntry
-				a.journalEntry = newJournalEntry()
+				a.journalEntry = newJournalEntry(a.currencies)
 				return a, a.journalEntry.loadAccounts(a.client)
 			}
 
@@ -362,10 +537,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.txnDetail, cmd = a.txnDetail.update(msg)
 	case modeBalanceSheet:
 		a.balanceSheet, cmd = a.balanceSheet.update(msg)
+	case modeIncomeStatement:
+		a.incomeStmt, cmd = a.incomeStmt.update(msg)
+	case modeCashFlow:
+		a.cashFlow, cmd = a.cashFlow.update(msg)
 	case modeRatios:
 		a.ratios, cmd = a.ratios.update(msg)
 	case modeOTCFX:
 		a.otcFX, cmd = a.otcFX.update(msg, a.client)
+	case modeApprovals:
+		a.approvals, cmd = a.approvals.update(msg)
 	case modeConfig:
 		a.config, cmd = a.config.update(msg, a.client)
 	case modeLearn:
@@ -382,10 +563,16 @@ func (a *App) refreshTab() tea.Cmd {
 		return a.txnList.init(a.client)
 	case modeBalanceSheet:
 		return a.balanceSheet.init(a.client)
+	case modeIncomeStatement:
+		return a.incomeStmt.init(a.client)
+	case modeCashFlow:
+		return a.cashFlow.init(a.client)
 	case modeRatios:
 		return a.ratios.init(a.client)
 	case modeOTCFX:
 		return a.otcFX.init(a.client)
+	case modeApprovals:
+		return a.approvals.init(a.client)
 	case modeConfig:
 		return a.config.init(a.client)
 	}
@@ -422,6 +609,10 @@ func (a *App) View() string {
 		content = a.txnDetail.view()
 	case modeBalanceSheet:
 		content = a.balanceSheet.view()
+	case modeIncomeStatement:
+		content = a.incomeStmt.view()
+	case modeCashFlow:
+		content = a.cashFlow.view()
 	case modeWizard:
 		content = a.wizard.view()
 	case modeJournalEntry:
@@ -430,6 +621,8 @@ func (a *App) View() string {
 		content = a.ratios.view()
 	case modeOTCFX:
 		content = a.otcFX.view()
+	case modeApprovals:
+		content = a.approvals.view()
 	case modeConfig:
 		content = a.config.view()
 	case modeLearn:
@@ -445,7 +638,7 @@ func (a *App) View() string {
 		status = errorStyle.Render(a.err.Error())
 	}
 
-	helpText := dimStyle.Render("tab:switch  enter:select  esc:back  n:new  d:delete  r:rename  t:new txn  f:fx deal  q:quit")
+	helpText := dimStyle.Render("tab:switch  enter:select  esc:back  n:new  d:delete  r:rename  t:new txn  v:reverse  c/x:capture/void  y/x:approve/reject  a:as-of  f:fx deal  q:quit")
 
 	return lipgloss.JoinVertical(lipgloss.Left,
 		tabs,
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonvc/miniledger/internal/client"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+// readOnlyApprovals explains why the embedded server cannot decide approvals.
+const readOnlyApprovals = "Approving needs API keys: run 'miniledger serve' and start the TUI with --server and --token."
+
+type approvalsLoadedMsg struct {
+	approvals []ledger.Approval
+	err       error
+}
+
+// approvalDecisionMsg asks the App to approve or reject a parked transaction.
+type approvalDecisionMsg struct {
+	id      string
+	reject  bool
+	comment string
+}
+
+// approvalDecidedMsg is sent after the server records the decision.
+type approvalDecidedMsg struct {
+	approval *ledger.Approval
+	err      error
+}
+
+type approvalsModel struct {
+	approvals    []ledger.Approval
+	cursor       int
+	loading      bool
+	err          error
+	width        int
+	height       int
+	rejecting    bool
+	commentInput textinput.Model
+
+	// readOnly is set when the server cannot tell the approver from the
+	// maker, so decisions are not offered.
+	readOnly bool
+}
+
+func (m *approvalsModel) init(c *client.Client) tea.Cmd {
+	m.loading = true
+	return func() tea.Msg {
+		approvals, err := c.ListApprovals(context.Background(), "")
+		return approvalsLoadedMsg{approvals: approvals, err: err}
+	}
+}
+
+func (m approvalsModel) update(msg tea.Msg) (approvalsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case approvalsLoadedMsg:
+		m.loading = false
+		m.approvals = msg.approvals
+		m.err = msg.err
+		if m.cursor >= len(m.approvals) {
+			m.cursor = max(len(m.approvals)-1, 0)
+		}
+
+	case approvalDecidedMsg:
+		m.rejecting = false
+		m.err = msg.err
+
+	case tea.KeyMsg:
+		if m.rejecting {
+			switch {
+			case key.Matches(msg, keys.Enter):
+				req := approvalDecisionMsg{id: m.selectedID(), reject: true, comment: strings.TrimSpace(m.commentInput.Value())}
+				return m, func() tea.Msg { return req }
+			case key.Matches(msg, keys.Escape):
+				m.rejecting = false
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.commentInput, cmd = m.commentInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.cursor < len(m.approvals)-1 {
+				m.cursor++
+			}
+		case key.Matches(msg, keys.Approve):
+			if id := m.selectedID(); id != "" && !m.readOnly {
+				m.err = nil
+				return m, func() tea.Msg { return approvalDecisionMsg{id: id} }
+			}
+		case key.Matches(msg, keys.Reject):
+			if m.selectedID() != "" && !m.readOnly {
+				m.rejecting = true
+				m.commentInput = textinput.New()
+				m.commentInput.Placeholder = "reason (optional)"
+				m.commentInput.CharLimit = 80
+				m.commentInput.Focus()
+				m.err = nil
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *approvalsModel) selectedID() string {
+	if m.cursor >= 0 && m.cursor < len(m.approvals) {
+		return m.approvals[m.cursor].TransactionID
+	}
+	return ""
+}
+
+func (m *approvalsModel) view() string {
+	if m.loading {
+		return "Loading approvals..."
+	}
+
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Approvals"))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("  Transactions above a code's APPROVAL_THRESHOLD wait here for a second user."))
+	b.WriteString("\n\n")
+
+	if len(m.approvals) == 0 {
+		b.WriteString(dimStyle.Render("  Nothing awaiting approval."))
+		if m.err != nil {
+			b.WriteString("\n\n" + errorStyle.Render("  Error: "+m.err.Error()))
+		}
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-10s %-16s %-12s %s", "TXN", "REQUESTED", "BY", "DESCRIPTION")
+	b.WriteString(headerStyle.Render(header))
+	b.WriteString("\n")
+
+	// Leave room for the selected request's callout below the list.
+	maxRows := m.height - 16
+	if maxRows < 1 {
+		maxRows = 5
+	}
+
+	start := 0
+	if m.cursor >= maxRows {
+		start = m.cursor - maxRows + 1
+	}
+
+	for i := start; i < len(m.approvals) && i < start+maxRows; i++ {
+		a := m.approvals[i]
+		desc := ""
+		if a.Transaction != nil {
+			desc = a.Transaction.Description
+		}
+		if len(desc) > 40 {
+			desc = desc[:38] + ".."
+		}
+		line := fmt.Sprintf("  %-10s %-16s %-12s %s",
+			a.TransactionID[:8],
+			a.RequestedAt.Local().Format("2006-01-02 15:04"),
+			a.RequestedBy,
+			desc,
+		)
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("> " + line[2:]))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	sel := m.approvals[m.cursor]
+	b.WriteString("\n")
+	b.WriteString(labelStyle.Render("  Reason: "))
+	b.WriteString(sel.Reason)
+	b.WriteString("\n")
+	if sel.Transaction != nil {
+		for _, e := range sel.Transaction.Entries {
+			if e.Amount > 0 {
+				b.WriteString(debitStyle.Render(fmt.Sprintf("    DR %-12s %s %s", e.AccountID, ledger.FormatAmount(e.Amount, e.Currency), e.Currency)))
+			} else {
+				b.WriteString(creditStyle.Render(fmt.Sprintf("    CR %-12s %s %s", e.AccountID, ledger.FormatAmount(-e.Amount, e.Currency), e.Currency)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if m.rejecting {
+		b.WriteString("\n")
+		b.WriteString(labelStyle.Render("  Reject " + sel.TransactionID[:8] + ": "))
+		b.WriteString(m.commentInput.View())
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render("  enter: reject  esc: cancel"))
+	} else if m.readOnly {
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render("  " + readOnlyApprovals))
+	} else {
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render("  y: approve  x: reject"))
+	}
+
+	if m.err != nil {
+		b.WriteString("\n\n" + errorStyle.Render("  Error: "+m.err.Error()))
+	}
+	return b.String()
+}
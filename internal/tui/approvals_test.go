@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+func keyPress(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+// The embedded server has no API keys and refuses every decision, so the
+// Approvals tab must not offer them.
+func TestApprovalsOnEmbeddedServer(t *testing.T) {
+	queue := approvalsLoadedMsg{approvals: []ledger.Approval{{
+		TransactionID: "0190f8a4-7b1e-7000-8000-000000000001",
+		Status:        ledger.ApprovalPending,
+		Reason:        "over the threshold",
+		RequestedBy:   "alice",
+	}}}
+
+	for _, readOnly := range []bool{false, true} {
+		m := approvalsModel{readOnly: readOnly}
+		m, _ = m.update(queue)
+
+		_, cmd := m.update(keyPress('y'))
+		var decided bool
+		if cmd != nil {
+			_, decided = cmd().(approvalDecisionMsg)
+		}
+		if decided == readOnly {
+			t.Errorf("readOnly %v: approving sent a decision: %v", readOnly, decided)
+		}
+		m, _ = m.update(keyPress('x'))
+		if m.rejecting == readOnly {
+			t.Errorf("readOnly %v: rejecting opened the comment prompt: %v", readOnly, m.rejecting)
+		}
+
+		view := m.view()
+		if strings.Contains(view, readOnlyApprovals) != readOnly {
+			t.Errorf("readOnly %v: view explains API keys are needed: %v", readOnly, !readOnly)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/simonvc/miniledger/internal/client"
 	"github.com/simonvc/miniledger/internal/ledger"
@@ -15,18 +17,28 @@ type balanceSheetLoadedMsg struct {
 	err error
 }
 
+// balanceSheetReloadMsg asks the App to reload the balance sheet after the
+// as-of date changed.
+type balanceSheetReloadMsg struct{}
+
 type balanceSheetModel struct {
 	bs      *ledger.BalanceSheet
 	loading bool
 	err     error
 	width   int
 	height  int
+
+	// As-of date picker. asOf is zero for the current position.
+	asOf     time.Time
+	picking  bool
+	pickDate time.Time
 }
 
 func (m *balanceSheetModel) init(c *client.Client) tea.Cmd {
 	m.loading = true
+	asOf := m.asOf
 	return func() tea.Msg {
-		bs, err := c.BalanceSheet(context.Background())
+		bs, err := c.BalanceSheet(context.Background(), asOf)
 		return balanceSheetLoadedMsg{bs: bs, err: err}
 	}
 }
@@ -37,11 +49,65 @@ func (m balanceSheetModel) update(msg tea.Msg) (balanceSheetModel, tea.Cmd) {
 		m.loading = false
 		m.bs = msg.bs
 		m.err = msg.err
+
+	case tea.KeyMsg:
+		if m.picking {
+			return m.updatePicker(msg)
+		}
+		if key.Matches(msg, keys.AsOf) {
+			m.picking = true
+			m.pickDate = m.asOf
+			if m.pickDate.IsZero() {
+				m.pickDate = time.Now().UTC()
+			}
+			y, mo, d := m.pickDate.Date()
+			m.pickDate = time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+		}
 	}
 	return m, nil
 }
 
+// updatePicker steps the picked date by day (left/right) or month (up/down).
+// Enter applies it, c resets to the current position, esc cancels.
+func (m balanceSheetModel) updatePicker(msg tea.KeyMsg) (balanceSheetModel, tea.Cmd) {
+	reload := func() tea.Msg { return balanceSheetReloadMsg{} }
+	switch msg.String() {
+	case "left", "h":
+		m.pickDate = m.pickDate.AddDate(0, 0, -1)
+	case "right", "l":
+		m.pickDate = m.pickDate.AddDate(0, 0, 1)
+	case "up", "k":
+		m.pickDate = m.pickDate.AddDate(0, -1, 0)
+	case "down", "j":
+		m.pickDate = m.pickDate.AddDate(0, 1, 0)
+	case "enter":
+		m.picking = false
+		m.asOf = ledger.EndOfDay(m.pickDate)
+		return m, reload
+	case "c":
+		m.picking = false
+		m.asOf = time.Time{}
+		return m, reload
+	case "esc":
+		m.picking = false
+	}
+	return m, nil
+}
+
+func (m *balanceSheetModel) asOfLabel() string {
+	if m.asOf.IsZero() {
+		return "As of: now"
+	}
+	return "As of: " + m.asOf.Format("2006-01-02")
+}
+
 func (m *balanceSheetModel) view() string {
+	if m.picking {
+		return fmt.Sprintf("  %s %s\n\n  %s",
+			headerStyle.Render("As of:"),
+			selectedStyle.Render("◀ "+m.pickDate.Format("2006-01-02")+" ▶"),
+			dimStyle.Render("←/→ day  ↑/↓ month  enter:apply  c:current  esc:cancel"))
+	}
 	if m.loading {
 		return "Loading balance sheet..."
 	}
@@ -53,6 +119,7 @@ func (m *balanceSheetModel) view() string {
 	}
 
 	var b strings.Builder
+	rc := m.bs.ReportingCurrency
 	w := m.width
 	if w < 60 {
 		w = 80
@@ -63,7 +130,7 @@ func (m *balanceSheetModel) view() string {
 	nameW := len("Name")
 	nativeW := 1
 	ccyW := 3
-	gelW := 1
+	rptW := 1
 	allLines := make([]ledger.BalanceSheetLine, 0, len(m.bs.Assets)+len(m.bs.Liabilities)+len(m.bs.Equity))
 	allLines = append(allLines, m.bs.Assets...)
 	allLines = append(allLines, m.bs.Liabilities...)
@@ -81,16 +148,19 @@ func (m *balanceSheetModel) view() string {
 		if l2 := len(l.Currency); l2 > ccyW {
 			ccyW = l2
 		}
-		gelAmt := ledger.ToGEL(l.Balance, l.Currency)
-		if l2 := len(formatBalanceSheetAmt(gelAmt, ledger.ReportingCurrency)); l2 > gelW {
-			gelW = l2
+		rptStr := noRate
+		if rptAmt, err := m.bs.Rates.Convert(l.Balance, l.Currency, rc); err == nil {
+			rptStr = formatBalanceSheetAmt(rptAmt, rc)
+		}
+		if l2 := len(rptStr); l2 > rptW {
+			rptW = l2
 		}
 	}
 	// +1 for inter-column gap
 	acctW++
 	ccyW++
 	// Cap name to remaining terminal width
-	fixedW := 4 + acctW + nativeW + ccyW + gelW + 4 // indent(4) + cols + " GEL"(4)
+	fixedW := 4 + acctW + nativeW + ccyW + rptW + 4 // indent(4) + cols + " CCY"(4)
 	maxNameW := w - fixedW
 	if maxNameW < 10 {
 		maxNameW = 10
@@ -102,12 +172,12 @@ func (m *balanceSheetModel) view() string {
 		nameW = 40
 	}
 	nameW++ // +1 gap after name
-	// Width of the label field in total rows so GEL amount aligns with line items
+	// Width of the label field in total rows so the reporting amount aligns with line items
 	totalLabelW := acctW + nameW + nativeW + ccyW
 
 	b.WriteString(titleStyle.Render(centerStr("BALANCE SHEET", w)))
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render(centerStr("Reporting currency: "+ledger.ReportingCurrency, w)))
+	b.WriteString(dimStyle.Render(centerStr("Reporting currency: "+rc+"  ·  "+m.asOfLabel()+"  (a: change)", w)))
 	b.WriteString("\n\n")
 
 	renderSection := func(title string, lines []ledger.BalanceSheetLine) int64 {
@@ -116,33 +186,35 @@ func (m *balanceSheetModel) view() string {
 			b.WriteString(dimStyle.Render("    (no entries)") + "\n\n")
 			return 0
 		}
-		var totalGEL int64
+		var totalRpt int64
 		for _, l := range lines {
 			name := l.AccountName
 			if len(name) > nameW-2 {
 				name = name[:nameW-2] + ".."
 			}
 			nativeAmt := formatBalanceSheetAmt(l.Balance, l.Currency)
-			gelAmt := ledger.ToGEL(l.Balance, l.Currency)
-			totalGEL += gelAmt
-			gelStr := formatBalanceSheetAmt(gelAmt, ledger.ReportingCurrency)
-			b.WriteString(fmt.Sprintf("    %-*s%-*s%*s %-*s%*s GEL\n",
-				acctW, l.AccountID, nameW, name, nativeW, nativeAmt, ccyW, l.Currency, gelW, gelStr))
+			rptStr := noRate
+			if rptAmt, err := m.bs.Rates.Convert(l.Balance, l.Currency, rc); err == nil {
+				totalRpt += rptAmt
+				rptStr = formatBalanceSheetAmt(rptAmt, rc)
+			}
+			b.WriteString(fmt.Sprintf("    %-*s%-*s%*s %-*s%*s %s\n",
+				acctW, l.AccountID, nameW, name, nativeW, nativeAmt, ccyW, l.Currency, rptW, rptStr, rc))
 		}
-		b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("─", totalLabelW+gelW+4)))
-		b.WriteString(fmt.Sprintf("    %-*s%*s GEL\n",
-			totalLabelW, "Total "+title, gelW, formatBalanceSheetAmt(totalGEL, ledger.ReportingCurrency)))
+		b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("─", totalLabelW+rptW+4)))
+		b.WriteString(fmt.Sprintf("    %-*s%*s %s\n",
+			totalLabelW, "Total "+title, rptW, formatBalanceSheetAmt(totalRpt, rc), rc))
 		b.WriteString("\n")
-		return totalGEL
+		return totalRpt
 	}
 
 	renderSection("Assets", m.bs.Assets)
-	totalLiabGEL := renderSection("Liabilities", m.bs.Liabilities)
-	totalEquityGEL := renderSection("Equity", m.bs.Equity)
+	totalLiab := renderSection("Liabilities", m.bs.Liabilities)
+	totalEquity := renderSection("Equity", m.bs.Equity)
 
-	b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("═", totalLabelW+gelW+4)))
-	b.WriteString(fmt.Sprintf("    %-*s%*s GEL\n",
-		totalLabelW, "Total L + E", gelW, formatBalanceSheetAmt(totalLiabGEL+totalEquityGEL, ledger.ReportingCurrency)))
+	b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("═", totalLabelW+rptW+4)))
+	b.WriteString(fmt.Sprintf("    %-*s%*s %s\n",
+		totalLabelW, "Total L + E", rptW, formatBalanceSheetAmt(totalLiab+totalEquity, rc), rc))
 
 	b.WriteString("\n")
 	if m.bs.Balanced {
@@ -154,6 +226,10 @@ func (m *balanceSheetModel) view() string {
 	return b.String()
 }
 
+// noRate stands in for a reporting-currency amount whose currency has no
+// FX rate. Such amounts are left out of totals.
+const noRate = "no rate"
+
 func formatBalanceSheetAmt(amount int64, currency string) string {
 	if amount < 0 {
 		return "(" + ledger.FormatAmount(-amount, currency) + ")"
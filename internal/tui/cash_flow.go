@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonvc/miniledger/internal/client"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+type cashFlowLoadedMsg struct {
+	cf  *ledger.CashFlowStatement
+	err error
+}
+
+// cashFlowReloadMsg asks the App to reload after the year changed.
+type cashFlowReloadMsg struct{}
+
+type cashFlowModel struct {
+	cf      *ledger.CashFlowStatement
+	loading bool
+	err     error
+	width   int
+	height  int
+	year    int // fiscal year shown; 0 until first load
+}
+
+func (m *cashFlowModel) init(c *client.Client) tea.Cmd {
+	m.loading = true
+	if m.year == 0 {
+		m.year = time.Now().UTC().Year()
+	}
+	from := time.Date(m.year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := ledger.EndOfDay(time.Date(m.year, time.December, 31, 0, 0, 0, 0, time.UTC))
+	return func() tea.Msg {
+		cf, err := c.CashFlowStatement(context.Background(), from, to)
+		return cashFlowLoadedMsg{cf: cf, err: err}
+	}
+}
+
+func (m cashFlowModel) update(msg tea.Msg) (cashFlowModel, tea.Cmd) {
+	reload := func() tea.Msg { return cashFlowReloadMsg{} }
+	switch msg := msg.(type) {
+	case cashFlowLoadedMsg:
+		m.loading = false
+		m.cf = msg.cf
+		m.err = msg.err
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "left", "h":
+			m.year--
+			return m, reload
+		case "right", "l":
+			m.year++
+			return m, reload
+		}
+	}
+	return m, nil
+}
+
+func (m *cashFlowModel) view() string {
+	if m.loading {
+		return "Loading cash flow statement..."
+	}
+	if m.err != nil {
+		return errorStyle.Render("Error: " + m.err.Error())
+	}
+	if m.cf == nil {
+		return dimStyle.Render("No data available.")
+	}
+
+	var b strings.Builder
+	rc := m.cf.ReportingCurrency
+	w := m.width
+	if w < 60 {
+		w = 80
+	}
+
+	b.WriteString(titleStyle.Render(centerStr("CASH FLOW STATEMENT", w)))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(centerStr(fmt.Sprintf("Fiscal year %d  ·  Cash accounts 1010/1060  (←/→: year)", m.year), w)))
+	b.WriteString("\n\n")
+
+	for _, sec := range []ledger.CashFlowSection{m.cf.Operating, m.cf.Investing, m.cf.Financing} {
+		title := strings.ToUpper(string(sec.Activity[:1])) + string(sec.Activity[1:]) + " activities"
+		b.WriteString(fmt.Sprintf("  %s\n", headerStyle.Render(title)))
+		if len(sec.Lines) == 0 {
+			b.WriteString(dimStyle.Render("    (no movements)") + "\n\n")
+			continue
+		}
+		for _, l := range sec.Lines {
+			label := fmt.Sprintf("%d %s", l.Code, l.Name)
+			if len(label) > 40 {
+				label = label[:38] + ".."
+			}
+			b.WriteString(fmt.Sprintf("    %-42s%16s %s\n", label, formatBalanceSheetAmt(l.Amount, l.Currency), l.Currency))
+		}
+		b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("─", 62)))
+		b.WriteString(fmt.Sprintf("    %-42s%16s %s\n\n", "Net cash from "+string(sec.Activity),
+			formatBalanceSheetAmt(sec.Total, rc), rc))
+	}
+
+	ccys := map[string]bool{}
+	for _, bal := range []map[string]int64{m.cf.Opening, m.cf.Closing, m.cf.NetChange} {
+		for ccy := range bal {
+			ccys[ccy] = true
+		}
+	}
+	sorted := make([]string, 0, len(ccys))
+	for ccy := range ccys {
+		sorted = append(sorted, ccy)
+	}
+	sort.Strings(sorted)
+
+	b.WriteString(fmt.Sprintf("  %s\n", headerStyle.Render("Cash position")))
+	b.WriteString(dimStyle.Render(fmt.Sprintf("    %-6s%16s%16s%16s", "CCY", "Opening", "Net change", "Closing")) + "\n")
+	for _, ccy := range sorted {
+		b.WriteString(fmt.Sprintf("    %-6s%16s%16s%16s\n", ccy,
+			formatBalanceSheetAmt(m.cf.Opening[ccy], ccy),
+			formatBalanceSheetAmt(m.cf.NetChange[ccy], ccy),
+			formatBalanceSheetAmt(m.cf.Closing[ccy], ccy)))
+	}
+	b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("═", 62)))
+	b.WriteString(fmt.Sprintf("    %-42s%16s %s", "Net change in cash", formatBalanceSheetAmt(m.cf.NetChangeTotal, rc), rc))
+
+	return b.String()
+}
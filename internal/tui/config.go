@@ -2,8 +2,10 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,6 +26,16 @@ type settingUpdatedMsg struct {
 
 type configFlashClearMsg struct{}
 
+// settingHistoryMsg carries the latest audit log changes to each code's
+// settings, newest first.
+type settingHistoryMsg struct {
+	history map[int][]ledger.AuditLogEntry
+	err     error
+}
+
+// configHistoryRows is how many changes the callout shows for a code.
+const configHistoryRows = 3
+
 type configRow struct {
 	code     int
 	name     string
@@ -47,6 +59,7 @@ type configModel struct {
 	width    int
 	height   int
 	flashRow int // row index to flash, -1 for none
+	history  map[int][]ledger.AuditLogEntry
 }
 
 // validDirection checks if a direction is compatible with block-inverted for a category.
@@ -77,6 +90,32 @@ func correctedDirection(cat ledger.Category) ledger.EntryDirection {
 
 func (m *configModel) init(c *client.Client) tea.Cmd {
 	m.loading = true
+	return tea.Batch(loadSettings(c), loadSettingHistory(c))
+}
+
+// loadSettingHistory fetches recent setting changes from the audit log and
+// groups them by code.
+func loadSettingHistory(c *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		page, err := c.ListAuditLog(context.Background(), client.AuditLogQuery{Operation: "setting", Limit: 500})
+		if err != nil {
+			return settingHistoryMsg{err: err}
+		}
+		history := map[int][]ledger.AuditLogEntry{}
+		for _, e := range page.Entries {
+			prefix, _, _ := strings.Cut(e.Target, "/")
+			code, err := strconv.Atoi(prefix)
+			if err != nil || len(history[code]) >= configHistoryRows {
+				continue
+			}
+			history[code] = append(history[code], e)
+		}
+		return settingHistoryMsg{history: history}
+	}
+}
+
+// loadSettings builds a row per chart code with its resolved settings.
+func loadSettings(c *client.Client) tea.Cmd {
 	return func() tea.Msg {
 		// Load chart of accounts (both predefined and system)
 		chart := ledger.AllChartEntries()
@@ -102,6 +141,8 @@ func (m *configModel) init(c *client.Client) tea.Cmd {
 				cs.BlockInverted = s.Value == "1"
 			case ledger.SettingEntryDirection:
 				cs.EntryDirection = ledger.EntryDirection(s.Value)
+			case ledger.SettingApprovalThreshold:
+				cs.ApprovalThreshold, _ = strconv.ParseInt(s.Value, 10, 64)
 			}
 			settingsMap[s.Code] = cs
 		}
@@ -161,6 +202,13 @@ func (m configModel) update(msg tea.Msg, c *client.Client) (configModel, tea.Cmd
 		if msg.err != nil {
 			m.err = msg.err
 		}
+		return m, loadSettingHistory(c)
+
+	case settingHistoryMsg:
+		// History is informational; a failure to load it leaves the callout without it
+		if msg.err == nil {
+			m.history = msg.history
+		}
 
 	case configFlashClearMsg:
 		m.flashRow = -1
@@ -278,22 +326,24 @@ func (m *configModel) view() string {
 	nameW := 34
 	normalW := 9
 	blockW := 16
+	dirW := 14
 
 	// Header
-	header := fmt.Sprintf("  %-*s%-*s%-*s%-*s%s",
+	header := fmt.Sprintf("  %-*s%-*s%-*s%-*s%-*s%s",
 		codeW, "CODE",
 		nameW, "NAME",
 		normalW, "NORMAL",
 		blockW, "BLOCK_INVERTED",
-		"ENTRY_DIR",
+		dirW, "ENTRY_DIR",
+		"APPROVAL_ABOVE",
 	)
 	b.WriteString(headerStyle.Render(header))
 	b.WriteString("\n")
 
 	// Budget within m.height (which is termHeight-6):
-	// title+margin(2) + subtitle(1) + blank(1) + header(1) + footer(2) + callout \n\n+box(~15) = 22
+	// title+margin(2) + subtitle(1) + blank(1) + header(1) + footer(2) + callout \n\n+box(~20) = 27
 	// Plus app shell adds tabs(1)+blank(1)+blank(1)+help(1)+status(1) outside m.height → need extra 1
-	maxRows := m.height - 23
+	maxRows := m.height - 28
 	if maxRows < 5 {
 		maxRows = 5
 	}
@@ -319,7 +369,11 @@ func (m *configModel) view() string {
 
 		// Pad to column width first, then apply styling (ANSI codes break %-*s padding)
 		blockCell := fmt.Sprintf("%-*s", blockW, blockRaw)
-		dirCell := dirRaw
+		dirCell := fmt.Sprintf("%-*s", dirW, dirRaw)
+		approval := dimStyle.Render("-")
+		if row.settings.ApprovalThreshold > 0 {
+			approval = strconv.FormatInt(row.settings.ApprovalThreshold, 10)
+		}
 
 		if i == m.flashRow {
 			dirCell = successStyle.Render(dirCell)
@@ -332,12 +386,13 @@ func (m *configModel) view() string {
 			}
 		}
 
-		line := fmt.Sprintf("  %-*d%-*s%-*s%s%s",
+		line := fmt.Sprintf("  %-*d%-*s%-*s%s%s%s",
 			codeW, row.code,
 			nameW, name,
 			normalW, normal,
 			blockCell,
 			dirCell,
+			approval,
 		)
 
 		if i == m.cursor {
@@ -373,6 +428,8 @@ func (m *configModel) view() string {
 		lines = append(lines, "")
 
 		lines = append(lines, configSuggestion(row.code, row.category, normal)...)
+		lines = append(lines, "")
+		lines = append(lines, configHistory(m.history[row.code])...)
 
 		b.WriteString("\n\n")
 		b.WriteString(boxStyle.Render(strings.Join(lines, "\n")))
@@ -381,6 +438,49 @@ func (m *configModel) view() string {
 	return b.String()
 }
 
+// configHistory renders the latest changes to a code's settings.
+func configHistory(entries []ledger.AuditLogEntry) []string {
+	lines := []string{headerStyle.Render("") + "  Recent changes:"}
+	if len(entries) == 0 {
+		return append(lines, dimStyle.Render("  No changes recorded."))
+	}
+	for _, e := range entries {
+		var before, after ledger.CoASetting
+		json.Unmarshal(e.Before, &before)
+		json.Unmarshal(e.After, &after)
+		name := before.Setting
+		if name == "" {
+			name = after.Setting
+		}
+		label := "ENTRY_DIR"
+		switch name {
+		case ledger.SettingBlockInverted:
+			label = "BLOCK_INVERTED"
+		case ledger.SettingApprovalThreshold:
+			label = "APPROVAL_ABOVE"
+		}
+		lines = append(lines, fmt.Sprintf("  %-16s%s  %s",
+			label,
+			settingValueLabel(name, len(e.Before) > 0, before.Value)+" -> "+settingValueLabel(name, len(e.After) > 0, after.Value),
+			dimStyle.Render(fmt.Sprintf("%s by %s", e.At.Local().Format("2006-01-02 15:04"), e.Actor)),
+		))
+	}
+	return lines
+}
+
+// settingValueLabel shows a stored setting value as the config table does.
+func settingValueLabel(name ledger.SettingName, set bool, value string) string {
+	switch {
+	case !set:
+		return "default"
+	case name == ledger.SettingBlockInverted && value == "1":
+		return "[x]"
+	case name == ledger.SettingBlockInverted:
+		return "[ ]"
+	}
+	return value
+}
+
 // Per-code hint overrides. Only codes that deserve a specific callout go here.
 type codeHint struct {
 	block string
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/simonvc/miniledger/internal/client"
+	"github.com/simonvc/miniledger/internal/ledger"
+)
+
+type incomeStatementLoadedMsg struct {
+	is  *ledger.IncomeStatement
+	err error
+}
+
+// incomeStatementReloadMsg asks the App to reload after the year changed.
+type incomeStatementReloadMsg struct{}
+
+type incomeStatementModel struct {
+	is      *ledger.IncomeStatement
+	loading bool
+	err     error
+	width   int
+	height  int
+	year    int // fiscal year shown; 0 until first load
+}
+
+func (m *incomeStatementModel) init(c *client.Client) tea.Cmd {
+	m.loading = true
+	if m.year == 0 {
+		m.year = time.Now().UTC().Year()
+	}
+	from := time.Date(m.year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := ledger.EndOfDay(time.Date(m.year, time.December, 31, 0, 0, 0, 0, time.UTC))
+	return func() tea.Msg {
+		is, err := c.IncomeStatement(context.Background(), from, to)
+		return incomeStatementLoadedMsg{is: is, err: err}
+	}
+}
+
+func (m incomeStatementModel) update(msg tea.Msg) (incomeStatementModel, tea.Cmd) {
+	reload := func() tea.Msg { return incomeStatementReloadMsg{} }
+	switch msg := msg.(type) {
+	case incomeStatementLoadedMsg:
+		m.loading = false
+		m.is = msg.is
+		m.err = msg.err
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "left", "h":
+			m.year--
+			return m, reload
+		case "right", "l":
+			m.year++
+			return m, reload
+		}
+	}
+	return m, nil
+}
+
+func (m *incomeStatementModel) view() string {
+	if m.loading {
+		return "Loading income statement..."
+	}
+	if m.err != nil {
+		return errorStyle.Render("Error: " + m.err.Error())
+	}
+	if m.is == nil {
+		return dimStyle.Render("No data available.")
+	}
+
+	var b strings.Builder
+	rc := m.is.ReportingCurrency
+	w := m.width
+	if w < 60 {
+		w = 80
+	}
+
+	b.WriteString(titleStyle.Render(centerStr("INCOME STATEMENT", w)))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(centerStr(fmt.Sprintf("Fiscal year %d  ·  Reporting currency: %s  (←/→: year)", m.year, rc), w)))
+	b.WriteString("\n\n")
+
+	renderSection := func(title string, groups []ledger.IncomeStatementGroup, total int64) {
+		b.WriteString(fmt.Sprintf("  %s\n", headerStyle.Render(title)))
+		if len(groups) == 0 {
+			b.WriteString(dimStyle.Render("    (no entries)") + "\n\n")
+			return
+		}
+		for _, g := range groups {
+			b.WriteString(fmt.Sprintf("    %s\n", dimStyle.Render(fmt.Sprintf("%d %s", g.Code, g.Name))))
+			for _, l := range g.Lines {
+				name := l.AccountName
+				if len(name) > 28 {
+					name = name[:26] + ".."
+				}
+				b.WriteString(fmt.Sprintf("      %-12s%-30s%16s %s\n",
+					l.AccountID, name, formatBalanceSheetAmt(l.Amount, l.Currency), l.Currency))
+			}
+			if len(g.Subtotals) > 1 {
+				ccys := make([]string, 0, len(g.Subtotals))
+				for ccy := range g.Subtotals {
+					ccys = append(ccys, ccy)
+				}
+				sort.Strings(ccys)
+				for _, ccy := range ccys {
+					b.WriteString(fmt.Sprintf("      %-42s%16s %s\n", "Subtotal", formatBalanceSheetAmt(g.Subtotals[ccy], ccy), ccy))
+				}
+			}
+			b.WriteString(fmt.Sprintf("      %-42s%16s %s\n", fmt.Sprintf("Total %d", g.Code), formatBalanceSheetAmt(g.Total, rc), rc))
+		}
+		b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("─", 64)))
+		b.WriteString(fmt.Sprintf("    %-44s%16s %s\n\n", "Total "+title, formatBalanceSheetAmt(total, rc), rc))
+	}
+
+	renderSection("Revenue", m.is.Revenue, m.is.TotalRevenue)
+	renderSection("Expenses", m.is.Expenses, m.is.TotalExpenses)
+
+	b.WriteString(fmt.Sprintf("    %s\n", strings.Repeat("═", 64)))
+	net := fmt.Sprintf("    %-44s%16s %s", "Net Income", formatBalanceSheetAmt(m.is.NetIncome, rc), rc)
+	if m.is.NetIncome < 0 {
+		b.WriteString(errorStyle.Render(net))
+	} else {
+		b.WriteString(successStyle.Render(net))
+	}
+
+	return b.String()
+}
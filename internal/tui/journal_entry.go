@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -72,7 +73,7 @@ type journalEntryModel struct {
 	width     int
 }
 
-func newJournalEntry() journalEntryModel {
+func newJournalEntry(currencies []string) journalEntryModel {
 	descInput := textinput.New()
 	descInput.Placeholder = "e.g. Customer deposit"
 	descInput.CharLimit = 100
@@ -92,7 +93,7 @@ func newJournalEntry() journalEntryModel {
 		accountInput: acctInput,
 		amountInput:  amtInput,
 		isDebit:      true,
-		curOptions:   ledger.CurrencyCodes(),
+		curOptions:   currencies,
 	}
 }
 
@@ -123,6 +124,9 @@ func (m journalEntryModel) update(msg tea.Msg, c *client.Client) (journalEntryMo
 		}
 		m.done = true
 		m.statusMsg = fmt.Sprintf("Transaction %s created!", msg.txn.ID[:8])
+		if msg.txn.ApprovalStatus == ledger.ApprovalPending {
+			m.statusMsg = fmt.Sprintf("Transaction %s is awaiting approval", msg.txn.ID[:8])
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -153,7 +157,7 @@ func (m journalEntryModel) update(msg tea.Msg, c *client.Client) (journalEntryMo
 		// Load ratios when entering confirm step
 		if prevStep != jeStepConfirm && m.step == jeStepConfirm {
 			loadRatios := func() tea.Msg {
-				ratios, err := c.RegulatoryRatios(context.Background())
+				ratios, err := c.RegulatoryRatios(context.Background(), time.Time{})
 				return jeRatiosLoadedMsg{ratios: ratios, err: err}
 			}
 			if cmd != nil {
@@ -500,7 +504,7 @@ func (m *journalEntryModel) view() string {
 
 		for i := start; i < end; i++ {
 			code := m.curOptions[i]
-			cur := ledger.Currencies[code]
+			cur, _ := ledger.LookupCurrency(code)
 			label := fmt.Sprintf("%s - %s", code, cur.Name)
 			if i == m.currencyIdx {
 				b.WriteString(selectedStyle.Render("  > "+label) + "\n")
@@ -15,6 +15,12 @@ type keyMap struct {
 	Down       key.Binding
 	Help       key.Binding
 	NewTxn     key.Binding
+	Reverse    key.Binding
+	AsOf       key.Binding
+	Capture    key.Binding
+	Void       key.Binding
+	Approve    key.Binding
+	Reject     key.Binding
 }
 
 var keys = keyMap{
@@ -66,4 +72,28 @@ var keys = keyMap{
 		key.WithKeys("t"),
 		key.WithHelp("t", "new transaction"),
 	),
+	Reverse: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "reverse transaction"),
+	),
+	AsOf: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "as-of date"),
+	),
+	Capture: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "capture pending transaction"),
+	),
+	Void: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "void pending transaction"),
+	),
+	Approve: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "approve transaction"),
+	),
+	Reject: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "reject transaction"),
+	),
 }
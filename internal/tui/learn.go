@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -64,9 +65,9 @@ type learnModel struct {
 	ratios *ledger.RegulatoryRatios
 }
 
-func (m *learnModel) init() {
+func (m *learnModel) init(currencies []string) {
 	m.templates = ledger.Templates
-	m.curOptions = ledger.CurrencyCodes()
+	m.curOptions = currencies
 	for i, c := range m.curOptions {
 		if c == "USD" {
 			m.currencyIdx = i
@@ -132,7 +133,7 @@ func (m learnModel) update(msg tea.Msg, c *client.Client) (learnModel, tea.Cmd)
 
 		if prevState != learnConfirm && m.state == learnConfirm {
 			loadRatios := func() tea.Msg {
-				ratios, err := c.RegulatoryRatios(context.Background())
+				ratios, err := c.RegulatoryRatios(context.Background(), time.Time{})
 				return learnRatiosLoadedMsg{ratios: ratios, err: err}
 			}
 			if cmd != nil {
@@ -595,7 +596,7 @@ func (m *learnModel) viewCurrencyPicker(b *strings.Builder, idx int) {
 	}
 	for i := start; i < end; i++ {
 		code := m.curOptions[i]
-		cur := ledger.Currencies[code]
+		cur, _ := ledger.LookupCurrency(code)
 		label := fmt.Sprintf("%s - %s", code, cur.Name)
 		if i == idx {
 			b.WriteString(selectedStyle.Render("  > "+label) + "\n")
@@ -6,6 +6,7 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -31,7 +32,8 @@ type currencyPosition struct {
 	Liabilities int64 // positive (absolute), minor units
 	Equity      int64 // positive (absolute), minor units
 	Net         int64 // raw sum of all balances in this currency
-	GELEquiv    int64 // ToGEL(Net, Currency)
+	Equiv       int64 // Net in the reporting currency
+	NoRate      bool  // no rate to the reporting currency; Equiv is zero
 }
 
 // otcFXDashLoadedMsg is sent when all dashboard data is loaded.
@@ -40,6 +42,8 @@ type otcFXDashLoadedMsg struct {
 	fxTxns    []ledger.Transaction
 	accounts  []ledger.Account
 	balances  map[string]*client.BalanceResponse
+	reporting string
+	rates     ledger.RateTable
 	err       error
 }
 
@@ -77,10 +81,12 @@ type otcFXModel struct {
 	rateExact bool
 
 	// Dashboard data
+	reporting   string           // the ledger's reporting currency
+	rates       ledger.RateTable // reporting-currency rates effective at load time
 	fxEntries   []ledger.Entry
 	fxTxns      []ledger.Transaction
 	positions   []currencyPosition
-	totalGEL    int64
+	totalEquiv  int64
 	dashLoading bool
 
 	// Shared
@@ -127,19 +133,31 @@ func (m *otcFXModel) init(c *client.Client) tea.Cmd {
 			return otcFXDashLoadedMsg{err: err}
 		}
 
-		fxEntries, err := c.ListAccountEntries(ctx, "~fx")
+		info, err := c.LedgerInfo(ctx)
+		if err != nil {
+			return otcFXDashLoadedMsg{accounts: accounts, err: err}
+		}
+		rates, err := c.EffectiveFXRates(ctx, time.Time{})
+		if err != nil {
+			return otcFXDashLoadedMsg{accounts: accounts, err: err}
+		}
+
+		fxEntries, err := c.AllAccountEntries(ctx, "~fx")
 		if err != nil {
 			return otcFXDashLoadedMsg{accounts: accounts, err: err}
 		}
 
-		fxTxns, _ := c.ListTransactions(ctx, "~fx")
+		var fxTxns []ledger.Transaction
+		if page, err := c.ListTransactions(ctx, client.TxnQuery{AccountID: "~fx", Limit: 5}); err == nil {
+			fxTxns = page.Transactions
+		}
 
 		balances := make(map[string]*client.BalanceResponse, len(accounts))
 		for _, a := range accounts {
 			if a.Currency == "*" {
 				continue
 			}
-			bal, err := c.GetAccountBalance(ctx, a.ID)
+			bal, err := c.GetAccountBalance(ctx, a.ID, time.Time{})
 			if err == nil {
 				balances[a.ID] = bal
 			}
@@ -150,6 +168,8 @@ func (m *otcFXModel) init(c *client.Client) tea.Cmd {
 			fxTxns:    fxTxns,
 			accounts:  accounts,
 			balances:  balances,
+			reporting: info.ReportingCurrency,
+			rates:     rates,
 		}
 	}
 }
@@ -200,31 +220,52 @@ func (m *otcFXModel) computePositions(accounts []ledger.Account, balances map[st
 	sort.Strings(currencies)
 
 	m.positions = make([]currencyPosition, 0, len(currencies))
-	m.totalGEL = 0
+	m.totalEquiv = 0
+	rc := m.reporting
 	for _, ccy := range currencies {
 		b := byCurrency[ccy]
-		gelEquiv := ledger.ToGEL(b.net, ccy)
-		m.totalGEL += gelEquiv
-		m.positions = append(m.positions, currencyPosition{
+		p := currencyPosition{
 			Currency:    ccy,
 			Assets:      b.assets,
 			Liabilities: b.liabilities,
 			Equity:      b.equity,
 			Net:         b.net,
-			GELEquiv:    gelEquiv,
-		})
+		}
+		if equiv, err := m.rates.Convert(b.net, ccy, rc); err == nil {
+			p.Equiv = equiv
+			m.totalEquiv += equiv
+		} else {
+			p.NoRate = true
+		}
+		m.positions = append(m.positions, p)
 	}
 }
 
+// equivString formats the position's reporting-currency equivalent.
+func (p currencyPosition) equivString(rc string) string {
+	if p.NoRate {
+		return noRate
+	}
+	return ledger.FormatAmount(p.Equiv, rc)
+}
+
 func (m *otcFXModel) sourceCur() string { return m.srcCurrency }
 func (m *otcFXModel) destCur() string   { return m.destCurrency }
 
+func currencyExponent(code string) int {
+	c, _ := ledger.LookupCurrency(code)
+	return c.Exponent
+}
+
+// midRate is the cross rate of the source currency in the destination
+// currency, via the reporting-currency rates effective when the dashboard
+// was loaded.
 func (m *otcFXModel) midRate() float64 {
 	if m.srcCurrency == "" || m.destCurrency == "" {
 		return 0
 	}
-	src := ledger.FXRatesToGEL[m.srcCurrency]
-	dst := ledger.FXRatesToGEL[m.destCurrency]
+	src := m.rates.Rate(m.srcCurrency)
+	dst := m.rates.Rate(m.destCurrency)
 	if dst == 0 {
 		return 0
 	}
@@ -249,8 +290,8 @@ func (m *otcFXModel) spreadFromRate(rate float64) float64 {
 func (m *otcFXModel) customerGives() int64 {
 	srcCur := m.sourceCur()
 	dstCur := m.destCur()
-	srcMul := math.Pow10(ledger.Currencies[srcCur].Exponent)
-	dstMul := math.Pow10(ledger.Currencies[dstCur].Exponent)
+	srcMul := math.Pow10(currencyExponent(srcCur))
+	dstMul := math.Pow10(currencyExponent(dstCur))
 	rate := m.effectiveRate()
 
 	if m.intentionVerb == "give" {
@@ -265,8 +306,8 @@ func (m *otcFXModel) customerGives() int64 {
 func (m *otcFXModel) customerGets() int64 {
 	srcCur := m.sourceCur()
 	dstCur := m.destCur()
-	srcMul := math.Pow10(ledger.Currencies[srcCur].Exponent)
-	dstMul := math.Pow10(ledger.Currencies[dstCur].Exponent)
+	srcMul := math.Pow10(currencyExponent(srcCur))
+	dstMul := math.Pow10(currencyExponent(dstCur))
 	rate := m.effectiveRate()
 
 	if m.intentionVerb == "get" {
@@ -275,8 +316,18 @@ func (m *otcFXModel) customerGets() int64 {
 	return int64(math.Round(float64(m.intentionAmt) * rate * dstMul / srcMul))
 }
 
-func (m *otcFXModel) bankPnLGEL() int64 {
-	return ledger.ToGEL(m.customerGives(), m.sourceCur()) - ledger.ToGEL(m.customerGets(), m.destCur())
+// bankPnL formats the deal's margin in the reporting currency at mid rates.
+func (m *otcFXModel) bankPnL() string {
+	rc := m.reporting
+	gives, err := m.rates.Convert(m.customerGives(), m.sourceCur(), rc)
+	if err != nil {
+		return noRate
+	}
+	gets, err := m.rates.Convert(m.customerGets(), m.destCur(), rc)
+	if err != nil {
+		return noRate
+	}
+	return ledger.FormatAmount(gives-gets, rc) + " " + rc
 }
 
 func (m otcFXModel) update(msg tea.Msg, c *client.Client) (otcFXModel, tea.Cmd) {
@@ -288,6 +339,8 @@ func (m otcFXModel) update(msg tea.Msg, c *client.Client) (otcFXModel, tea.Cmd)
 			return m, nil
 		}
 		m.accounts = msg.accounts
+		m.reporting = msg.reporting
+		m.rates = msg.rates
 		m.fxEntries = msg.fxEntries
 		if len(msg.fxTxns) > 5 {
 			m.fxTxns = msg.fxTxns[:5]
@@ -304,6 +357,9 @@ func (m otcFXModel) update(msg tea.Msg, c *client.Client) (otcFXModel, tea.Cmd)
 		}
 		m.done = true
 		m.statusMsg = fmt.Sprintf("FX trade %s executed!", msg.txn.ID[:8])
+		if msg.txn.ApprovalStatus == ledger.ApprovalPending {
+			m.statusMsg = fmt.Sprintf("FX trade %s is awaiting approval", msg.txn.ID[:8])
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -653,29 +709,33 @@ func (m *otcFXModel) viewFXPnL(b *strings.Builder) {
 
 	b.WriteString(headerStyle.Render("  FX Book PnL"))
 	b.WriteString("\n")
-	b.WriteString(dimStyle.Render(fmt.Sprintf("  %-6s %18s %18s", "CCY", "NET POSITION", "GEL EQUIV")))
+	rc := m.reporting
+	b.WriteString(dimStyle.Render(fmt.Sprintf("  %-6s %18s %18s", "CCY", "NET POSITION", rc+" EQUIV")))
 	b.WriteString("\n")
 
-	var totalGEL int64
+	var total int64
 	for _, ccy := range currencies {
 		bal := byCurrency[ccy]
-		gelEquiv := ledger.ToGEL(bal, ccy)
-		totalGEL += gelEquiv
-		b.WriteString(fmt.Sprintf("  %-6s %14s %-3s %14s GEL\n",
+		equiv := fmt.Sprintf("%18s", noRate)
+		if amt, err := m.rates.Convert(bal, ccy, rc); err == nil {
+			total += amt
+			equiv = fmt.Sprintf("%14s %s", ledger.FormatAmount(amt, rc), rc)
+		}
+		b.WriteString(fmt.Sprintf("  %-6s %14s %-3s %s\n",
 			ccy,
 			ledger.FormatAmount(bal, ccy), ccy,
-			ledger.FormatAmount(gelEquiv, ledger.ReportingCurrency)))
+			equiv))
 	}
 	b.WriteString(fmt.Sprintf("  %s\n", strings.Repeat("─", 44)))
 
 	label := "Net FX PnL"
-	gelStr := ledger.FormatAmount(totalGEL, ledger.ReportingCurrency) + " GEL"
-	if totalGEL > 0 {
-		b.WriteString(successStyle.Render(fmt.Sprintf("  %-24s %14s", label, gelStr)))
-	} else if totalGEL < 0 {
-		b.WriteString(errorStyle.Render(fmt.Sprintf("  %-24s %14s", label, gelStr)))
+	totalStr := ledger.FormatAmount(total, rc) + " " + rc
+	if total > 0 {
+		b.WriteString(successStyle.Render(fmt.Sprintf("  %-24s %14s", label, totalStr)))
+	} else if total < 0 {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("  %-24s %14s", label, totalStr)))
 	} else {
-		b.WriteString(fmt.Sprintf("  %-24s %14s", label, gelStr))
+		b.WriteString(fmt.Sprintf("  %-24s %14s", label, totalStr))
 	}
 	b.WriteString("\n")
 }
@@ -715,7 +775,9 @@ func (m *otcFXModel) viewPositions(b *strings.Builder) {
 	liabW := len("LIABS (Short)")
 	eqW := len("EQUITY")
 	netW := len("NET")
-	gelW := len("GEL EQUIV")
+	rc := m.reporting
+	equivHeader := rc + " EQUIV"
+	equivW := len(equivHeader)
 
 	for _, p := range m.positions {
 		if l := len(ledger.FormatAmount(p.Assets, p.Currency)); l > assetsW {
@@ -730,15 +792,15 @@ func (m *otcFXModel) viewPositions(b *strings.Builder) {
 		if l := len(ledger.FormatAmount(p.Net, p.Currency)); l > netW {
 			netW = l
 		}
-		if l := len(ledger.FormatAmount(p.GELEquiv, ledger.ReportingCurrency)); l > gelW {
-			gelW = l
+		if l := len(p.equivString(rc)); l > equivW {
+			equivW = l
 		}
 	}
 	assetsW += 2
 	liabW += 2
 	eqW += 2
 	netW += 2
-	gelW += 2
+	equivW += 2
 
 	header := fmt.Sprintf("  %-*s%*s%*s%*s%*s%*s",
 		ccyW, "CCY",
@@ -746,7 +808,7 @@ func (m *otcFXModel) viewPositions(b *strings.Builder) {
 		liabW, "LIABS (Short)",
 		eqW, "EQUITY",
 		netW, "NET",
-		gelW, "GEL EQUIV")
+		equivW, equivHeader)
 	b.WriteString(dimStyle.Render(header))
 	b.WriteString("\n")
 
@@ -755,7 +817,7 @@ func (m *otcFXModel) viewPositions(b *strings.Builder) {
 		liab := ledger.FormatAmount(p.Liabilities, p.Currency)
 		eq := ledger.FormatAmount(p.Equity, p.Currency)
 		net := ledger.FormatAmount(p.Net, p.Currency)
-		gel := ledger.FormatAmount(p.GELEquiv, ledger.ReportingCurrency)
+		equiv := p.equivString(rc)
 
 		line := fmt.Sprintf("  %-*s%*s%*s%*s%*s%*s",
 			ccyW, p.Currency,
@@ -763,7 +825,7 @@ func (m *otcFXModel) viewPositions(b *strings.Builder) {
 			liabW, liab,
 			eqW, eq,
 			netW, net,
-			gelW, gel)
+			equivW, equiv)
 
 		if p.Net > 0 {
 			b.WriteString(debitStyle.Render(line))
@@ -777,15 +839,15 @@ func (m *otcFXModel) viewPositions(b *strings.Builder) {
 
 	totalW := ccyW + assetsW + liabW + eqW + netW
 	totalLabel := "Total Open Position"
-	totalGEL := ledger.FormatAmount(m.totalGEL, ledger.ReportingCurrency) + " GEL"
+	total := ledger.FormatAmount(m.totalEquiv, rc) + " " + rc
 
-	b.WriteString(fmt.Sprintf("  %s\n", strings.Repeat("─", totalW+gelW)))
-	if m.totalGEL > 0 {
-		b.WriteString(debitStyle.Render(fmt.Sprintf("  %-*s%*s", totalW-2, totalLabel, gelW, totalGEL)))
-	} else if m.totalGEL < 0 {
-		b.WriteString(creditStyle.Render(fmt.Sprintf("  %-*s%*s", totalW-2, totalLabel, gelW, totalGEL)))
+	b.WriteString(fmt.Sprintf("  %s\n", strings.Repeat("─", totalW+equivW)))
+	if m.totalEquiv > 0 {
+		b.WriteString(debitStyle.Render(fmt.Sprintf("  %-*s%*s", totalW-2, totalLabel, equivW, total)))
+	} else if m.totalEquiv < 0 {
+		b.WriteString(creditStyle.Render(fmt.Sprintf("  %-*s%*s", totalW-2, totalLabel, equivW, total)))
 	} else {
-		b.WriteString(fmt.Sprintf("  %-*s%*s", totalW-2, totalLabel, gelW, totalGEL))
+		b.WriteString(fmt.Sprintf("  %-*s%*s", totalW-2, totalLabel, equivW, total))
 	}
 	b.WriteString("\n")
 }
@@ -818,7 +880,7 @@ func (m *otcFXModel) viewRate(b *strings.Builder) {
 	rate := m.effectiveRate()
 	gives := m.customerGives()
 	gets := m.customerGets()
-	pnl := m.bankPnLGEL()
+	pnl := m.bankPnL()
 	srcCur := m.sourceCur()
 	dstCur := m.destCur()
 
@@ -835,7 +897,7 @@ func (m *otcFXModel) viewRate(b *strings.Builder) {
 	summary.WriteString("\n")
 	summary.WriteString(fmt.Sprintf("Customer gives:  %s %s\n", ledger.FormatAmount(gives, srcCur), srcCur))
 	summary.WriteString(fmt.Sprintf("Customer gets:   %s %s\n", ledger.FormatAmount(gets, dstCur), dstCur))
-	summary.WriteString(fmt.Sprintf("Bank P&L:        %s GEL", ledger.FormatAmount(pnl, "GEL")))
+	summary.WriteString(fmt.Sprintf("Bank P&L:        %s", pnl))
 
 	b.WriteString(boxStyle.Render(summary.String()))
 	b.WriteString("\n\n")
@@ -853,7 +915,7 @@ func (m *otcFXModel) viewConfirm(b *strings.Builder) {
 	mid := m.midRate()
 	gives := m.customerGives()
 	gets := m.customerGets()
-	pnl := m.bankPnLGEL()
+	pnl := m.bankPnL()
 	srcCur := m.sourceCur()
 	dstCur := m.destCur()
 	bps := m.spreadFromRate(rate)
@@ -865,7 +927,7 @@ func (m *otcFXModel) viewConfirm(b *strings.Builder) {
 	summary.WriteString(fmt.Sprintf("Deal rate:       %.4f (mid %.4f %+.0f bps)\n", rate, mid, bps))
 	summary.WriteString(fmt.Sprintf("Customer gives:  %s %s\n", ledger.FormatAmount(gives, srcCur), srcCur))
 	summary.WriteString(fmt.Sprintf("Customer gets:   %s %s\n", ledger.FormatAmount(gets, dstCur), dstCur))
-	summary.WriteString(fmt.Sprintf("Bank P&L:        %s GEL\n", ledger.FormatAmount(pnl, "GEL")))
+	summary.WriteString(fmt.Sprintf("Bank P&L:        %s\n", pnl))
 	summary.WriteString("\n")
 	summary.WriteString(fmt.Sprintf("Source acct:     %s (%s)\n", m.srcAcctID, srcCur))
 	summary.WriteString(fmt.Sprintf("Dest acct:       %s (%s)", m.destAcctID, dstCur))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -27,7 +28,7 @@ type ratiosModel struct {
 func (m *ratiosModel) init(c *client.Client) tea.Cmd {
 	m.loading = true
 	return func() tea.Msg {
-		ratios, err := c.RegulatoryRatios(context.Background())
+		ratios, err := c.RegulatoryRatios(context.Background(), time.Time{})
 		return ratiosLoadedMsg{ratios: ratios, err: err}
 	}
 }
@@ -113,15 +114,15 @@ func (m *ratiosModel) view() string {
 
 	renderRatio("Capital Adequacy Ratio (CAR)",
 		r.CapitalAdequacy, 12, 8, 50,
-		r.Equity, r.TotalAssets, "Equity", "Total Assets", "USD")
+		r.Equity, r.TotalAssets, "Equity", "Total Assets", r.ReportingCurrency)
 
 	renderRatio("Leverage Ratio",
 		r.LeverageRatio, 5, 3, 50,
-		r.Equity, r.TotalAssets, "Equity", "Total Assets", "USD")
+		r.Equity, r.TotalAssets, "Equity", "Total Assets", r.ReportingCurrency)
 
 	renderRatio("Reserve Ratio",
 		r.ReserveRatio, 20, 10, 100,
-		r.Reserves, r.CustomerDeposits, "Reserves (1060)", "Customer Deposits (2020)", "USD")
+		r.Reserves, r.CustomerDeposits, "Reserves (1060)", "Customer Deposits (2020)", r.ReportingCurrency)
 
 	// Summary
 	b.WriteString(fmt.Sprintf("  %s\n", strings.Repeat("─", 52)))
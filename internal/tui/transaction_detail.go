@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/simonvc/miniledger/internal/client"
 	"github.com/simonvc/miniledger/internal/ledger"
@@ -15,11 +17,38 @@ type txnDetailLoadedMsg struct {
 	err error
 }
 
+// txnReverseRequestMsg is sent when the user submits a reversal reason.
+type txnReverseRequestMsg struct {
+	id     string
+	reason string
+}
+
+// txnReversedMsg is sent after the server posts the reversing transaction.
+type txnReversedMsg struct {
+	id  string
+	rev *ledger.Transaction
+	err error
+}
+
+// txnHoldRequestMsg asks the App to capture or void a pending transaction.
+type txnHoldRequestMsg struct {
+	id   string
+	void bool
+}
+
+// txnHoldResolvedMsg is sent after the server captures or voids the hold.
+type txnHoldResolvedMsg struct {
+	txn *ledger.Transaction
+	err error
+}
+
 type txnDetailModel struct {
-	txn     *ledger.Transaction
-	loading bool
-	err     error
-	width   int
+	txn         *ledger.Transaction
+	loading     bool
+	err         error
+	width       int
+	reversing   bool
+	reasonInput textinput.Model
 }
 
 func (m *txnDetailModel) init(c *client.Client, id string) tea.Cmd {
@@ -36,10 +65,63 @@ func (m txnDetailModel) update(msg tea.Msg) (txnDetailModel, tea.Cmd) {
 		m.loading = false
 		m.txn = msg.txn
 		m.err = msg.err
+
+	case txnReversedMsg:
+		m.reversing = false
+		if msg.err != nil {
+			m.err = msg.err
+		}
+
+	case txnHoldResolvedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+
+	case tea.KeyMsg:
+		if m.reversing {
+			switch {
+			case key.Matches(msg, keys.Enter):
+				id := m.txn.ID
+				reason := strings.TrimSpace(m.reasonInput.Value())
+				return m, func() tea.Msg {
+					return txnReverseRequestMsg{id: id, reason: reason}
+				}
+			case key.Matches(msg, keys.Escape):
+				m.reversing = false
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.reasonInput, cmd = m.reasonInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		if key.Matches(msg, keys.Reverse) && m.canReverse() {
+			m.reversing = true
+			m.reasonInput = textinput.New()
+			m.reasonInput.Placeholder = "reason (optional)"
+			m.reasonInput.CharLimit = 80
+			m.reasonInput.Focus()
+			m.err = nil
+		}
+		if m.isPending() && (key.Matches(msg, keys.Capture) || key.Matches(msg, keys.Void)) {
+			req := txnHoldRequestMsg{id: m.txn.ID, void: key.Matches(msg, keys.Void)}
+			return m, func() tea.Msg { return req }
+		}
 	}
 	return m, nil
 }
 
+// isPending reports whether the loaded transaction is an open hold.
+func (m *txnDetailModel) isPending() bool {
+	return m.txn != nil && m.txn.Status == ledger.StatusPending
+}
+
+// canReverse reports whether the loaded transaction can still be reversed.
+func (m *txnDetailModel) canReverse() bool {
+	return m.txn != nil && m.txn.Finalized && m.txn.ReversedBy == ""
+}
+
 func (m *txnDetailModel) view() string {
 	if m.loading {
 		return "Loading transaction..."
@@ -59,6 +141,19 @@ func (m *txnDetailModel) view() string {
 	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Description:"), m.txn.Description))
 	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Posted:"), m.txn.PostedAt.Format("2006-01-02 15:04:05")))
 	b.WriteString(fmt.Sprintf("%s %v\n", labelStyle.Render("Finalized:"), m.txn.Finalized))
+	if m.txn.Status != "" {
+		status := string(m.txn.Status)
+		if m.isPending() && m.txn.ExpiresAt != nil {
+			status += " until " + m.txn.ExpiresAt.Local().Format("2006-01-02 15:04")
+		}
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Status:"), status))
+	}
+	if m.txn.ReversalOf != "" {
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Reverses:"), m.txn.ReversalOf))
+	}
+	if m.txn.ReversedBy != "" {
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Reversed by:"), m.txn.ReversedBy))
+	}
 	b.WriteString("\n")
 
 	header := fmt.Sprintf("  %-4s %-14s %15s %15s %s", "TYPE", "ACCOUNT", "DEBIT", "CREDIT", "CCY")
@@ -88,6 +183,19 @@ func (m *txnDetailModel) view() string {
 		b.WriteString("\n")
 	}
 
-	b.WriteString("\n" + dimStyle.Render("  Press ESC to go back"))
+	if m.reversing {
+		b.WriteString("\n" + errorStyle.Render("  Reverse this transaction?") + " ")
+		b.WriteString(m.reasonInput.View())
+		b.WriteString("\n" + dimStyle.Render("  enter: post reversal  esc: cancel"))
+		return b.String()
+	}
+
+	if m.canReverse() {
+		b.WriteString("\n" + dimStyle.Render("  Press v to reverse, ESC to go back"))
+	} else if m.isPending() {
+		b.WriteString("\n" + dimStyle.Render("  Press c to capture, x to void, ESC to go back"))
+	} else {
+		b.WriteString("\n" + dimStyle.Render("  Press ESC to go back"))
+	}
 	return b.String()
 }
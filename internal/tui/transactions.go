@@ -11,25 +11,48 @@ import (
 	"github.com/simonvc/miniledger/internal/ledger"
 )
 
+// txnPageSize is how many transactions the list fetches at a time.
+const txnPageSize = 100
+
 type txnsLoadedMsg struct {
-	txns []ledger.Transaction
-	err  error
+	page   *ledger.TransactionPage
+	append bool // a follow-on page rather than a fresh load
+	err    error
 }
 
+// txnsMoreMsg asks the App to fetch the next page once the cursor reaches
+// the last loaded transaction.
+type txnsMoreMsg struct{}
+
 type txnListModel struct {
-	txns    []ledger.Transaction
-	cursor  int
-	loading bool
-	err     error
-	width   int
-	height  int
+	txns        []ledger.Transaction
+	nextCursor  string
+	loadingMore bool
+	cursor      int
+	loading     bool
+	err         error
+	width       int
+	height      int
 }
 
 func (m *txnListModel) init(c *client.Client) tea.Cmd {
 	m.loading = true
 	return func() tea.Msg {
-		txns, err := c.ListTransactions(context.Background(), "")
-		return txnsLoadedMsg{txns: txns, err: err}
+		page, err := c.ListTransactions(context.Background(), client.TxnQuery{Limit: txnPageSize})
+		return txnsLoadedMsg{page: page, err: err}
+	}
+}
+
+// loadMore fetches the page after the last loaded transaction.
+func (m *txnListModel) loadMore(c *client.Client) tea.Cmd {
+	if m.nextCursor == "" || m.loadingMore {
+		return nil
+	}
+	m.loadingMore = true
+	cursor := m.nextCursor
+	return func() tea.Msg {
+		page, err := c.ListTransactions(context.Background(), client.TxnQuery{Limit: txnPageSize, Cursor: cursor})
+		return txnsLoadedMsg{page: page, append: true, err: err}
 	}
 }
 
@@ -37,8 +60,20 @@ func (m txnListModel) update(msg tea.Msg) (txnListModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case txnsLoadedMsg:
 		m.loading = false
-		m.txns = msg.txns
+		m.loadingMore = false
 		m.err = msg.err
+		if msg.err != nil {
+			break
+		}
+		if msg.append {
+			m.txns = append(m.txns, msg.page.Transactions...)
+		} else {
+			m.txns = msg.page.Transactions
+			if m.cursor >= len(m.txns) {
+				m.cursor = max(len(m.txns)-1, 0)
+			}
+		}
+		m.nextCursor = msg.page.NextCursor
 
 	case tea.KeyMsg:
 		switch {
@@ -50,6 +85,9 @@ func (m txnListModel) update(msg tea.Msg) (txnListModel, tea.Cmd) {
 			if m.cursor < len(m.txns)-1 {
 				m.cursor++
 			}
+			if m.cursor == len(m.txns)-1 && m.nextCursor != "" && !m.loadingMore {
+				return m, func() tea.Msg { return txnsMoreMsg{} }
+			}
 		}
 	}
 	return m, nil
@@ -118,5 +156,10 @@ func (m *txnListModel) view() string {
 	}
 
 	b.WriteString(fmt.Sprintf("\n  %d transactions", len(m.txns)))
+	if m.loadingMore {
+		b.WriteString(dimStyle.Render("  (loading more...)"))
+	} else if m.nextCursor != "" {
+		b.WriteString(dimStyle.Render("  (more below)"))
+	}
 	return b.String()
 }
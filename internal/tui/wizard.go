@@ -55,7 +55,7 @@ type wizardModel struct {
 	width     int
 }
 
-func newWizard() wizardModel {
+func newWizard(currencies []string) wizardModel {
 	codeInput := textinput.New()
 	codeInput.Placeholder = "e.g. 1060"
 	codeInput.CharLimit = 4
@@ -73,7 +73,7 @@ func newWizard() wizardModel {
 		code:       codeInput,
 		id:         idInput,
 		name:       nameInput,
-		curOptions: ledger.CurrencyCodes(),
+		curOptions: currencies,
 	}
 }
 
@@ -501,7 +501,7 @@ func (m *wizardModel) view() string {
 
 		for i := start; i < end; i++ {
 			code := m.curOptions[i]
-			cur := ledger.Currencies[code]
+			cur, _ := ledger.LookupCurrency(code)
 			label := fmt.Sprintf("%s - %s", code, cur.Name)
 			if i == m.currency {
 				b.WriteString(selectedStyle.Render("  > "+label) + "\n")